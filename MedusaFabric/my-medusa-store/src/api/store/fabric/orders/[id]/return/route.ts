@@ -29,7 +29,7 @@ export const POST = async (req: MedusaRequest, res: MedusaResponse) => {
     // Chaincode trả về: "đã quá 7 ngày..."
     let errorMessage = error.message || "Lỗi hệ thống.";
     
-    if (errorMessage.includes("đã quá")) {
+    if (errorMessage.includes("WINDOW_EXPIRED") || errorMessage.includes("đã quá")) {
         return res.status(400).json({ error: "Đã hết thời hạn đổi trả (5 phút demo)." });
     }
     
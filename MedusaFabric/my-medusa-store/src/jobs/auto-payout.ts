@@ -65,9 +65,13 @@ export default async function autoPayoutJob(
         if (diffMinutes >= 5) {
           console.log(`>>> Executing PAYOUT for: ${cand.blockchain_id}`);
 
-          await fabricService.payoutToSeller(cand.blockchain_id);
+          const result = await fabricService.payoutToSeller(cand.blockchain_id);
 
-          console.log(`[CronJob] Payout SUCCESS: ${cand.blockchain_id}`);
+          if (result.pending) {
+            console.log(`[CronJob] Payout PENDING approval ${result.approvalID}: ${cand.blockchain_id}`);
+          } else {
+            console.log(`[CronJob] Payout SUCCESS: ${cand.blockchain_id}`);
+          }
         }
 
       } catch (err: any) {
@@ -8,8 +8,20 @@ const yaml = require('js-yaml');
 
 const FABRIC_HOST = process.env.FABRIC_HOST || '192.168.40.11'; 
 
-async function enrollShipperIdentity(enrollmentID, companyCodeAttr) {
+// Vai trò trong chứng chỉ để chaincode phân quyền (permissions.go). Mặc định là vai trò thấp
+// nhất đủ cho nghiệp vụ của Shipper; quyền admin phải được truyền rõ ràng (role = 'admin').
+const USER_ROLES = ['admin', 'finance', 'support', 'warehouse'];
+const DEFAULT_SHIPPER_ROLE = 'warehouse';
+
+// role: một hoặc nhiều vai trò ghép bằng dấu phẩy (VD: 'support,warehouse')
+async function enrollShipperIdentity(enrollmentID, companyCodeAttr, role = DEFAULT_SHIPPER_ROLE) {
     try {
+        const roles = String(role).split(',').map(r => r.trim()).filter(Boolean);
+        const unknown = roles.filter(r => !USER_ROLES.includes(r));
+        if (roles.length === 0 || unknown.length > 0) {
+            throw new Error(`Invalid role "${role}". Allowed: ${USER_ROLES.join(', ')}`);
+        }
+
         const ccpPath = path.resolve(process.cwd(), 'connection-profile.yaml');
         if (!fs.existsSync(ccpPath)) {
             throw new Error(`Cannot find connection profile at: ${ccpPath}`);
@@ -79,7 +91,10 @@ async function enrollShipperIdentity(enrollmentID, companyCodeAttr) {
             affiliation: '',
             enrollmentID: enrollmentID,
             role: 'client',
-            attrs: [{ name: 'companyCode', value: companyCodeAttr, ecert: true }]
+            attrs: [
+                { name: 'companyCode', value: companyCodeAttr, ecert: true },
+                { name: 'role', value: roles.join(','), ecert: true }
+            ]
         }, adminUser);
         
         console.log(`Secret generated for "${enrollmentID}"`);
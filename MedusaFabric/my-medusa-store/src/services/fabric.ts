@@ -139,6 +139,23 @@ function hybridDecrypt(blobString, rsaPrivateKey) {
     }
 }
 
+// Mã yêu cầu trả hàng mặc định: luồng trả hàng của cửa hàng trả toàn bộ đơn con một lần
+const DEFAULT_RETURN_ID = 'R1';
+
+// Dòng hàng cho chaincode (CreateOrder.linesJSON): [{lineID, sku, quantity, unitPrice}]
+// Chaincode tự tính TotalAmount = tổng quantity * unitPrice
+function toOrderLinesJSON(productLines) {
+    if (!Array.isArray(productLines) || productLines.length === 0) {
+        return '';
+    }
+    return JSON.stringify(productLines.map((line, index) => ({
+        lineID: line.line_id || `L${index + 1}`,
+        sku: line.sku || line.product_name || line.name || '',
+        quantity: Number(line.quantity) || 0,
+        unitPrice: Math.round(Number(line.unit_price) || 0)
+    })));
+}
+
 class FabricService {
     constructor(container) {
         this.container = container;
@@ -186,7 +203,7 @@ class FabricService {
     }
 
     // --- Create Order ---
-    async createOrder(data, sellerCompanyId, baseAmount) {
+    async createOrder(data, sellerCompanyId = data.sellerCompanyID) {
         const { contract } = await this._getContract('seller');
 
         const sellerPayload = JSON.stringify({
@@ -219,15 +236,15 @@ class FabricService {
             // 2. KHÔNG DÙNG setEndorsingPeers thủ công (Tránh lỗi peer0.tax.com)
             // SDK sẽ tự dùng Discovery để tìm Peer của ECommerce, Seller hoặc Shipper
 
-            // 3. Thực hiện gửi giao dịch (Đảm bảo đủ 7 tham số theo Chaincode v7.0)
+            // 3. Thực hiện gửi giao dịch (đủ 7 tham số của CreateOrder)
             await transaction.submit(
                 data.orderID,           // 1. string
                 data.paymentMethod,     // 2. string
                 data.shipperCompanyID,  // 3. string
                 encryptedSellerBlob,    // 4. string
                 encryptedShipperBlob,   // 5. string
-                sellerCompanyId,        // 6. string
-                data.amount_total?.toString() // 7. baseAmount (string đại diện int64)
+                sellerCompanyId || '',  // 6. string
+                toOrderLinesJSON(data.product_lines) // 7. linesJSON (tổng tiền do chaincode tính)
             );
 
             console.log(`[Fabric] Success: ${data.orderID}`);
@@ -453,11 +470,13 @@ class FabricService {
     }
 
     // Thanh toán cho Seller (Payout)
+    // Số tiền vượt ngưỡng duyệt: chaincode chỉ tạo đề xuất, status = 'PENDING' kèm approvalID
     async payoutToSeller(orderId) {
         const { contract } = await this._getContract('admin');
         console.log(`[Fabric] Admin executing payout: ${orderId}`);
-        await contract.submitTransaction('PayoutToSeller', orderId);
-        return { success: true };
+        const resultBytes = await contract.submitTransaction('PayoutToSeller', orderId);
+        const result = JSON.parse(resultBytes.toString());
+        return { success: true, pending: result.status === 'PENDING', ...result };
     }
 
     // Hủy đơn hàng (Admin only, chỉ khi status = CREATED hoặc PAID)
@@ -471,23 +490,25 @@ class FabricService {
     // 4. RETURN FLOW (Trả hàng)
     // =========================================================================
 
-    // Khách yêu cầu trả hàng
-    async requestReturn(orderId) {
+    // Khách yêu cầu trả hàng. returnLines rỗng = trả toàn bộ đơn; trả một phần: [{lineID, quantity}]
+    async requestReturn(orderId, returnId = DEFAULT_RETURN_ID, returnLines = null) {
         const { contract } = await this._getContract('admin');
-        await contract.submitTransaction('RequestReturn', orderId); return { success: true };
+        const returnLinesJSON = returnLines && returnLines.length > 0 ? JSON.stringify(returnLines) : '';
+        await contract.submitTransaction('RequestReturn', orderId, returnId, returnLinesJSON);
+        return { success: true, returnId };
     }
 
-    async shipReturn(orderId, shipperCompanyID) {
+    async shipReturn(orderId, shipperCompanyID, returnId = DEFAULT_RETURN_ID) {
         const { contract } = await this._getContract('shipper');
-        console.log(`[Fabric] Shipper shipping return: ${orderId} for Company ID: ${shipperCompanyID}`);
-        await contract.submitTransaction('ShipReturn', orderId, shipperCompanyID);
+        console.log(`[Fabric] Shipper shipping return: ${orderId}/${returnId} for Company ID: ${shipperCompanyID}`);
+        await contract.submitTransaction('ShipReturn', orderId, returnId, shipperCompanyID);
         return { success: true };
     }
 
-    async confirmReturnReceived(orderId, sellerCompanyID) {
+    async confirmReturnReceived(orderId, sellerCompanyID, returnId = DEFAULT_RETURN_ID) {
         const { contract } = await this._getContract('seller');
-        console.log(`[Fabric] Seller confirming return received: ${orderId} for Company ID: ${sellerCompanyID}`);
-        await contract.submitTransaction('ConfirmReturnReceived', orderId, sellerCompanyID);
+        console.log(`[Fabric] Seller confirming return received: ${orderId}/${returnId} for Company ID: ${sellerCompanyID}`);
+        await contract.submitTransaction('ConfirmReturnReceived', orderId, returnId, sellerCompanyID);
         return { success: true };
     }
 
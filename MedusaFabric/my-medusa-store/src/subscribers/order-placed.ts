@@ -261,11 +261,7 @@ export default async function orderPlacedHandler({
             try {
                 console.log('payload', payload);
                 console.log(`[Submit] ${splitOrderID} -> Shipper: ${shipperCode}, HasShipperKey: ${!!shipperPublicKey}`);
-                const txId = await fabricService.createOrder(
-                    payload,
-                    sellerID,
-                    payload.amount_total 
-                ); console.log(`[${splitOrderID}] Ghi thành công! TX: ${txId}`);
+                const txId = await fabricService.createOrder(payload, sellerID); console.log(`[${splitOrderID}] Ghi thành công! TX: ${txId}`);
                 // ===================
                 // BƯỚC 6: GỌI TAX API + LƯU VÀO MEDUSA METADATA
                 // ===================
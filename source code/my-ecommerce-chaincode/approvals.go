@@ -0,0 +1,292 @@
+// my-ecommerce-chaincode/approvals.go
+
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Duyệt hai người (maker-checker) cho thanh toán Seller và hoàn tiền có số tiền lớn hơn
+// PolicyConfig.ApprovalThreshold: giao dịch đầu tiên chỉ tạo đề xuất PENDING, nghiệp vụ
+// chỉ được thực hiện khi một định danh khác có vai trò approver gọi ApproveAction.
+// Đề xuất lưu theo khóa Approval~orderID~approvalID (approvalID = TxID của giao dịch đề xuất).
+const approvalObjectType = "Approval"
+
+// Các nghiệp vụ cần duyệt (trùng tên giao dịch gốc)
+const (
+	approvalPayout = "PayoutToSeller"
+	approvalRefund = "RefundReturn"
+)
+
+// ApprovalStatus là trạng thái của một đề xuất chờ duyệt
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "PENDING"
+	ApprovalExecuted ApprovalStatus = "EXECUTED"
+	ApprovalExpired  ApprovalStatus = "EXPIRED"
+)
+
+// Approval là một đề xuất thanh toán/hoàn tiền chờ người thứ hai duyệt
+type Approval struct {
+	DocType      string         `json:"docType"`
+	ApprovalID   string         `json:"approvalID"`
+	Action       string         `json:"action"`
+	OrderID      string         `json:"orderID"`
+	ReturnID     string         `json:"returnID,omitempty" metadata:",optional"`
+	Amount       int64          `json:"amount"`
+	Status       ApprovalStatus `json:"status"`
+	ProposerOrg  string         `json:"proposerOrg"`
+	ProposerID   string         `json:"proposerID"` // Định danh (subject + issuer) của người đề xuất
+	ApproverOrg  string         `json:"approverOrg,omitempty" metadata:",optional"`
+	ApproverID   string         `json:"approverID,omitempty" metadata:",optional"`
+	ExecutedTxID string         `json:"executedTxID,omitempty" metadata:",optional"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	ExpiresAt    time.Time      `json:"expiresAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+}
+
+// SettlementResult là kết quả của PayoutToSeller / RefundReturn: EXECUTED khi đã thanh toán/hoàn
+// tiền ngay, PENDING khi số tiền vượt ngưỡng và chỉ một đề xuất ApprovalID được tạo (chờ ApproveAction,
+// xem hạn duyệt qua GetApprovals)
+type SettlementResult struct {
+	Action     string         `json:"action"`
+	OrderID    string         `json:"orderID"`
+	ReturnID   string         `json:"returnID,omitempty" metadata:",optional"`
+	Amount     int64          `json:"amount"`
+	Status     ApprovalStatus `json:"status"`
+	ApprovalID string         `json:"approvalID,omitempty" metadata:",optional"`
+}
+
+// newSettlementResult tạo kết quả cho nghiệp vụ action; approval khác nil nghĩa là đang chờ duyệt
+func newSettlementResult(action string, orderID string, returnID string, amount int64, approval *Approval) *SettlementResult {
+	result := &SettlementResult{Action: action, OrderID: orderID, ReturnID: returnID, Amount: amount, Status: ApprovalExecuted}
+	if approval != nil {
+		result.Status = ApprovalPending
+		result.ApprovalID = approval.ApprovalID
+	}
+	return result
+}
+
+// getCallerID trả về định danh duy nhất của người gọi trong tổ chức
+func getCallerID(ctx contractapi.TransactionContextInterface) (string, error) {
+	id, err := ctx.GetClientIdentity().GetID()
+	if err != nil {
+		return "", newError(ctx, ErrIdentity, errorDetails{"cause": err.Error()})
+	}
+	return id, nil
+}
+
+// getApproval: Đọc một đề xuất theo đơn hàng và approvalID
+func getApproval(ctx contractapi.TransactionContextInterface, orderID string, approvalID string) (*Approval, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(approvalObjectType, []string{orderID, approvalID})
+	if err != nil {
+		return nil, errLedger(ctx, "CreateCompositeKey", err)
+	}
+	approvalJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, errLedger(ctx, "GetState", err)
+	}
+	if approvalJSON == nil {
+		return nil, newError(ctx, ErrApprovalNotFound, errorDetails{"orderID": orderID, "approvalID": approvalID})
+	}
+
+	var approval Approval
+	if err := json.Unmarshal(approvalJSON, &approval); err != nil {
+		return nil, errLedger(ctx, "unmarshal", err)
+	}
+	return &approval, nil
+}
+
+// saveApproval: Lưu đề xuất vào sổ cái
+func saveApproval(ctx contractapi.TransactionContextInterface, approval *Approval) error {
+	key, err := ctx.GetStub().CreateCompositeKey(approvalObjectType, []string{approval.OrderID, approval.ApprovalID})
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	approvalJSON, err := json.Marshal(approval)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	return ctx.GetStub().PutState(key, approvalJSON)
+}
+
+// getApprovals: Tất cả đề xuất của một đơn hàng
+func getApprovals(ctx contractapi.TransactionContextInterface, orderID string) ([]*Approval, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(approvalObjectType, []string{orderID})
+	if err != nil {
+		return nil, errLedger(ctx, "GetStateByPartialCompositeKey", err)
+	}
+	defer iterator.Close()
+
+	approvals := []*Approval{}
+	for iterator.HasNext() {
+		item, err := iterator.Next()
+		if err != nil {
+			return nil, errLedger(ctx, "iterator.Next", err)
+		}
+		var approval Approval
+		if err := json.Unmarshal(item.Value, &approval); err != nil {
+			return nil, errLedger(ctx, "unmarshal", err)
+		}
+		approvals = append(approvals, &approval)
+	}
+	return approvals, nil
+}
+
+// requireApproval: Trả về đề xuất PENDING vừa tạo nếu số tiền cần duyệt - khi đó giao dịch gọi
+// không được thực hiện nghiệp vụ; nil nếu không cần duyệt. Đề xuất cũ đã hết hạn được đánh dấu EXPIRED.
+// Đơn đang bị đóng băng không nhận đề xuất mới.
+func requireApproval(ctx contractapi.TransactionContextInterface, action string, order *Order, returnID string, amount int64, txTime time.Time) (*Approval, error) {
+	config, err := getPolicyConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if config.ApprovalThreshold == 0 || amount <= config.ApprovalThreshold {
+		return nil, nil
+	}
+	if err := checkOrderNotFrozen(ctx, order); err != nil {
+		return nil, err
+	}
+
+	orderID := order.OrderID
+	approvals, err := getApprovals(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, approval := range approvals {
+		if approval.Status != ApprovalPending || approval.Action != action || approval.ReturnID != returnID {
+			continue
+		}
+		if txTime.Before(approval.ExpiresAt) {
+			return nil, newError(ctx, ErrApprovalPending, errorDetails{
+				"action": action, "orderID": orderID, "approvalID": approval.ApprovalID, "expiresAt": approval.ExpiresAt,
+			})
+		}
+		approval.Status = ApprovalExpired
+		approval.UpdatedAt = txTime
+		if err := saveApproval(ctx, approval); err != nil {
+			return nil, err
+		}
+	}
+
+	actorOrg, err := getActorOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proposerID, err := getCallerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	approval := &Approval{
+		DocType:     approvalObjectType,
+		ApprovalID:  ctx.GetStub().GetTxID(),
+		Action:      action,
+		OrderID:     orderID,
+		ReturnID:    returnID,
+		Amount:      amount,
+		Status:      ApprovalPending,
+		ProposerOrg: actorOrg,
+		ProposerID:  proposerID,
+		CreatedAt:   txTime,
+		ExpiresAt:   txTime.Add(time.Duration(config.ApprovalTTLSeconds) * time.Second),
+		UpdatedAt:   txTime,
+	}
+	if err := saveApproval(ctx, approval); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] ApproveAction: Người thứ hai (vai trò approver, khác người đề xuất) duyệt và
+// thực hiện ngay nghiệp vụ của đề xuất. Điều kiện nghiệp vụ được kiểm tra lại tại thời điểm duyệt.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) ApproveAction(ctx contractapi.TransactionContextInterface, orderID string, approvalID string) error {
+	if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+		return err
+	}
+
+	// 1. Kiểm tra ACL: tổ chức Sàn, định danh khác người đề xuất
+	actorOrg, err := requireRole(ctx, RolePlatform)
+	if err != nil {
+		return err
+	}
+	approval, err := getApproval(ctx, orderID, approvalID)
+	if err != nil {
+		return err
+	}
+	approverID, err := getCallerID(ctx)
+	if err != nil {
+		return err
+	}
+	if approverID == approval.ProposerID {
+		return newError(ctx, ErrSelfApproval, errorDetails{"approvalID": approvalID})
+	}
+
+	// 2. Đề xuất phải còn hiệu lực
+	if approval.Status != ApprovalPending {
+		return errInvalidState(ctx, approval.Status, ApprovalPending)
+	}
+	txTime, err := getTimeNow(ctx)
+	if err != nil {
+		return err
+	}
+	if !txTime.Before(approval.ExpiresAt) {
+		return newError(ctx, ErrApprovalExpired, errorDetails{"approvalID": approvalID, "expiresAt": approval.ExpiresAt})
+	}
+
+	// 3. Thực hiện nghiệp vụ với đúng số tiền đã đề xuất
+	order, err := getOrderState(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	switch approval.Action {
+	case approvalPayout:
+		if err := checkPayoutAllowed(ctx, order, txTime); err != nil {
+			return err
+		}
+		config, err := getPolicyConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if amount := unpaidPayout(order, &config.Fees); amount != approval.Amount {
+			return newError(ctx, ErrInvalidState, errorDetails{"currentStatus": amount, "requiredStatus": approval.Amount, "field": "amount"})
+		}
+		if err := settlePayout(ctx, order, actorOrg, txTime); err != nil {
+			return err
+		}
+	case approvalRefund:
+		returnCase, err := getReturnCaseState(ctx, orderID, approval.ReturnID)
+		if err != nil {
+			return err
+		}
+		if err := checkRefundAllowed(ctx, returnCase); err != nil {
+			return err
+		}
+		if err := settleRefund(ctx, order, returnCase, actorOrg, txTime); err != nil {
+			return err
+		}
+	}
+
+	// 4. Ghi nhận người duyệt
+	approval.Status = ApprovalExecuted
+	approval.ApproverOrg = actorOrg
+	approval.ApproverID = approverID
+	approval.ExecutedTxID = ctx.GetStub().GetTxID()
+	approval.UpdatedAt = txTime
+	return saveApproval(ctx, approval)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetApprovals: Sàn xem các đề xuất (đang chờ, đã thực hiện, hết hạn) của một đơn
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetApprovals(ctx contractapi.TransactionContextInterface, orderID string) ([]*Approval, error) {
+	if _, err := requireRole(ctx, RolePlatform); err != nil {
+		return nil, err
+	}
+	return getApprovals(ctx, orderID)
+}
@@ -0,0 +1,90 @@
+// my-ecommerce-chaincode/approvals_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func decodeSettlement(t *testing.T, result *SimResult) *SettlementResult {
+	t.Helper()
+	var settlement SettlementResult
+	if err := json.Unmarshal(result.Payload, &settlement); err != nil {
+		t.Fatalf("kết quả không phải SettlementResult: %q", result.Payload)
+	}
+	return &settlement
+}
+
+// newDeliveredOrder tạo đơn PREPAID orderID đã giao, đã qua thời gian giữ tiền
+func newDeliveredOrder(t *testing.T, sim *Simulator, orderID string) {
+	t.Helper()
+	mustSubmit(t, sim, "seller", "CreateOrder", orderID, "PREPAID", "GHN", "", "", "Shop_ABC",
+		`[{"lineID":"L1","sku":"TV","quantity":1,"unitPrice":5000000}]`)
+	mustSubmit(t, sim, "platform", "ConfirmPayment", orderID)
+	mustSubmit(t, sim, "shipper", "ShipOrder", orderID, "GHN")
+	mustSubmit(t, sim, "shipper", "ConfirmDelivery", orderID, "GHN")
+	sim.Advance(10 * time.Minute)
+}
+
+func TestPayoutBelowThresholdIsExecuted(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	newDeliveredOrder(t, sim, "AP001")
+
+	result, err := sim.Submit("platform", "PayoutToSeller", "AP001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settlement := decodeSettlement(t, result)
+	if settlement.Status != ApprovalExecuted || settlement.ApprovalID != "" || settlement.Amount != 5000000 {
+		t.Errorf("PayoutToSeller = %+v, muốn EXECUTED 5000000 không có approvalID", settlement)
+	}
+}
+
+func TestPayoutAboveThresholdReturnsPendingApproval(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	mustSubmit(t, sim, "platform", "SetPolicyConfig", `{"approvalThreshold": 1000000}`)
+	newDeliveredOrder(t, sim, "AP002")
+
+	call := SimCall{As: "platform", Function: "PayoutToSeller", Args: []string{"AP002"},
+		Transient: map[string][]byte{requestIDTransientKey: []byte("payout-AP002")}}
+	result, err := sim.Invoke(call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settlement := decodeSettlement(t, result)
+	if settlement.Status != ApprovalPending || settlement.ApprovalID != result.TxID {
+		t.Fatalf("PayoutToSeller = %+v, muốn PENDING với approvalID %s", settlement, result.TxID)
+	}
+
+	// Đơn chưa được thanh toán cho tới khi có người duyệt
+	order, err := sim.Evaluate("platform", "QueryOrder", "AP002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var status struct {
+		Status Status `json:"status"`
+	}
+	if err := json.Unmarshal(order.Payload, &status); err != nil || status.Status != StatusDelivered {
+		t.Fatalf("trạng thái khi chờ duyệt = %s, muốn %s", status.Status, StatusDelivered)
+	}
+
+	// Gửi lại cùng request ID nhận lại đúng đề xuất ban đầu
+	replay, err := sim.Invoke(call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := decodeSettlement(t, replay); *again != *settlement {
+		t.Errorf("gửi lại = %+v, muốn %+v", again, settlement)
+	}
+
+	mustSubmit(t, sim, "approver", "ApproveAction", "AP002", settlement.ApprovalID)
+	order, err = sim.Evaluate("platform", "QueryOrder", "AP002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(order.Payload, &status); err != nil || status.Status != StatusSettled {
+		t.Errorf("trạng thái sau khi duyệt = %s, muốn %s", status.Status, StatusSettled)
+	}
+}
@@ -0,0 +1,196 @@
+// my-ecommerce-chaincode/bootstrap.go
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Bootstrap là cấu hình khởi tạo truyền vào InitLedger (mọi phần đều tùy chọn):
+//
+//	{"roles": {"orgs": {...}}, "policy": {...PolicyConfig}, "companies": {"GHN": "ShipperOrgMSP"}}
+//
+// companies được gộp vào roles.companies (hoặc sổ đăng ký hiện hành nếu không có roles).
+type Bootstrap struct {
+	Roles     *RoleRegistry     `json:"roles,omitempty"`
+	Policy    json.RawMessage   `json:"policy,omitempty"`
+	Companies map[string]string `json:"companies,omitempty"`
+}
+
+// ContractMetadata ghi nhận lần khởi tạo đầu tiên và gần nhất của hợp đồng (Config~metadata)
+type ContractMetadata struct {
+	ContractVersion string    `json:"contractVersion"`
+	SchemaVersion   int       `json:"schemaVersion"` // Phiên bản lược đồ Order của chaincode
+	InitializedAt   time.Time `json:"initializedAt"`
+	InitializedBy   string    `json:"initializedBy"`
+	InitTxID        string    `json:"initTxID"`
+	LastInitAt      time.Time `json:"lastInitAt"`
+	LastInitTxID    string    `json:"lastInitTxID"`
+}
+
+// configExists kiểm tra bản ghi cấu hình Config~section đã có trên sổ cái chưa
+func configExists(ctx contractapi.TransactionContextInterface, section string) (bool, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{section})
+	if err != nil {
+		return false, errLedger(ctx, "CreateCompositeKey", err)
+	}
+	value, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return false, errLedger(ctx, "GetState", err)
+	}
+	return value != nil, nil
+}
+
+// getContractMetadata: Đọc bản ghi metadata, nil nếu hợp đồng chưa từng được khởi tạo
+func getContractMetadata(ctx contractapi.TransactionContextInterface) (*ContractMetadata, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{"metadata"})
+	if err != nil {
+		return nil, errLedger(ctx, "CreateCompositeKey", err)
+	}
+	metadataJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, errLedger(ctx, "GetState", err)
+	}
+	if metadataJSON == nil {
+		return nil, nil
+	}
+
+	var metadata ContractMetadata
+	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
+		return nil, errLedger(ctx, "unmarshal", err)
+	}
+	return &metadata, nil
+}
+
+// saveContractMetadata: Lưu bản ghi metadata
+func saveContractMetadata(ctx contractapi.TransactionContextInterface, metadata *ContractMetadata) error {
+	key, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{"metadata"})
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	return ctx.GetStub().PutState(key, metadataJSON)
+}
+
+// applyBootstrapSection ghi một phần cấu hình. Bản ghi đã tồn tại chỉ được ghi đè khi
+// overwrite = true; nếu không, bootstrap phải trùng cấu hình hiện hành (gọi lại an toàn).
+func applyBootstrapSection(ctx contractapi.TransactionContextInterface, section string, current interface{}, desired interface{}, overwrite bool, save func() error) error {
+	exists, err := configExists(ctx, section)
+	if err != nil {
+		return err
+	}
+	if exists && !overwrite {
+		currentJSON, err := json.Marshal(current)
+		if err != nil {
+			return errLedger(ctx, "marshal", err)
+		}
+		desiredJSON, err := json.Marshal(desired)
+		if err != nil {
+			return errLedger(ctx, "marshal", err)
+		}
+		if !bytes.Equal(currentJSON, desiredJSON) {
+			return newError(ctx, ErrConfigExists, errorDetails{"section": section})
+		}
+		return nil
+	}
+	return save()
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] InitLedger: Khởi tạo cấu hình hợp đồng từ bootstrapJSON (rỗng = mặc định), được
+// ecom-deploy gọi với --isInit sau mỗi lần nâng cấp. Cấu hình đã có trên sổ cái
+// được giữ nguyên; bootstrap khác cấu hình hiện hành bị từ chối trừ khi overwrite = true.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface, bootstrapJSON string, overwrite bool) error {
+	// 1. Kiểm tra ACL
+	actorOrg, err := requireRole(ctx, RolePlatform)
+	if err != nil {
+		return err
+	}
+	var bootstrap Bootstrap
+	if bootstrapJSON != "" {
+		if err := json.Unmarshal([]byte(bootstrapJSON), &bootstrap); err != nil {
+			return errInvalidArgument(ctx, "bootstrapJSON", err.Error())
+		}
+	}
+
+	// 2. Sổ đăng ký vai trò và công ty (mặc định khi sổ cái chưa có và bootstrap không khai báo)
+	current, err := getRoleRegistry(ctx)
+	if err != nil {
+		return err
+	}
+	registry := bootstrap.Roles
+	if registry == nil {
+		registry = &RoleRegistry{Orgs: current.Orgs, Companies: current.Companies}
+	}
+	if len(bootstrap.Companies) > 0 {
+		companies := make(map[string]string, len(registry.Companies)+len(bootstrap.Companies))
+		for companyID, mspID := range registry.Companies {
+			companies[companyID] = mspID
+		}
+		for companyID, mspID := range bootstrap.Companies {
+			companies[companyID] = mspID
+		}
+		registry.Companies = companies
+	}
+	if err := registry.validate(ctx); err != nil {
+		return err
+	}
+	if registry.Orgs[actorOrg] != RolePlatform {
+		return errInvalidArgument(ctx, "roles.orgs["+actorOrg+"]", string(RolePlatform))
+	}
+	if err := applyBootstrapSection(ctx, "roles", current, registry, overwrite, func() error {
+		return saveRoleRegistry(ctx, registry)
+	}); err != nil {
+		return err
+	}
+
+	// 3. Cấu hình chính sách (thời hạn, ngưỡng duyệt, biểu phí); giải mã chồng lên mặc định
+	if len(bootstrap.Policy) > 0 {
+		config := defaultPolicyConfig()
+		if err := json.Unmarshal(bootstrap.Policy, config); err != nil {
+			return errInvalidArgument(ctx, "policy", err.Error())
+		}
+		if err := config.validate(ctx); err != nil {
+			return err
+		}
+		currentConfig, err := getPolicyConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if err := applyBootstrapSection(ctx, "policy", currentConfig, config, overwrite, func() error {
+			return savePolicyConfig(ctx, config)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// 4. Metadata: giữ thời điểm khởi tạo đầu tiên, cập nhật phiên bản và lần gọi gần nhất
+	txTime, err := getTimeNow(ctx)
+	if err != nil {
+		return err
+	}
+	metadata, err := getContractMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		metadata = &ContractMetadata{
+			InitializedAt: txTime,
+			InitializedBy: actorOrg,
+			InitTxID:      ctx.GetStub().GetTxID(),
+		}
+	}
+	metadata.ContractVersion = contractVersion
+	metadata.SchemaVersion = currentOrderSchemaVersion
+	metadata.LastInitAt = txTime
+	metadata.LastInitTxID = ctx.GetStub().GetTxID()
+	return saveContractMetadata(ctx, metadata)
+}
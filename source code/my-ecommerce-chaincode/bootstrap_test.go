@@ -0,0 +1,112 @@
+// my-ecommerce-chaincode/bootstrap_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const testBootstrap = `{"policy": {"payoutHoldSeconds": 600}, "companies": {"GHN": "ShipperOrgMSP"}}`
+
+func initLedger(sim *Simulator, bootstrapJSON string, overwrite bool) error {
+	overwriteArg := "false"
+	if overwrite {
+		overwriteArg = "true"
+	}
+	_, err := sim.Submit("platform", "InitLedger", bootstrapJSON, overwriteArg)
+	return err
+}
+
+func contractInfo(t *testing.T, sim *Simulator) *ContractInfo {
+	t.Helper()
+	result, err := sim.Evaluate("platform", "GetContractInfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info ContractInfo
+	if err := json.Unmarshal(result.Payload, &info); err != nil {
+		t.Fatal(err)
+	}
+	return &info
+}
+
+func TestInitLedgerSameBootstrapIsNoOp(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	initializedAt := sim.Now()
+	if err := initLedger(sim, testBootstrap, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Gọi lại sau mỗi lần nâng cấp: cùng bootstrap, hoặc bootstrap rỗng, đều được chấp nhận
+	sim.Advance(time.Hour)
+	if err := initLedger(sim, testBootstrap, false); err != nil {
+		t.Fatalf("gọi lại cùng bootstrap: %v", err)
+	}
+	sim.Advance(time.Hour)
+	if err := initLedger(sim, "", false); err != nil {
+		t.Fatalf("gọi lại với bootstrap rỗng: %v", err)
+	}
+
+	info := contractInfo(t, sim)
+	if info.Policy.PayoutHoldSeconds != 600 || info.Roles.Companies["GHN"] != "ShipperOrgMSP" {
+		t.Errorf("cấu hình = payoutHold %d, GHN %q; muốn 600, ShipperOrgMSP", info.Policy.PayoutHoldSeconds, info.Roles.Companies["GHN"])
+	}
+	metadata := info.Metadata
+	if metadata == nil {
+		t.Fatal("thiếu metadata sau InitLedger")
+	}
+	if !metadata.InitializedAt.Equal(initializedAt) || !metadata.LastInitAt.Equal(initializedAt.Add(2*time.Hour)) {
+		t.Errorf("metadata = khởi tạo %s, gần nhất %s; muốn %s, %s", metadata.InitializedAt, metadata.LastInitAt, initializedAt, initializedAt.Add(2*time.Hour))
+	}
+	if metadata.InitTxID == metadata.LastInitTxID || metadata.SchemaVersion != currentOrderSchemaVersion {
+		t.Errorf("metadata = %+v, muốn giữ InitTxID của lần đầu và schemaVersion %d", metadata, currentOrderSchemaVersion)
+	}
+}
+
+func TestInitLedgerDifferentBootstrapNeedsOverwrite(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	initializedAt := sim.Now()
+	if err := initLedger(sim, testBootstrap, false); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Hour)
+
+	changedPolicy := `{"policy": {"payoutHoldSeconds": 900}, "companies": {"GHN": "ShipperOrgMSP"}}`
+	changedRoles := `{"companies": {"GHN": "ShipperOrgMSP", "VTP": "ShipperOrgMSP"}}`
+	for _, bootstrap := range []string{changedPolicy, changedRoles} {
+		if err := initLedger(sim, bootstrap, false); !hasErrorCode(err, ErrConfigExists) {
+			t.Errorf("bootstrap %s: lỗi = %v, muốn %s", bootstrap, err, ErrConfigExists)
+		}
+	}
+	if info := contractInfo(t, sim); info.Policy.PayoutHoldSeconds != 600 || info.Roles.Companies["VTP"] != "" {
+		t.Fatal("bootstrap bị từ chối vẫn thay đổi cấu hình")
+	}
+
+	if err := initLedger(sim, changedPolicy, true); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	info := contractInfo(t, sim)
+	if info.Policy.PayoutHoldSeconds != 900 {
+		t.Errorf("payoutHoldSeconds = %d sau overwrite, muốn 900", info.Policy.PayoutHoldSeconds)
+	}
+	if !info.Metadata.InitializedAt.Equal(initializedAt) || !info.Metadata.LastInitAt.Equal(initializedAt.Add(time.Hour)) {
+		t.Errorf("metadata = khởi tạo %s, gần nhất %s; overwrite không được đổi thời điểm khởi tạo", info.Metadata.InitializedAt, info.Metadata.LastInitAt)
+	}
+}
+
+func TestInitLedgerRequiresPlatform(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	if _, err := sim.Submit("seller", "InitLedger", testBootstrap, "false"); !hasErrorCode(err, ErrAccessDenied) {
+		t.Errorf("lỗi = %v, muốn %s", err, ErrAccessDenied)
+	}
+	// Bootstrap không được tước vai trò Sàn của chính tổ chức gọi
+	roles := `{"roles": {"orgs": {"ECommercePlatformOrgMSP": "SELLER", "OtherPlatformMSP": "PLATFORM"}}}`
+	if err := initLedger(sim, roles, false); !hasErrorCode(err, ErrInvalidArgument) {
+		t.Errorf("lỗi = %v, muốn %s", err, ErrInvalidArgument)
+	}
+	if info := contractInfo(t, sim); info.Metadata != nil {
+		t.Error("InitLedger bị từ chối vẫn ghi metadata")
+	}
+}
@@ -0,0 +1,145 @@
+// my-ecommerce-chaincode/config.go
+
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const configObjectType = "Config"
+
+// PolicyConfig chứa các tham số chính sách của hợp đồng, lưu trên sổ cái
+// để Sàn có thể thay đổi mà không cần nâng cấp chaincode.
+type PolicyConfig struct {
+	PaymentDeadlineSeconds int64       `json:"paymentDeadlineSeconds"` // Hạn thanh toán đơn PREPAID, tính từ lúc tạo đơn
+	ReturnWindowSeconds    int64       `json:"returnWindowSeconds"`    // Thời hạn yêu cầu trả hàng, tính từ lúc giao
+	PayoutHoldSeconds      int64       `json:"payoutHoldSeconds"`      // Thời gian giữ tiền trước khi thanh toán cho Seller
+	ApprovalThreshold      int64       `json:"approvalThreshold"`      // Thanh toán/hoàn tiền lớn hơn ngưỡng cần người thứ hai duyệt (0 = tắt)
+	ApprovalTTLSeconds     int64       `json:"approvalTTLSeconds"`     // Thời hạn của một đề xuất chờ duyệt
+	Fees                   FeeSchedule `json:"fees"`
+}
+
+// FeeSchedule là biểu phí Sàn khấu trừ khi thanh toán cho Seller
+type FeeSchedule struct {
+	CommissionBps int64 `json:"commissionBps"` // Hoa hồng theo phần vạn trên tiền hàng được thanh toán (250 = 2,5%)
+	CodFee        int64 `json:"codFee"`        // Phí cố định cho mỗi đơn COD
+}
+
+// defaultPolicyConfig là cấu hình dùng khi sổ cái chưa có bản ghi cấu hình.
+// Thời hạn trả hàng và thời gian giữ tiền mặc định 5 phút (DEMO, thực tế: 7 ngày).
+func defaultPolicyConfig() *PolicyConfig {
+	return &PolicyConfig{
+		PaymentDeadlineSeconds: 24 * 60 * 60,
+		ReturnWindowSeconds:    5 * 60,
+		PayoutHoldSeconds:      5 * 60,
+		ApprovalThreshold:      0,
+		ApprovalTTLSeconds:     24 * 60 * 60,
+	}
+}
+
+// validate kiểm tra các giá trị cấu hình hợp lệ
+func (p *PolicyConfig) validate(ctx contractapi.TransactionContextInterface) error {
+	if p.PaymentDeadlineSeconds <= 0 {
+		return errInvalidArgument(ctx, "paymentDeadlineSeconds", "> 0")
+	}
+	if p.ReturnWindowSeconds <= 0 {
+		return errInvalidArgument(ctx, "returnWindowSeconds", "> 0")
+	}
+	if p.PayoutHoldSeconds < 0 {
+		return errInvalidArgument(ctx, "payoutHoldSeconds", ">= 0")
+	}
+	if p.ApprovalThreshold < 0 {
+		return errInvalidArgument(ctx, "approvalThreshold", ">= 0")
+	}
+	if p.ApprovalTTLSeconds <= 0 {
+		return errInvalidArgument(ctx, "approvalTTLSeconds", "> 0")
+	}
+	if p.Fees.CommissionBps < 0 || p.Fees.CommissionBps > 10000 {
+		return errInvalidArgument(ctx, "fees.commissionBps", "0..10000")
+	}
+	if p.Fees.CodFee < 0 {
+		return errInvalidArgument(ctx, "fees.codFee", ">= 0")
+	}
+	return nil
+}
+
+// platformFee tính phí Sàn trên số tiền hàng thanh toán cho Seller (không vượt quá số tiền đó)
+func (f *FeeSchedule) platformFee(order *Order, netAmount int64) int64 {
+	fee := netAmount * f.CommissionBps / 10000
+	if order.PaymentMethod == PaymentCOD {
+		fee += f.CodFee
+	}
+	if fee > netAmount {
+		fee = netAmount
+	}
+	if fee < 0 {
+		fee = 0
+	}
+	return fee
+}
+
+// getPolicyConfig: Đọc cấu hình chính sách, trả về mặc định nếu chưa được thiết lập
+func getPolicyConfig(ctx contractapi.TransactionContextInterface) (*PolicyConfig, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{"policy"})
+	if err != nil {
+		return nil, errLedger(ctx, "CreateCompositeKey", err)
+	}
+	configJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, errLedger(ctx, "GetState", err)
+	}
+	if configJSON == nil {
+		return defaultPolicyConfig(), nil
+	}
+
+	// Giải mã chồng lên giá trị mặc định để các trường mới có giá trị hợp lệ
+	config := defaultPolicyConfig()
+	if err := json.Unmarshal(configJSON, config); err != nil {
+		return nil, errLedger(ctx, "unmarshal", err)
+	}
+	return config, nil
+}
+
+// savePolicyConfig: Lưu cấu hình chính sách vào sổ cái
+func savePolicyConfig(ctx contractapi.TransactionContextInterface, config *PolicyConfig) error {
+	key, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{"policy"})
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	return ctx.GetStub().PutState(key, configJSON)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] SetPolicyConfig: Sàn cập nhật cấu hình chính sách (JSON của PolicyConfig)
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) SetPolicyConfig(ctx contractapi.TransactionContextInterface, policyJSON string) error {
+	if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+		return err
+	}
+
+	if _, err := requireRole(ctx, RolePlatform); err != nil {
+		return err
+	}
+
+	config := defaultPolicyConfig()
+	if err := json.Unmarshal([]byte(policyJSON), config); err != nil {
+		return errInvalidArgument(ctx, "policyJSON", err.Error())
+	}
+	if err := config.validate(ctx); err != nil {
+		return err
+	}
+	return savePolicyConfig(ctx, config)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetPolicyConfig: Xem cấu hình chính sách hiện hành
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetPolicyConfig(ctx contractapi.TransactionContextInterface) (*PolicyConfig, error) {
+	return getPolicyConfig(ctx)
+}
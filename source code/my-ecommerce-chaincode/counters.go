@@ -0,0 +1,306 @@
+// my-ecommerce-chaincode/counters.go
+
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Bộ đếm số đơn theo trạng thái, lưu thành tổng đã cộng dồn theo khóa
+// OrderCount~status~bucket. Đơn thuộc một trong orderCountBuckets ngăn theo băm của orderID,
+// nên hai giao dịch song song chỉ xung đột MVCC khi đơn của chúng rơi vào cùng ngăn; số khóa
+// GetContractInfo phải đọc luôn không quá số trạng thái x orderCountBuckets.
+//
+// Thay đổi trong một giao dịch được gom trong orderContext rồi ghi một lần ở afterTransaction
+// (GetState không thấy PutState của chính giao dịch, nên không cộng dồn trực tiếp được khi một
+// giao dịch lô đổi nhiều đơn cùng ngăn). Giao dịch lỗi không ghi gì.
+const (
+	orderCountObjectType        = "OrderCount"
+	orderCountRebuildObjectType = "OrderCountRebuild" // Tổng tạm của RebuildOrderCounters giữa các lượt
+	orderCountBuckets           = 32
+	rebuildCountBucket          = "00" // RebuildOrderCounters ghi kết quả đếm vào một ngăn
+	maxCounterRebuildBatch      = 500
+
+	// Phạm vi của mọi khóa đơn giản (khóa composite bắt đầu bằng \x00; khóa phải là UTF-8 hợp lệ).
+	// shim thay startKey rỗng bằng \x01 và peer coi endKey rỗng là không giới hạn; ghi rõ hai đầu
+	// để MockStub quét đúng phạm vi.
+	firstSimpleKey = "\x01"
+	lastSimpleKey  = string(utf8.MaxRune)
+)
+
+// orderContext là ngữ cảnh giao dịch của SmartContract
+type orderContext struct {
+	contractapi.TransactionContext
+	countDeltas map[countKey]int64 // Thay đổi bộ đếm của giao dịch, ghi ở afterTransaction
+}
+
+type countKey struct {
+	status string
+	bucket string
+}
+
+// GetTransactionContextHandler: contractapi tạo một orderContext mới cho mỗi giao dịch
+func (s *SmartContract) GetTransactionContextHandler() contractapi.SettableTransactionContextInterface {
+	return new(orderContext)
+}
+
+// GetAfterTransaction: contractapi gọi hàm này sau giao dịch thành công của SmartContract
+func (s *SmartContract) GetAfterTransaction() interface{} {
+	return afterTransaction
+}
+
+// afterTransaction: Ghi các thay đổi bộ đếm đã gom trong giao dịch
+func afterTransaction(ctx contractapi.TransactionContextInterface) error {
+	if tx, ok := ctx.(*orderContext); ok {
+		return tx.flushCounts()
+	}
+	return nil
+}
+
+// countBucket là ngăn của đơn orderID
+func countBucket(orderID string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(orderID))
+	return fmt.Sprintf("%02d", hash.Sum32()%orderCountBuckets)
+}
+
+// recordStatusChange cập nhật bộ đếm khi đơn chuyển từ trạng thái from (rỗng = đơn mới) sang to
+func recordStatusChange(ctx contractapi.TransactionContextInterface, from Status, to Status, orderID string) error {
+	if from == to {
+		return nil
+	}
+	tx, ok := ctx.(*orderContext)
+	if !ok {
+		return errLedger(ctx, "recordStatusChange", fmt.Errorf("ngữ cảnh giao dịch %T không có bộ đếm", ctx))
+	}
+	noteStatusChange(ctx.GetStub(), from, to)
+	if tx.countDeltas == nil {
+		tx.countDeltas = make(map[countKey]int64)
+	}
+	bucket := countBucket(orderID)
+	if from != "" {
+		tx.countDeltas[countKey{string(from), bucket}]--
+	}
+	tx.countDeltas[countKey{string(to), bucket}]++
+	return nil
+}
+
+// flushCounts cộng các thay đổi đã gom vào tổng của từng ngăn (theo thứ tự khóa để tất định)
+func (tx *orderContext) flushCounts() error {
+	keys := make([]countKey, 0, len(tx.countDeltas))
+	for key, delta := range tx.countDeltas {
+		if delta != 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].status != keys[j].status {
+			return keys[i].status < keys[j].status
+		}
+		return keys[i].bucket < keys[j].bucket
+	})
+
+	for _, key := range keys {
+		ledgerKey, err := tx.GetStub().CreateCompositeKey(orderCountObjectType, []string{key.status, key.bucket})
+		if err != nil {
+			return errLedger(tx, "CreateCompositeKey", err)
+		}
+		value, err := tx.GetStub().GetState(ledgerKey)
+		if err != nil {
+			return errLedger(tx, "GetState", err)
+		}
+		var count int64
+		if value != nil {
+			if count, err = strconv.ParseInt(string(value), 10, 64); err != nil {
+				return errLedger(tx, "ParseInt", err)
+			}
+		}
+		count += tx.countDeltas[key]
+		if count == 0 {
+			if err := tx.GetStub().DelState(ledgerKey); err != nil {
+				return errLedger(tx, "DelState", err)
+			}
+		} else if err := tx.GetStub().PutState(ledgerKey, []byte(strconv.FormatInt(count, 10))); err != nil {
+			return errLedger(tx, "PutState", err)
+		}
+	}
+	tx.countDeltas = nil
+	return nil
+}
+
+// readCounts cộng các khóa objectType~status~... theo trạng thái (attrs[0])
+func readCounts(ctx contractapi.TransactionContextInterface, objectType string) (map[string]int64, []string, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, []string{})
+	if err != nil {
+		return nil, nil, errLedger(ctx, "GetStateByPartialCompositeKey", err)
+	}
+	defer iterator.Close()
+
+	counts := make(map[string]int64)
+	var keys []string
+	for iterator.HasNext() {
+		item, err := iterator.Next()
+		if err != nil {
+			return nil, nil, errLedger(ctx, "iterator.Next", err)
+		}
+		_, attrs, err := ctx.GetStub().SplitCompositeKey(item.Key)
+		if err != nil || len(attrs) == 0 {
+			return nil, nil, errLedger(ctx, "SplitCompositeKey", fmt.Errorf("khóa bộ đếm không hợp lệ: %q", item.Key))
+		}
+		value, err := strconv.ParseInt(string(item.Value), 10, 64)
+		if err != nil {
+			return nil, nil, errLedger(ctx, "ParseInt", err)
+		}
+		counts[attrs[0]] += value
+		keys = append(keys, item.Key)
+	}
+	return counts, keys, nil
+}
+
+// getOrderCounts cộng tổng các ngăn của mọi trạng thái
+func getOrderCounts(ctx contractapi.TransactionContextInterface) (map[string]int64, error) {
+	counts, _, err := readCounts(ctx, orderCountObjectType)
+	return counts, err
+}
+
+// CounterRebuildResult là kết quả của một lượt RebuildOrderCounters
+type CounterRebuildResult struct {
+	Scanned  int              `json:"scanned"`                                 // Số khóa đã đọc trong lượt này
+	Counts   map[string]int64 `json:"counts,omitempty" metadata:",optional"`   // Số đơn theo trạng thái, có ở lượt cuối
+	Bookmark string           `json:"bookmark,omitempty" metadata:",optional"` // fromKey cho lượt tiếp theo, rỗng khi đã xong
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] RebuildOrderCounters: Sàn đếm lại số đơn theo trạng thái từ chính các đơn hàng,
+// từng lượt tối đa limit khóa bắt đầu từ fromKey (rỗng = từ đầu; tổng tạm của lần đếm trước
+// bị bỏ). Gọi lại với bookmark trả về cho đến khi bookmark rỗng: lượt cuối thay bộ đếm bằng
+// kết quả đếm. Đếm qua nhiều lượt cần đóng băng toàn cục (Freeze GLOBAL) để không đơn nào đổi trạng thái
+// giữa các lượt; khi chưa đóng băng chỉ chạy được nếu xong trong một lượt. Sau khi nâng cấp từ
+// phiên bản chưa có bộ đếm, chạy một lần để đếm các đơn đã có (bộ đếm bắt đầu từ 0).
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) RebuildOrderCounters(ctx contractapi.TransactionContextInterface, fromKey string, limit int) (*CounterRebuildResult, error) {
+	if prior, err := checkClientRequest(ctx); err != nil {
+		return nil, err
+	} else if prior != nil {
+		result := &CounterRebuildResult{}
+		return result, prior.decodeResult(ctx, result)
+	}
+
+	// 1. Kiểm tra ACL
+	if _, err := requireRole(ctx, RolePlatform); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxCounterRebuildBatch {
+		return nil, errInvalidArgument(ctx, "limit", fmt.Sprintf("1..%d", maxCounterRebuildBatch))
+	}
+	globalFreeze, err := getFreeze(ctx, FreezeGlobal, globalFreezeTarget)
+	if err != nil {
+		return nil, err
+	}
+	frozen := globalFreeze != nil
+	if fromKey != "" && !frozen {
+		return nil, errRebuildNotFrozen(ctx)
+	}
+
+	// 2. Tổng tạm của các lượt trước (lượt đầu bắt đầu từ 0 và xóa tổng tạm cũ)
+	staged, stagedKeys, err := readCounts(ctx, orderCountRebuildObjectType)
+	if err != nil {
+		return nil, err
+	}
+	if fromKey == "" {
+		for _, key := range stagedKeys {
+			if err := ctx.GetStub().DelState(key); err != nil {
+				return nil, errLedger(ctx, "DelState", err)
+			}
+		}
+		staged, stagedKeys = make(map[string]int64), nil
+	}
+
+	// 3. Đếm đơn (khóa đơn giản) theo trạng thái; đọc thêm một khóa để biết lượt sau bắt đầu từ đâu
+	startKey := fromKey
+	if startKey == "" {
+		startKey = firstSimpleKey
+	}
+	result := &CounterRebuildResult{}
+	resultsIterator, err := ctx.GetStub().GetStateByRange(startKey, lastSimpleKey)
+	if err != nil {
+		return nil, errLedger(ctx, "GetStateByRange", err)
+	}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			resultsIterator.Close()
+			return nil, errLedger(ctx, "iterator.Next", err)
+		}
+		if result.Scanned == limit {
+			result.Bookmark = queryResponse.Key
+			break
+		}
+		result.Scanned++
+
+		order, _, err := decodeOrder(queryResponse.Value)
+		if err != nil {
+			resultsIterator.Close()
+			return nil, errLedger(ctx, "unmarshal "+queryResponse.Key, err)
+		}
+		if order.DocType == "Order" {
+			staged[string(order.Status)]++
+		}
+	}
+	resultsIterator.Close()
+
+	if result.Bookmark != "" && !frozen {
+		return nil, errRebuildNotFrozen(ctx)
+	}
+
+	// 4. Chưa xong: lưu tổng tạm cho lượt sau
+	if result.Bookmark != "" {
+		for status, count := range staged {
+			if err := putCount(ctx, orderCountRebuildObjectType, []string{status}, count); err != nil {
+				return nil, err
+			}
+		}
+		return result, recordClientResult(ctx, result)
+	}
+
+	// 5. Lượt cuối: thay mọi ngăn bằng kết quả đếm (ghi vào một ngăn) và xóa tổng tạm
+	_, liveKeys, err := readCounts(ctx, orderCountObjectType)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range append(liveKeys, stagedKeys...) {
+		if err := ctx.GetStub().DelState(key); err != nil {
+			return nil, errLedger(ctx, "DelState", err)
+		}
+	}
+	for status, count := range staged {
+		if err := putCount(ctx, orderCountObjectType, []string{status, rebuildCountBucket}, count); err != nil {
+			return nil, err
+		}
+	}
+	result.Counts = staged
+	return result, recordClientResult(ctx, result)
+}
+
+// errRebuildNotFrozen báo RebuildOrderCounters cần nhiều lượt mà hợp đồng chưa đóng băng toàn cục
+func errRebuildNotFrozen(ctx contractapi.TransactionContextInterface) error {
+	return newError(ctx, ErrInvalidState, errorDetails{"currentStatus": "NOT_FROZEN", "requiredStatus": FreezeGlobal, "field": "freeze"})
+}
+
+// putCount ghi một tổng của bộ đếm
+func putCount(ctx contractapi.TransactionContextInterface, objectType string, attrs []string, count int64) error {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, attrs)
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	if err := ctx.GetStub().PutState(key, []byte(strconv.FormatInt(count, 10))); err != nil {
+		return errLedger(ctx, "PutState", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// my-ecommerce-chaincode/counters_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func orderCounts(t *testing.T, sim *Simulator) map[string]int64 {
+	t.Helper()
+	result, err := sim.Evaluate("platform", "GetContractInfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info ContractInfo
+	if err := json.Unmarshal(result.Payload, &info); err != nil {
+		t.Fatal(err)
+	}
+	return info.OrderCounts
+}
+
+func createUnpaidOrders(t *testing.T, sim *Simulator, n int) {
+	t.Helper()
+	for i := 1; i <= n; i++ {
+		mustSubmit(t, sim, "seller", "CreateOrder", fmt.Sprintf("CT%03d", i), "PREPAID", "GHN", "", "", "Shop_ABC",
+			`[{"lineID":"L1","sku":"A","quantity":1,"unitPrice":100000}]`)
+	}
+}
+
+func rebuild(t *testing.T, sim *Simulator, fromKey string, limit int) (*CounterRebuildResult, error) {
+	t.Helper()
+	result, err := sim.Submit("platform", "RebuildOrderCounters", fromKey, fmt.Sprint(limit))
+	if err != nil {
+		return nil, err
+	}
+	var rebuilt CounterRebuildResult
+	if err := json.Unmarshal(result.Payload, &rebuilt); err != nil {
+		t.Fatal(err)
+	}
+	return &rebuilt, nil
+}
+
+func TestBatchStatusChangesAreCountedOnce(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	createUnpaidOrders(t, sim, 40)
+	mustSubmit(t, sim, "platform", "ConfirmPayment", "CT001")
+
+	// Một giao dịch đổi nhiều đơn, có đơn cùng ngăn: mỗi đơn phải được đếm đúng một lần
+	sim.Advance(30 * 24 * time.Hour)
+	mustSubmit(t, sim, "platform", "ExpireUnpaidOrders", "", "100")
+
+	want := map[string]int64{"PAID": 1, "EXPIRED": 39}
+	if got := orderCounts(t, sim); !reflect.DeepEqual(got, want) {
+		t.Errorf("số đơn = %v, muốn %v", got, want)
+	}
+}
+
+func TestGetContractInfoReadsBoundedKeys(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	createUnpaidOrders(t, sim, 200)
+
+	keys := 0
+	for key := range sim.stub.State {
+		if strings.HasPrefix(key, "\x00"+orderCountObjectType+"\x00") {
+			keys++
+		}
+	}
+	if keys > orderCountBuckets {
+		t.Errorf("%d khóa bộ đếm cho một trạng thái, muốn <= %d", keys, orderCountBuckets)
+	}
+	if got := orderCounts(t, sim)["CREATED"]; got != 200 {
+		t.Errorf("CREATED = %d, muốn 200", got)
+	}
+}
+
+func TestRebuildOrderCountersSinglePage(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	createUnpaidOrders(t, sim, 3)
+
+	// Bộ đếm sai (VD: đơn có từ trước khi có bộ đếm) được thay bằng kết quả đếm
+	staleKey, _ := sim.stub.CreateCompositeKey(orderCountObjectType, []string{"CREATED", "07"})
+	sim.stub.MockTransactionStart("stale")
+	sim.stub.PutState(staleKey, []byte("7"))
+	sim.stub.MockTransactionEnd("stale")
+
+	rebuilt, err := rebuild(t, sim, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt.Bookmark != "" || rebuilt.Scanned != 3 || !reflect.DeepEqual(rebuilt.Counts, map[string]int64{"CREATED": 3}) {
+		t.Errorf("RebuildOrderCounters = %+v", rebuilt)
+	}
+	if sim.State(staleKey) != nil {
+		t.Error("ngăn bộ đếm cũ chưa bị xóa")
+	}
+	if got := orderCounts(t, sim); !reflect.DeepEqual(got, map[string]int64{"CREATED": 3}) {
+		t.Errorf("số đơn sau khi đếm lại = %v", got)
+	}
+}
+
+func TestRebuildOrderCountersPagesRequireGlobalFreeze(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	createUnpaidOrders(t, sim, 5)
+	mustSubmit(t, sim, "platform", "ConfirmPayment", "CT002")
+
+	if _, err := rebuild(t, sim, "", 2); !hasErrorCode(err, ErrInvalidState) {
+		t.Fatalf("đếm nhiều lượt khi chưa đóng băng: lỗi = %v, muốn %s", err, ErrInvalidState)
+	}
+
+	mustSubmit(t, sim, "platform", "Freeze", string(FreezeGlobal), globalFreezeTarget, "đếm lại bộ đếm")
+	var rebuilt *CounterRebuildResult
+	pages := 0
+	for fromKey := ""; pages == 0 || fromKey != ""; pages++ {
+		var err error
+		if rebuilt, err = rebuild(t, sim, fromKey, 2); err != nil {
+			t.Fatal(err)
+		}
+		fromKey = rebuilt.Bookmark
+	}
+	if pages != 3 {
+		t.Errorf("số lượt = %d, muốn 3", pages)
+	}
+	want := map[string]int64{"CREATED": 4, "PAID": 1}
+	if !reflect.DeepEqual(rebuilt.Counts, want) {
+		t.Errorf("kết quả lượt cuối = %v, muốn %v", rebuilt.Counts, want)
+	}
+	mustSubmit(t, sim, "platform", "Unfreeze", string(FreezeGlobal), globalFreezeTarget, "xong")
+	if got := orderCounts(t, sim); !reflect.DeepEqual(got, want) {
+		t.Errorf("số đơn sau khi đếm lại = %v, muốn %v", got, want)
+	}
+}
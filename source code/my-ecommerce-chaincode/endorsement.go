@@ -0,0 +1,100 @@
+// my-ecommerce-chaincode/endorsement.go
+
+package main
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Chính sách chứng thực theo khóa (state-based endorsement) của đơn hàng:
+//   - CreateOrder: peer của Seller sở hữu đơn + peer của Sàn
+//   - Giao dịch của Shipper (lấy hàng, giao hàng, hoàn hàng): thêm peer của hãng vận chuyển
+// Sau đó mọi thay đổi của đơn phải có chữ ký của tất cả tổ chức trong chính sách, bất kể
+// chính sách chung của chaincode. Client cần gửi đề xuất tới đủ các peer này
+// (xem GetOrderEndorsers) vì service discovery không biết chính sách theo khóa.
+
+// newOrderPolicy đọc chính sách hiện tại của khóa đơn hàng (rỗng nếu chưa có)
+func newOrderPolicy(ctx contractapi.TransactionContextInterface, orderID string) (statebased.KeyEndorsementPolicy, error) {
+	current, err := ctx.GetStub().GetStateValidationParameter(orderID)
+	if err != nil {
+		return nil, errLedger(ctx, "GetStateValidationParameter", err)
+	}
+	policy, err := statebased.NewStateEP(current)
+	if err != nil {
+		return nil, errLedger(ctx, "NewStateEP", err)
+	}
+	return policy, nil
+}
+
+// putOrderPolicy ghi chính sách chứng thực cho khóa đơn hàng
+func putOrderPolicy(ctx contractapi.TransactionContextInterface, orderID string, policy statebased.KeyEndorsementPolicy) error {
+	policyBytes, err := policy.Policy()
+	if err != nil {
+		return errLedger(ctx, "Policy", err)
+	}
+	if err := ctx.GetStub().SetStateValidationParameter(orderID, policyBytes); err != nil {
+		return errLedger(ctx, "SetStateValidationParameter", err)
+	}
+	return nil
+}
+
+// setOrderEndorsers: Đặt chính sách của khóa đơn hàng là đúng các MSP (vai trò peer) được liệt kê
+func setOrderEndorsers(ctx contractapi.TransactionContextInterface, orderID string, mspIDs ...string) error {
+	policy, err := statebased.NewStateEP(nil)
+	if err != nil {
+		return errLedger(ctx, "NewStateEP", err)
+	}
+	if err := policy.AddOrgs(statebased.RoleTypePeer, mspIDs...); err != nil {
+		return errLedger(ctx, "AddOrgs", err)
+	}
+	return putOrderPolicy(ctx, orderID, policy)
+}
+
+// addOrderEndorsers: Thêm các MSP (vai trò peer) vào chính sách hiện có của khóa đơn hàng
+func addOrderEndorsers(ctx contractapi.TransactionContextInterface, orderID string, mspIDs ...string) error {
+	policy, err := newOrderPolicy(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := policy.AddOrgs(statebased.RoleTypePeer, mspIDs...); err != nil {
+		return errLedger(ctx, "AddOrgs", err)
+	}
+	return putOrderPolicy(ctx, orderID, policy)
+}
+
+// getOrderEndorsers: Danh sách MSP phải chứng thực thay đổi của đơn (rỗng = chính sách chaincode)
+func getOrderEndorsers(ctx contractapi.TransactionContextInterface, orderID string) ([]string, error) {
+	policy, err := newOrderPolicy(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	mspIDs := policy.ListOrgs()
+	sort.Strings(mspIDs)
+	return mspIDs, nil
+}
+
+// initialOrderEndorsers: Seller sở hữu đơn và (các) tổ chức Sàn trong sổ đăng ký vai trò
+func initialOrderEndorsers(ctx contractapi.TransactionContextInterface, sellerOrg string) ([]string, error) {
+	registry, err := getRoleRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{sellerOrg}, registry.orgsWithRole(RolePlatform)...), nil
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetOrderEndorsers: Các MSP mà client phải gửi đề xuất tới khi thay đổi đơn
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetOrderEndorsers(ctx contractapi.TransactionContextInterface, orderID string) ([]string, error) {
+	order, err := getOrderState(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrderAccess(ctx, order); err != nil {
+		return nil, err
+	}
+	return getOrderEndorsers(ctx, orderID)
+}
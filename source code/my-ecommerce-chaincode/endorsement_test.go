@@ -0,0 +1,98 @@
+// my-ecommerce-chaincode/endorsement_test.go
+
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+)
+
+// orderPolicyOrgs đọc chính sách chứng thực đã commit của khóa đơn trên MockStub
+func orderPolicyOrgs(t *testing.T, sim *Simulator, orderID string) []string {
+	t.Helper()
+	policyBytes, err := sim.stub.GetStateValidationParameter(orderID)
+	if err != nil {
+		t.Fatalf("GetStateValidationParameter(%s): %v", orderID, err)
+	}
+	if policyBytes == nil {
+		t.Fatalf("%s chưa có chính sách chứng thực theo khóa", orderID)
+	}
+	policy, err := statebased.NewStateEP(policyBytes)
+	if err != nil {
+		t.Fatalf("NewStateEP: %v", err)
+	}
+	orgs := policy.ListOrgs()
+	sort.Strings(orgs)
+	return orgs
+}
+
+func newEndorsementSimulator(t *testing.T) *Simulator {
+	t.Helper()
+	sim, err := NewSimulator(time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim
+}
+
+func mustSubmit(t *testing.T, sim *Simulator, as string, function string, args ...string) {
+	t.Helper()
+	if _, err := sim.Submit(as, function, args...); err != nil {
+		t.Fatalf("%s bởi %s: %v", function, as, err)
+	}
+}
+
+func TestCreateOrderSetsSellerAndPlatformPolicy(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	mustSubmit(t, sim, "seller", "CreateOrder", "EP001", "PREPAID", "GHN", "", "", "Shop_ABC", "")
+
+	want := []string{"ECommercePlatformOrgMSP", "SellerOrgMSP"}
+	if got := orderPolicyOrgs(t, sim, "EP001"); !reflect.DeepEqual(got, want) {
+		t.Errorf("chính sách sau CreateOrder = %v, muốn %v", got, want)
+	}
+
+	// Giao dịch của Sàn không đổi chính sách
+	mustSubmit(t, sim, "platform", "ConfirmPayment", "EP001")
+	if got := orderPolicyOrgs(t, sim, "EP001"); !reflect.DeepEqual(got, want) {
+		t.Errorf("chính sách sau ConfirmPayment = %v, muốn %v", got, want)
+	}
+}
+
+func TestShipOrderAddsShipperToPolicy(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	mustSubmit(t, sim, "seller", "CreateOrder", "EP002", "PREPAID", "GHN", "", "", "Shop_ABC", "")
+	mustSubmit(t, sim, "platform", "ConfirmPayment", "EP002")
+	mustSubmit(t, sim, "shipper", "ShipOrder", "EP002", "GHN")
+
+	want := []string{"ECommercePlatformOrgMSP", "SellerOrgMSP", "ShipperOrgMSP"}
+	if got := orderPolicyOrgs(t, sim, "EP002"); !reflect.DeepEqual(got, want) {
+		t.Errorf("chính sách sau ShipOrder = %v, muốn %v", got, want)
+	}
+
+	// GetOrderEndorsers trả về đúng chính sách cho client
+	result, err := sim.Evaluate("seller", "GetOrderEndorsers", "EP002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(result.Payload); got != `["ECommercePlatformOrgMSP","SellerOrgMSP","ShipperOrgMSP"]` {
+		t.Errorf("GetOrderEndorsers = %s", got)
+	}
+}
+
+func TestFailedTransactionKeepsPolicy(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	mustSubmit(t, sim, "seller", "CreateOrder", "EP003", "PREPAID", "GHN", "", "", "Shop_ABC", "")
+
+	// Đơn PREPAID chưa thanh toán: ShipOrder lỗi, chính sách không được thêm Shipper
+	if _, err := sim.Submit("shipper", "ShipOrder", "EP003", "GHN"); err == nil {
+		t.Fatal("ShipOrder khi chưa thanh toán phải lỗi")
+	}
+	want := []string{"ECommercePlatformOrgMSP", "SellerOrgMSP"}
+	if got := orderPolicyOrgs(t, sim, "EP003"); !reflect.DeepEqual(got, want) {
+		t.Errorf("chính sách sau ShipOrder lỗi = %v, muốn %v", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+// my-ecommerce-chaincode/enums.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Status là trạng thái của Order hoặc ReturnCase
+type Status string
+
+const (
+	StatusCreated         Status = "CREATED"
+	StatusPaid            Status = "PAID"
+	StatusShipped         Status = "SHIPPED"
+	StatusDelivered       Status = "DELIVERED"
+	StatusSettled         Status = "SETTLED"
+	StatusCancelled       Status = "CANCELLED"
+	StatusExpired         Status = "EXPIRED"
+	StatusReturnRequested Status = "RETURN_REQUESTED"
+	StatusReturnInTransit Status = "RETURN_IN_TRANSIT"
+	StatusReturned        Status = "RETURNED"
+	StatusReturnReceived  Status = "RETURN_RECEIVED"  // Chỉ dùng cho ReturnCase
+	StatusReturnInspected Status = "RETURN_INSPECTED" // Chỉ dùng cho ReturnCase
+	StatusRefunded        Status = "REFUNDED"         // Chỉ dùng cho ReturnCase
+)
+
+var validStatuses = map[Status]bool{
+	StatusCreated: true, StatusPaid: true, StatusShipped: true, StatusDelivered: true,
+	StatusSettled: true, StatusCancelled: true, StatusExpired: true,
+	StatusReturnRequested: true, StatusReturnInTransit: true, StatusReturned: true,
+	StatusReturnReceived: true, StatusReturnInspected: true, StatusRefunded: true,
+}
+
+// PaymentMethod là phương thức thanh toán của đơn
+type PaymentMethod string
+
+const (
+	PaymentCOD     PaymentMethod = "COD"
+	PaymentPrepaid PaymentMethod = "PREPAID"
+)
+
+var validPaymentMethods = map[PaymentMethod]bool{
+	PaymentCOD:     true,
+	PaymentPrepaid: true,
+}
+
+// CodStatus là trạng thái tiền thu hộ; rỗng với đơn PREPAID
+type CodStatus string
+
+const (
+	CodNone              CodStatus = ""
+	CodNotCollected      CodStatus = "NOT_COLLECTED"
+	CodPendingRemittance CodStatus = "PENDING_REMITTANCE"
+	CodRemitted          CodStatus = "REMITTED"
+)
+
+var validCodStatuses = map[CodStatus]bool{
+	CodNone:              true,
+	CodNotCollected:      true,
+	CodPendingRemittance: true,
+	CodRemitted:          true,
+}
+
+// MarshalJSON từ chối ghi ra giá trị không thuộc tập hợp lệ
+func (s Status) MarshalJSON() ([]byte, error) {
+	if !validStatuses[s] {
+		return nil, fmt.Errorf("trạng thái không hợp lệ: %q", string(s))
+	}
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON từ chối giá trị trạng thái không xác định
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if !validStatuses[Status(value)] {
+		return fmt.Errorf("trạng thái không hợp lệ: %q", value)
+	}
+	*s = Status(value)
+	return nil
+}
+
+// MarshalJSON từ chối ghi ra phương thức thanh toán không xác định
+func (p PaymentMethod) MarshalJSON() ([]byte, error) {
+	if !validPaymentMethods[p] {
+		return nil, fmt.Errorf("phương thức thanh toán không hợp lệ: %q", string(p))
+	}
+	return json.Marshal(string(p))
+}
+
+// UnmarshalJSON từ chối phương thức thanh toán không xác định
+func (p *PaymentMethod) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if !validPaymentMethods[PaymentMethod(value)] {
+		return fmt.Errorf("phương thức thanh toán không hợp lệ: %q", value)
+	}
+	*p = PaymentMethod(value)
+	return nil
+}
+
+// MarshalJSON từ chối ghi ra trạng thái COD không xác định
+func (c CodStatus) MarshalJSON() ([]byte, error) {
+	if !validCodStatuses[c] {
+		return nil, fmt.Errorf("trạng thái COD không hợp lệ: %q", string(c))
+	}
+	return json.Marshal(string(c))
+}
+
+// UnmarshalJSON từ chối trạng thái COD không xác định
+func (c *CodStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if !validCodStatuses[CodStatus(value)] {
+		return fmt.Errorf("trạng thái COD không hợp lệ: %q", value)
+	}
+	*c = CodStatus(value)
+	return nil
+}
@@ -0,0 +1,242 @@
+// my-ecommerce-chaincode/errors.go
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// ErrorCode là mã lỗi ổn định để client xử lý theo mã thay vì so khớp chuỗi
+type ErrorCode string
+
+const (
+	ErrLedger              ErrorCode = "LEDGER_ERROR"
+	ErrIdentity            ErrorCode = "IDENTITY_ERROR"
+	ErrInvalidArgument     ErrorCode = "INVALID_ARGUMENT"
+	ErrOrderNotFound       ErrorCode = "ORDER_NOT_FOUND"
+	ErrOrderAlreadyExists  ErrorCode = "ORDER_ALREADY_EXISTS"
+	ErrReturnNotFound      ErrorCode = "RETURN_NOT_FOUND"
+	ErrReturnAlreadyExists ErrorCode = "RETURN_ALREADY_EXISTS"
+	ErrNothingToReturn     ErrorCode = "NOTHING_TO_RETURN"
+	ErrAccessDenied        ErrorCode = "ACCESS_DENIED"
+	ErrRoleRequired        ErrorCode = "ROLE_REQUIRED"
+	ErrCompanyMismatch     ErrorCode = "COMPANY_MISMATCH"
+	ErrInvalidState        ErrorCode = "INVALID_STATE"
+	ErrPaymentMethod       ErrorCode = "PAYMENT_METHOD_MISMATCH"
+	ErrWindowExpired       ErrorCode = "WINDOW_EXPIRED"
+	ErrWindowNotElapsed    ErrorCode = "WINDOW_NOT_ELAPSED"
+	ErrTrackingOutOfOrder  ErrorCode = "TRACKING_OUT_OF_ORDER"
+	ErrRequestNotFound     ErrorCode = "REQUEST_NOT_FOUND"
+	ErrRequestIDConflict   ErrorCode = "REQUEST_ID_CONFLICT"
+	ErrApprovalNotFound    ErrorCode = "APPROVAL_NOT_FOUND"
+	ErrApprovalPending     ErrorCode = "APPROVAL_PENDING"
+	ErrApprovalExpired     ErrorCode = "APPROVAL_EXPIRED"
+	ErrSelfApproval        ErrorCode = "SELF_APPROVAL"
+	ErrFrozen              ErrorCode = "FROZEN"
+	ErrConfigExists        ErrorCode = "CONFIG_EXISTS"
+)
+
+// Ngôn ngữ của thông báo lỗi được chọn qua transient map, mặc định tiếng Việt
+const (
+	localeTransientKey = "locale"
+	localeVI           = "vi"
+	localeEN           = "en"
+)
+
+// errorCatalog chứa thông báo theo từng ngôn ngữ; {key} được thay bằng giá trị trong details
+var errorCatalog = map[ErrorCode]map[string]string{
+	ErrLedger: {
+		localeVI: "lỗi truy cập sổ cái ({operation}): {cause}",
+		localeEN: "ledger access failed ({operation}): {cause}",
+	},
+	ErrIdentity: {
+		localeVI: "không đọc được định danh người gọi: {cause}",
+		localeEN: "cannot read caller identity: {cause}",
+	},
+	ErrInvalidArgument: {
+		localeVI: "tham số '{field}' không hợp lệ: {constraint}",
+		localeEN: "invalid argument '{field}': {constraint}",
+	},
+	ErrOrderNotFound: {
+		localeVI: "đơn hàng {orderID} không tồn tại",
+		localeEN: "order {orderID} does not exist",
+	},
+	ErrOrderAlreadyExists: {
+		localeVI: "đơn hàng {orderID} đã tồn tại",
+		localeEN: "order {orderID} already exists",
+	},
+	ErrReturnNotFound: {
+		localeVI: "yêu cầu trả hàng {returnID} của đơn {orderID} không tồn tại",
+		localeEN: "return {returnID} of order {orderID} does not exist",
+	},
+	ErrReturnAlreadyExists: {
+		localeVI: "yêu cầu trả hàng {returnID} của đơn {orderID} đã tồn tại",
+		localeEN: "return {returnID} of order {orderID} already exists",
+	},
+	ErrNothingToReturn: {
+		localeVI: "toàn bộ sản phẩm của đơn {orderID} đã nằm trong yêu cầu trả hàng",
+		localeEN: "every item of order {orderID} is already being returned",
+	},
+	ErrAccessDenied: {
+		localeVI: "KHÔNG CÓ QUYỀN: tổ chức '{actorOrg}' không được thực hiện thao tác này (yêu cầu: {requiredOrg})",
+		localeEN: "ACCESS DENIED: organization '{actorOrg}' may not perform this operation (requires: {requiredOrg})",
+	},
+	ErrRoleRequired: {
+		localeVI: "KHÔNG CÓ QUYỀN: cần vai trò '{requiredRole}' (attribute role trong chứng chỉ), người gọi có: '{callerRoles}'",
+		localeEN: "ACCESS DENIED: role '{requiredRole}' is required (certificate attribute role), caller has: '{callerRoles}'",
+	},
+	ErrCompanyMismatch: {
+		localeVI: "KHÔNG CÓ QUYỀN: đơn hàng thuộc '{orderCompanyID}', nhưng mã công ty của bạn là '{callerCompanyID}'",
+		localeEN: "ACCESS DENIED: order belongs to '{orderCompanyID}', but caller company is '{callerCompanyID}'",
+	},
+	ErrInvalidState: {
+		localeVI: "trạng thái không hợp lệ: hiện tại '{currentStatus}', yêu cầu '{requiredStatus}'",
+		localeEN: "invalid state: current '{currentStatus}', required '{requiredStatus}'",
+	},
+	ErrPaymentMethod: {
+		localeVI: "thao tác chỉ áp dụng cho đơn '{requiredPaymentMethod}', đơn này là '{paymentMethod}'",
+		localeEN: "operation only applies to '{requiredPaymentMethod}' orders, this order is '{paymentMethod}'",
+	},
+	ErrWindowExpired: {
+		localeVI: "đã quá thời hạn trả hàng. Hạn chót: {deadline}",
+		localeEN: "the return window has expired. Deadline: {deadline}",
+	},
+	ErrWindowNotElapsed: {
+		localeVI: "chưa hết thời gian chờ kể từ khi giao hàng. Mở khóa lúc: {unlockTime}",
+		localeEN: "the hold period after delivery has not elapsed. Unlocks at: {unlockTime}",
+	},
+	ErrTrackingOutOfOrder: {
+		localeVI: "thời điểm checkpoint {timestamp} không hợp lệ: phải trong khoảng {notBefore} .. {notAfter}",
+		localeEN: "checkpoint time {timestamp} is out of order: must be within {notBefore} .. {notAfter}",
+	},
+	ErrRequestNotFound: {
+		localeVI: "request ID '{requestID}' không tồn tại",
+		localeEN: "request ID '{requestID}' does not exist",
+	},
+	ErrRequestIDConflict: {
+		localeVI: "request ID '{requestID}' đã được dùng cho giao dịch {function} với tham số khác (tx {txID})",
+		localeEN: "request ID '{requestID}' was already used for {function} with different parameters (tx {txID})",
+	},
+	ErrApprovalNotFound: {
+		localeVI: "đề xuất {approvalID} của đơn {orderID} không tồn tại",
+		localeEN: "approval {approvalID} of order {orderID} does not exist",
+	},
+	ErrApprovalPending: {
+		localeVI: "{action} của đơn {orderID} đang chờ duyệt (đề xuất {approvalID}, hết hạn lúc {expiresAt})",
+		localeEN: "{action} of order {orderID} is awaiting approval (approval {approvalID}, expires at {expiresAt})",
+	},
+	ErrApprovalExpired: {
+		localeVI: "đề xuất {approvalID} đã hết hạn lúc {expiresAt}",
+		localeEN: "approval {approvalID} expired at {expiresAt}",
+	},
+	ErrSelfApproval: {
+		localeVI: "người duyệt phải khác người đề xuất {approvalID}",
+		localeEN: "the approver must be a different identity from the proposer of {approvalID}",
+	},
+	ErrFrozen: {
+		localeVI: "thao tác bị chặn: {scope} '{target}' đang bị đóng băng từ {frozenAt} (lý do: {reason})",
+		localeEN: "operation blocked: {scope} '{target}' has been frozen since {frozenAt} (reason: {reason})",
+	},
+	ErrConfigExists: {
+		localeVI: "cấu hình '{section}' đã tồn tại và khác bootstrap; gọi InitLedger với overwrite=true để ghi đè",
+		localeEN: "configuration '{section}' already exists and differs from the bootstrap; call InitLedger with overwrite=true to replace it",
+	},
+}
+
+// errorDetails là thông tin bổ sung của lỗi (trạng thái hiện tại, trạng thái yêu cầu, thời điểm mở khóa...)
+type errorDetails map[string]interface{}
+
+// ContractError là lỗi có cấu trúc, được trả về client dưới dạng JSON
+type ContractError struct {
+	Code    ErrorCode    `json:"code"`
+	Message string       `json:"message"`
+	Details errorDetails `json:"details,omitempty"`
+}
+
+// Error tuần tự hóa lỗi thành JSON: {"code": ..., "message": ..., "details": {...}}
+func (e *ContractError) Error() string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false) // Giữ nguyên "<=", ">" trong thông báo
+	if err := encoder.Encode(e); err != nil {
+		return fmt.Sprintf(`{"code":%q,"message":%q}`, e.Code, e.Message)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// getLocale đọc ngôn ngữ thông báo từ transient map
+func getLocale(ctx contractapi.TransactionContextInterface) string {
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return localeVI
+	}
+	if locale := strings.ToLower(string(transient[localeTransientKey])); locale == localeEN {
+		return localeEN
+	}
+	return localeVI
+}
+
+// newError tạo lỗi có cấu trúc với thông báo theo ngôn ngữ của người gọi
+func newError(ctx contractapi.TransactionContextInterface, code ErrorCode, details errorDetails) *ContractError {
+	template := errorCatalog[code][getLocale(ctx)]
+	for key, value := range details {
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339)
+			details[key] = value
+		}
+		template = strings.ReplaceAll(template, "{"+key+"}", fmt.Sprint(value))
+	}
+	return &ContractError{Code: code, Message: template, Details: details}
+}
+
+// errLedger bọc lỗi đọc/ghi sổ cái hoặc (un)marshal dữ liệu
+func errLedger(ctx contractapi.TransactionContextInterface, operation string, cause error) error {
+	return newError(ctx, ErrLedger, errorDetails{"operation": operation, "cause": cause.Error()})
+}
+
+// errInvalidArgument báo tham số đầu vào không hợp lệ
+func errInvalidArgument(ctx contractapi.TransactionContextInterface, field string, constraint string) error {
+	return newError(ctx, ErrInvalidArgument, errorDetails{"field": field, "constraint": constraint})
+}
+
+// errAccessDenied báo tổ chức gọi không có quyền với thao tác
+func errAccessDenied(ctx contractapi.TransactionContextInterface, actorOrg string, requiredOrg string) error {
+	return newError(ctx, ErrAccessDenied, errorDetails{"actorOrg": actorOrg, "requiredOrg": requiredOrg})
+}
+
+// errCompanyMismatch báo mã công ty của người gọi không khớp với đơn hàng
+func errCompanyMismatch(ctx contractapi.TransactionContextInterface, orderCompanyID string, callerCompanyID string) error {
+	return newError(ctx, ErrCompanyMismatch, errorDetails{"orderCompanyID": orderCompanyID, "callerCompanyID": callerCompanyID})
+}
+
+// errInvalidState báo trạng thái hiện tại không cho phép thao tác
+// (currentStatus/requiredStatus là Status, CodStatus hoặc chuỗi ghép của chúng)
+func errInvalidState(ctx contractapi.TransactionContextInterface, currentStatus interface{}, requiredStatus interface{}) error {
+	return newError(ctx, ErrInvalidState, errorDetails{"currentStatus": currentStatus, "requiredStatus": requiredStatus})
+}
+
+// errPaymentMethod báo thao tác không áp dụng cho phương thức thanh toán của đơn
+func errPaymentMethod(ctx contractapi.TransactionContextInterface, paymentMethod PaymentMethod, requiredPaymentMethod PaymentMethod) error {
+	return newError(ctx, ErrPaymentMethod, errorDetails{"paymentMethod": paymentMethod, "requiredPaymentMethod": requiredPaymentMethod})
+}
+
+// hasErrorCode kiểm tra err có phải ContractError với mã code hay không
+func hasErrorCode(err error, code ErrorCode) bool {
+	contractErr, ok := err.(*ContractError)
+	return ok && contractErr.Code == code
+}
+
+// anyOf ghép nhiều trạng thái hợp lệ để hiển thị trong lỗi, VD: "CREATED|PAID"
+func anyOf(statuses ...Status) string {
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = string(status)
+	}
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,98 @@
+// my-ecommerce-chaincode/events.go
+
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Sự kiện chaincode cho các hệ thống ngoài chuỗi (my-ecommerce-client/cmd/ecom-events).
+// Fabric chỉ giữ MỘT sự kiện cho mỗi giao dịch (SetEvent sau ghi đè SetEvent trước), nên
+// một sự kiện mang danh sách đơn: giao dịch theo lô gọi setOrderEvent một lần sau vòng lặp.
+// Mọi thành viên kênh đều đọc được sự kiện -> không đưa dữ liệu riêng (*_sensitive_data)
+// hay dòng hàng vào payload; bên nhận gọi QueryOrder nếu cần chi tiết.
+const orderEventName = "OrderChanged"
+
+// orderEventVersion tăng khi payload thay đổi không tương thích
+const orderEventVersion = 1
+
+// OrderEvent là payload của sự kiện OrderChanged
+type OrderEvent struct {
+	Version   int               `json:"version"`
+	TxID      string            `json:"txID"`
+	Action    string            `json:"action"` // Tên giao dịch, giống HistoryEntry.Action
+	ActorOrg  string            `json:"actorOrg"`
+	Timestamp time.Time         `json:"timestamp"`
+	Orders    []OrderEventEntry `json:"orders"`
+	Tracking  *TrackingEvent    `json:"tracking,omitempty"` // Chỉ có với AddTrackingEvent
+}
+
+// OrderEventEntry là trạng thái của một đơn sau giao dịch
+type OrderEventEntry struct {
+	OrderID          string        `json:"orderID"`
+	PreviousStatus   Status        `json:"previousStatus,omitempty"` // Rỗng: đơn mới hoặc trạng thái không đổi
+	Status           Status        `json:"status"`
+	PaymentMethod    PaymentMethod `json:"paymentMethod"`
+	CodStatus        CodStatus     `json:"codStatus"`
+	SellerCompanyID  string        `json:"sellerCompanyID"`
+	ShipperCompanyID string        `json:"shipperCompanyID"`
+	TotalAmount      int64         `json:"totalAmount"`
+	RefundedAmount   int64         `json:"refundedAmount"`
+	PayoutAmount     int64         `json:"payoutAmount"`
+	ReturnIDs        []string      `json:"returnIDs,omitempty"`
+}
+
+// newOrderEventEntry tóm tắt đơn cho sự kiện; previous là trạng thái đọc từ sổ cái
+func newOrderEventEntry(order *Order, previous Status) OrderEventEntry {
+	if previous == order.Status {
+		previous = ""
+	}
+	return OrderEventEntry{
+		OrderID:          order.OrderID,
+		PreviousStatus:   previous,
+		Status:           order.Status,
+		PaymentMethod:    order.PaymentMethod,
+		CodStatus:        order.CodStatus,
+		SellerCompanyID:  order.SellerCompanyID,
+		ShipperCompanyID: order.ShipperCompanyID,
+		TotalAmount:      order.TotalAmount,
+		RefundedAmount:   order.RefundedAmount,
+		PayoutAmount:     order.PayoutAmount,
+		ReturnIDs:        order.ReturnIDs,
+	}
+}
+
+// newOrderEvent tạo sự kiện từ dòng lịch sử mà giao dịch hiện tại vừa thêm vào đơn.
+// Trả về nil nếu giao dịch không ghi lịch sử (VD: MigrateOrders chỉ đổi lược đồ).
+func newOrderEvent(ctx contractapi.TransactionContextInterface, order *Order, previous Status) *OrderEvent {
+	if len(order.History) == 0 {
+		return nil
+	}
+	last := order.History[len(order.History)-1]
+	if last.TxID != ctx.GetStub().GetTxID() {
+		return nil
+	}
+	return &OrderEvent{
+		Version:   orderEventVersion,
+		TxID:      last.TxID,
+		Action:    last.Action,
+		ActorOrg:  last.ActorOrg,
+		Timestamp: last.Timestamp,
+		Orders:    []OrderEventEntry{newOrderEventEntry(order, previous)},
+	}
+}
+
+// setOrderEvent ghi sự kiện OrderChanged cho giao dịch hiện tại (ghi đè sự kiện trước đó)
+func setOrderEvent(ctx contractapi.TransactionContextInterface, event *OrderEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	if err := ctx.GetStub().SetEvent(orderEventName, payload); err != nil {
+		return errLedger(ctx, "SetEvent", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// my-ecommerce-chaincode/expiry.go
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Chỉ mục (status, createdAt) giúp quét đơn theo trạng thái mà không cần full scan.
+// Khóa: StatusIndex~status~createdAt~orderID, được duy trì trong saveOrderState.
+const statusIndexObjectType = "StatusIndex"
+
+// Chỉ mục riêng cho đơn PREPAID đang chờ thanh toán (CREATED), để ExpireUnpaidOrders không
+// phải đọc qua các đơn COD cũng đang ở CREATED. Khóa: UnpaidIndex~createdAt~orderID.
+// Đơn lưu trước khi có chỉ mục này được bổ sung khi lưu lại (VD: MigrateOrders).
+const unpaidIndexObjectType = "UnpaidIndex"
+
+// indexTimeLayout có độ dài cố định (UTC) để thứ tự khóa trùng với thứ tự thời gian
+const indexTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// maxExpireBatch giới hạn số đơn xử lý trong một giao dịch quét
+const maxExpireBatch = 500
+
+// statusIndexKey tạo khóa chỉ mục cho một trạng thái của đơn
+func statusIndexKey(ctx contractapi.TransactionContextInterface, status Status, order *Order) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(statusIndexObjectType, []string{
+		string(status),
+		order.CreatedAt.UTC().Format(indexTimeLayout),
+		order.OrderID,
+	})
+}
+
+// unpaidIndexKey tạo khóa chỉ mục đơn PREPAID chờ thanh toán
+func unpaidIndexKey(ctx contractapi.TransactionContextInterface, order *Order) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(unpaidIndexObjectType, []string{
+		order.CreatedAt.UTC().Format(indexTimeLayout),
+		order.OrderID,
+	})
+}
+
+// updateStatusIndex: Chuyển khóa chỉ mục từ trạng thái đã đọc sang trạng thái hiện tại của đơn
+// (kèm bộ đếm số đơn theo trạng thái, xem counters.go)
+func updateStatusIndex(ctx contractapi.TransactionContextInterface, order *Order) error {
+	if err := recordStatusChange(ctx, order.indexedStatus, order.Status, order.OrderID); err != nil {
+		return err
+	}
+	if order.indexedStatus != "" && order.indexedStatus != order.Status {
+		oldKey, err := statusIndexKey(ctx, order.indexedStatus, order)
+		if err != nil {
+			return errLedger(ctx, "CreateCompositeKey", err)
+		}
+		if err := ctx.GetStub().DelState(oldKey); err != nil {
+			return errLedger(ctx, "DelState", err)
+		}
+	}
+
+	// Luôn ghi lại khóa hiện tại để các đơn cũ (chưa có chỉ mục) được bổ sung khi lưu lại
+	newKey, err := statusIndexKey(ctx, order.Status, order)
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	if err := ctx.GetStub().PutState(newKey, []byte{0x00}); err != nil {
+		return errLedger(ctx, "PutState", err)
+	}
+	if err := updateUnpaidIndex(ctx, order); err != nil {
+		return err
+	}
+	order.indexedStatus = order.Status
+	return nil
+}
+
+// updateUnpaidIndex: Đơn PREPAID có khóa UnpaidIndex khi ở CREATED và bị xóa khóa khi rời CREATED
+func updateUnpaidIndex(ctx contractapi.TransactionContextInterface, order *Order) error {
+	if order.PaymentMethod != PaymentPrepaid {
+		return nil
+	}
+	key, err := unpaidIndexKey(ctx, order)
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	switch {
+	case order.Status == StatusCreated:
+		if err := ctx.GetStub().PutState(key, []byte{0x00}); err != nil {
+			return errLedger(ctx, "PutState", err)
+		}
+	case order.indexedStatus == StatusCreated:
+		if err := ctx.GetStub().DelState(key); err != nil {
+			return errLedger(ctx, "DelState", err)
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] ExpireUnpaidOrders: Sàn quét các đơn PREPAID quá hạn thanh toán (CREATED -> EXPIRED)
+// olderThan (RFC3339, có thể rỗng): chỉ xét đơn tạo trước mốc này; luôn kết hợp với
+// hạn thanh toán trong PolicyConfig. limit giới hạn số khóa chỉ mục được đọc (kể cả đơn bị
+// bỏ qua vì đang đóng băng). Trả về danh sách orderID đã chuyển sang EXPIRED.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) ExpireUnpaidOrders(ctx contractapi.TransactionContextInterface, olderThan string, limit int) ([]string, error) {
+	if prior, err := checkClientRequest(ctx); err != nil {
+		return nil, err
+	} else if prior != nil {
+		expired := []string{}
+		return expired, prior.decodeResult(ctx, &expired)
+	}
+
+	// 1. Kiểm tra ACL
+	actorOrg, err := requireRole(ctx, RolePlatform)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxExpireBatch {
+		return nil, errInvalidArgument(ctx, "limit", fmt.Sprintf("1..%d", maxExpireBatch))
+	}
+
+	// 2. Xác định mốc thời gian quá hạn
+	txTime, err := getTimeNow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config, err := getPolicyConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := txTime.Add(-time.Duration(config.PaymentDeadlineSeconds) * time.Second)
+	if olderThan != "" {
+		olderThanTime, err := time.Parse(time.RFC3339, olderThan)
+		if err != nil {
+			return nil, errInvalidArgument(ctx, "olderThan", "RFC3339")
+		}
+		if olderThanTime.Before(cutoff) {
+			cutoff = olderThanTime
+		}
+	}
+
+	// 3. Đọc chỉ mục đơn PREPAID chờ thanh toán theo thứ tự createdAt tăng dần
+	// (đơn COD ở CREATED đang chờ giao, không thuộc diện quá hạn thanh toán nên không có trong chỉ mục)
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(unpaidIndexObjectType, []string{})
+	if err != nil {
+		return nil, errLedger(ctx, "GetStateByPartialCompositeKey", err)
+	}
+	var candidates []*Order
+	for visited := 0; visited < limit && resultsIterator.HasNext(); visited++ {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			resultsIterator.Close()
+			return nil, err
+		}
+		_, attrs, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil || len(attrs) != 2 {
+			resultsIterator.Close()
+			return nil, errLedger(ctx, "SplitCompositeKey", fmt.Errorf("khóa chỉ mục không hợp lệ: %q", queryResponse.Key))
+		}
+		createdAt, err := time.Parse(indexTimeLayout, attrs[0])
+		if err != nil {
+			resultsIterator.Close()
+			return nil, errLedger(ctx, "SplitCompositeKey", err)
+		}
+		if !createdAt.Before(cutoff) {
+			break
+		}
+		order, err := getOrderState(ctx, attrs[1])
+		if err != nil {
+			resultsIterator.Close()
+			return nil, err
+		}
+		if order.PaymentMethod != PaymentPrepaid || order.Status != StatusCreated {
+			continue
+		}
+		// Đơn đang bị đóng băng được bỏ qua thay vì làm hỏng cả lượt quét
+		freeze, err := getOrderFreeze(ctx, order)
+		if err != nil {
+			resultsIterator.Close()
+			return nil, err
+		}
+		if freeze == nil {
+			candidates = append(candidates, order)
+		}
+	}
+	resultsIterator.Close()
+
+	// 4. Chuyển trạng thái sau khi đóng iterator
+	expired := []string{}
+	var event *OrderEvent
+	for _, order := range candidates {
+		order.Status = StatusExpired
+		order.UpdatedAt = txTime
+		order.History = append(order.History, newHistoryEntry(ctx, "ExpireUnpaidOrders", actorOrg, txTime))
+		if err := saveOrderState(ctx, order); err != nil {
+			return nil, err
+		}
+		expired = append(expired, order.OrderID)
+		if event == nil {
+			event = newOrderEvent(ctx, order, StatusCreated)
+		} else {
+			event.Orders = append(event.Orders, newOrderEventEntry(order, StatusCreated))
+		}
+	}
+
+	// 5. Một giao dịch chỉ giữ một sự kiện: gộp mọi đơn đã hết hạn
+	if event != nil {
+		if err := setOrderEvent(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return expired, recordClientResult(ctx, expired)
+}
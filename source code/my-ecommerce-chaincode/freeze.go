@@ -0,0 +1,363 @@
+// my-ecommerce-chaincode/freeze.go
+
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Đóng băng khẩn cấp (nghi gian lận, tranh chấp, sự cố): Sàn khóa ghi một đơn hàng,
+// toàn bộ đơn của một Shop / hãng vận chuyển, hoặc mọi giao dịch ghi của hợp đồng.
+// Bản ghi đóng băng đang hiệu lực lưu theo khóa Freeze~scope~target và bị xóa khi gỡ;
+// mọi lần đóng băng / gỡ được ghi lại vĩnh viễn theo khóa FreezeLog~scope~target~txID.
+const (
+	freezeObjectType    = "Freeze"
+	freezeLogObjectType = "FreezeLog"
+)
+
+// FreezeScope là phạm vi của một lệnh đóng băng
+type FreezeScope string
+
+const (
+	FreezeOrder          FreezeScope = "ORDER"           // Một đơn hàng (target = orderID)
+	FreezeSellerCompany  FreezeScope = "SELLER_COMPANY"  // Mọi đơn của một Shop (target = sellerCompanyID)
+	FreezeShipperCompany FreezeScope = "SHIPPER_COMPANY" // Mọi đơn của một hãng (target = shipperCompanyID)
+	FreezeGlobal         FreezeScope = "GLOBAL"          // Mọi giao dịch ghi (target = "*")
+)
+
+var validFreezeScopes = map[FreezeScope]bool{
+	FreezeOrder:          true,
+	FreezeSellerCompany:  true,
+	FreezeShipperCompany: true,
+	FreezeGlobal:         true,
+}
+
+// globalFreezeTarget là target cố định của phạm vi GLOBAL
+const globalFreezeTarget = "*"
+
+// Hành động trong nhật ký đóng băng
+const (
+	freezeActionFreeze   = "FREEZE"
+	freezeActionUnfreeze = "UNFREEZE"
+)
+
+// freezeExemptFunctions vẫn được chạy khi hợp đồng bị đóng băng toàn cục.
+// InitLedger bắt buộc sau mỗi lần nâng cấp (--init-required), chặn nó thì không gỡ được đóng băng.
+// RebuildOrderCounters chỉ ghi bộ đếm và cần đóng băng để đếm qua nhiều lượt.
+var freezeExemptFunctions = map[string]bool{
+	"Freeze":               true,
+	"Unfreeze":             true,
+	"InitLedger":           true,
+	"RebuildOrderCounters": true,
+}
+
+// FreezeRecord là một lệnh đóng băng đang có hiệu lực
+type FreezeRecord struct {
+	DocType  string      `json:"docType"`
+	Scope    FreezeScope `json:"scope"`
+	Target   string      `json:"target"`
+	Reason   string      `json:"reason"`
+	ActorOrg string      `json:"actorOrg"`
+	ActorID  string      `json:"actorID"`
+	TxID     string      `json:"txID"`
+	FrozenAt time.Time   `json:"frozenAt"`
+}
+
+// FreezeLogEntry là một dòng nhật ký đóng băng / gỡ đóng băng
+type FreezeLogEntry struct {
+	DocType   string      `json:"docType"`
+	Action    string      `json:"action"` // FREEZE | UNFREEZE
+	Scope     FreezeScope `json:"scope"`
+	Target    string      `json:"target"`
+	Reason    string      `json:"reason"`
+	ActorOrg  string      `json:"actorOrg"`
+	ActorID   string      `json:"actorID"`
+	TxID      string      `json:"txID"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// normalizeFreezeTarget kiểm tra target theo phạm vi; GLOBAL luôn dùng "*"
+func normalizeFreezeTarget(ctx contractapi.TransactionContextInterface, scope FreezeScope, target string) (string, error) {
+	switch scope {
+	case FreezeGlobal:
+		if target != "" && target != globalFreezeTarget {
+			return "", errInvalidArgument(ctx, "target", `"" or "*" for GLOBAL`)
+		}
+		return globalFreezeTarget, nil
+	case FreezeOrder:
+		if !idPattern.MatchString(target) {
+			return "", errInvalidArgument(ctx, "target", idPattern.String())
+		}
+	default:
+		if !companyIDPattern.MatchString(target) {
+			return "", errInvalidArgument(ctx, "target", companyIDPattern.String())
+		}
+	}
+	return target, nil
+}
+
+// getFreeze: Đọc lệnh đóng băng đang hiệu lực, nil nếu không có
+func getFreeze(ctx contractapi.TransactionContextInterface, scope FreezeScope, target string) (*FreezeRecord, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(freezeObjectType, []string{string(scope), target})
+	if err != nil {
+		return nil, errLedger(ctx, "CreateCompositeKey", err)
+	}
+	freezeJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, errLedger(ctx, "GetState", err)
+	}
+	if freezeJSON == nil {
+		return nil, nil
+	}
+
+	var record FreezeRecord
+	if err := json.Unmarshal(freezeJSON, &record); err != nil {
+		return nil, errLedger(ctx, "unmarshal", err)
+	}
+	return &record, nil
+}
+
+// errFrozen báo thao tác bị chặn bởi lệnh đóng băng
+func errFrozen(ctx contractapi.TransactionContextInterface, record *FreezeRecord) error {
+	return newError(ctx, ErrFrozen, errorDetails{
+		"scope": record.Scope, "target": record.Target, "reason": record.Reason, "frozenAt": record.FrozenAt,
+	})
+}
+
+// getOrderFreeze trả về lệnh đóng băng đầu tiên áp dụng cho đơn (toàn cục, đơn, Shop, hãng), nil nếu không có
+func getOrderFreeze(ctx contractapi.TransactionContextInterface, order *Order) (*FreezeRecord, error) {
+	scopes := []struct {
+		scope  FreezeScope
+		target string
+	}{
+		{FreezeGlobal, globalFreezeTarget},
+		{FreezeOrder, order.OrderID},
+		{FreezeSellerCompany, order.SellerCompanyID},
+		{FreezeShipperCompany, order.ShipperCompanyID},
+	}
+	for _, s := range scopes {
+		if s.target == "" {
+			continue
+		}
+		record, err := getFreeze(ctx, s.scope, s.target)
+		if err != nil || record != nil {
+			return record, err
+		}
+	}
+	return nil, nil
+}
+
+// checkOrderNotFrozen được gọi trên mọi đường ghi dữ liệu của đơn (saveOrderState, tracking, đề xuất duyệt)
+func checkOrderNotFrozen(ctx contractapi.TransactionContextInterface, order *Order) error {
+	record, err := getOrderFreeze(ctx, order)
+	if err != nil {
+		return err
+	}
+	if record != nil {
+		return errFrozen(ctx, record)
+	}
+	return nil
+}
+
+// checkGlobalFreeze chặn mọi giao dịch ghi (có trong transactionPermissions) khi hợp đồng bị đóng băng toàn cục
+func checkGlobalFreeze(ctx contractapi.TransactionContextInterface) error {
+	function, _ := currentFunction(ctx)
+	if _, mutating := transactionPermissions[function]; !mutating || freezeExemptFunctions[function] {
+		return nil
+	}
+	record, err := getFreeze(ctx, FreezeGlobal, globalFreezeTarget)
+	if err != nil {
+		return err
+	}
+	if record != nil {
+		return errFrozen(ctx, record)
+	}
+	return nil
+}
+
+// appendFreezeLog ghi một dòng nhật ký cho lệnh đóng băng / gỡ đóng băng
+func appendFreezeLog(ctx contractapi.TransactionContextInterface, entry *FreezeLogEntry) error {
+	key, err := ctx.GetStub().CreateCompositeKey(freezeLogObjectType, []string{string(entry.Scope), entry.Target, entry.TxID})
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	return ctx.GetStub().PutState(key, entryJSON)
+}
+
+// changeFreeze: Phần chung của Freeze / Unfreeze - kiểm tra quyền, ghi hoặc xóa bản ghi, ghi nhật ký
+func changeFreeze(ctx contractapi.TransactionContextInterface, action string, scope FreezeScope, target string, reason string) error {
+	// 1. Kiểm tra ACL: chỉ Sàn
+	actorOrg, err := requireRole(ctx, RolePlatform)
+	if err != nil {
+		return err
+	}
+	actorID, err := getCallerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 2. Kiểm tra đầu vào và trạng thái hiện tại
+	target, err = normalizeFreezeTarget(ctx, scope, target)
+	if err != nil {
+		return err
+	}
+	current, err := getFreeze(ctx, scope, target)
+	if err != nil {
+		return err
+	}
+	key, err := ctx.GetStub().CreateCompositeKey(freezeObjectType, []string{string(scope), target})
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	txTime, err := getTimeNow(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 3. Ghi / xóa bản ghi đóng băng
+	if action == freezeActionFreeze {
+		if current != nil {
+			return errInvalidState(ctx, "FROZEN", "NOT_FROZEN")
+		}
+		if scope == FreezeOrder {
+			if _, err := getOrderState(ctx, target); err != nil {
+				return err
+			}
+		}
+		record := FreezeRecord{
+			DocType:  freezeObjectType,
+			Scope:    scope,
+			Target:   target,
+			Reason:   reason,
+			ActorOrg: actorOrg,
+			ActorID:  actorID,
+			TxID:     ctx.GetStub().GetTxID(),
+			FrozenAt: txTime,
+		}
+		recordJSON, err := json.Marshal(record)
+		if err != nil {
+			return errLedger(ctx, "marshal", err)
+		}
+		if err := ctx.GetStub().PutState(key, recordJSON); err != nil {
+			return errLedger(ctx, "PutState", err)
+		}
+	} else {
+		if current == nil {
+			return errInvalidState(ctx, "NOT_FROZEN", "FROZEN")
+		}
+		if err := ctx.GetStub().DelState(key); err != nil {
+			return errLedger(ctx, "DelState", err)
+		}
+	}
+
+	// 4. Ghi nhật ký
+	return appendFreezeLog(ctx, &FreezeLogEntry{
+		DocType:   freezeLogObjectType,
+		Action:    action,
+		Scope:     scope,
+		Target:    target,
+		Reason:    reason,
+		ActorOrg:  actorOrg,
+		ActorID:   actorID,
+		TxID:      ctx.GetStub().GetTxID(),
+		Timestamp: txTime,
+	})
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] Freeze: Sàn đóng băng một đơn (ORDER), mọi đơn của một Shop (SELLER_COMPANY)
+// hoặc hãng vận chuyển (SHIPPER_COMPANY), hoặc mọi giao dịch ghi (GLOBAL, target rỗng).
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) Freeze(ctx contractapi.TransactionContextInterface, scope string, target string, reason string) error {
+	if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+		return err
+	}
+	return changeFreeze(ctx, freezeActionFreeze, FreezeScope(scope), target, reason)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] Unfreeze: Sàn gỡ một lệnh đóng băng đang có hiệu lực (lý do được ghi vào nhật ký)
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) Unfreeze(ctx contractapi.TransactionContextInterface, scope string, target string, reason string) error {
+	if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+		return err
+	}
+	return changeFreeze(ctx, freezeActionUnfreeze, FreezeScope(scope), target, reason)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetFreezes: Các lệnh đóng băng đang có hiệu lực
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetFreezes(ctx contractapi.TransactionContextInterface) ([]*FreezeRecord, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(freezeObjectType, []string{})
+	if err != nil {
+		return nil, errLedger(ctx, "GetStateByPartialCompositeKey", err)
+	}
+	defer iterator.Close()
+
+	records := []*FreezeRecord{}
+	for iterator.HasNext() {
+		item, err := iterator.Next()
+		if err != nil {
+			return nil, errLedger(ctx, "iterator.Next", err)
+		}
+		var record FreezeRecord
+		if err := json.Unmarshal(item.Value, &record); err != nil {
+			return nil, errLedger(ctx, "unmarshal", err)
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetFreezeLog: Sàn xem nhật ký đóng băng theo thứ tự thời gian.
+// scope / target rỗng: lấy tất cả (target chỉ dùng được khi có scope).
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetFreezeLog(ctx contractapi.TransactionContextInterface, scope string, target string) ([]*FreezeLogEntry, error) {
+	if _, err := requireRole(ctx, RolePlatform); err != nil {
+		return nil, err
+	}
+	attributes := []string{}
+	if scope != "" {
+		attributes = append(attributes, scope)
+		if FreezeScope(scope) == FreezeGlobal {
+			target = globalFreezeTarget
+		}
+		if target != "" {
+			attributes = append(attributes, target)
+		}
+	} else if target != "" {
+		return nil, errInvalidArgument(ctx, "scope", "required when target is set")
+	}
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(freezeLogObjectType, attributes)
+	if err != nil {
+		return nil, errLedger(ctx, "GetStateByPartialCompositeKey", err)
+	}
+	defer iterator.Close()
+
+	entries := []*FreezeLogEntry{}
+	for iterator.HasNext() {
+		item, err := iterator.Next()
+		if err != nil {
+			return nil, errLedger(ctx, "iterator.Next", err)
+		}
+		var entry FreezeLogEntry
+		if err := json.Unmarshal(item.Value, &entry); err != nil {
+			return nil, errLedger(ctx, "unmarshal", err)
+		}
+		entries = append(entries, &entry)
+	}
+	// Khóa xếp theo txID nên cần sắp xếp lại theo thời gian
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	return entries, nil
+}
@@ -0,0 +1,197 @@
+// my-ecommerce-chaincode/idempotency.go
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Client (Odoo, Medusa...) có thể gửi kèm request ID qua transient map để giao dịch
+// ghi dữ liệu được xử lý đúng một lần: gửi lại cùng ID và cùng tham số sẽ nhận lại kết quả cũ.
+// Bản ghi lưu theo khóa ClientRequest~mspID~requestID (mỗi tổ chức một không gian ID).
+const (
+	clientRequestObjectType = "ClientRequest"
+	requestIDTransientKey   = "requestID"
+	maxRequestIDLength      = 128
+)
+
+// ClientRequest lưu kết quả của một giao dịch đã xử lý theo request ID của client
+type ClientRequest struct {
+	RequestID  string    `json:"requestID"`
+	ActorOrg   string    `json:"actorOrg"`
+	Function   string    `json:"function"`
+	ParamsHash string    `json:"paramsHash"` // SHA-256 của tên hàm + tham số
+	TxID       string    `json:"txID"`       // Giao dịch gốc đã xử lý request
+	Result     string    `json:"result,omitempty" metadata:",optional"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// getRequestID đọc request ID từ transient map (rỗng nếu client không gửi)
+func getRequestID(ctx contractapi.TransactionContextInterface) (string, error) {
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return "", errLedger(ctx, "GetTransient", err)
+	}
+	requestID := string(transient[requestIDTransientKey])
+	if len(requestID) > maxRequestIDLength {
+		return "", errInvalidArgument(ctx, requestIDTransientKey, fmt.Sprintf("<= %d characters", maxRequestIDLength))
+	}
+	return requestID, nil
+}
+
+// clientRequestKey tạo khóa lưu bản ghi request ID của tổ chức gọi
+func clientRequestKey(ctx contractapi.TransactionContextInterface, requestID string) (string, error) {
+	actorOrg, err := getActorOrg(ctx)
+	if err != nil {
+		return "", err
+	}
+	return ctx.GetStub().CreateCompositeKey(clientRequestObjectType, []string{actorOrg, requestID})
+}
+
+// requestFingerprint trả về tên hàm (bỏ tiền tố contract) và hash của tham số giao dịch
+func requestFingerprint(ctx contractapi.TransactionContextInterface) (string, string) {
+	function, params := currentFunction(ctx)
+
+	hash := sha256.New()
+	for _, part := range append([]string{function}, params...) {
+		// Tiền tố độ dài để ("ab","c") và ("a","bc") cho hash khác nhau
+		fmt.Fprintf(hash, "%d:%s;", len(part), part)
+	}
+	return function, hex.EncodeToString(hash.Sum(nil))
+}
+
+// getClientRequest: Đọc bản ghi request ID, nil nếu chưa tồn tại
+func getClientRequest(ctx contractapi.TransactionContextInterface, requestID string) (*ClientRequest, error) {
+	key, err := clientRequestKey(ctx, requestID)
+	if err != nil {
+		return nil, errLedger(ctx, "CreateCompositeKey", err)
+	}
+	requestJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, errLedger(ctx, "GetState", err)
+	}
+	if requestJSON == nil {
+		return nil, nil
+	}
+	var request ClientRequest
+	if err := json.Unmarshal(requestJSON, &request); err != nil {
+		return nil, errLedger(ctx, "unmarshal", err)
+	}
+	return &request, nil
+}
+
+// saveClientRequest: Lưu bản ghi request ID
+func saveClientRequest(ctx contractapi.TransactionContextInterface, request *ClientRequest) error {
+	key, err := clientRequestKey(ctx, request.RequestID)
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	return ctx.GetStub().PutState(key, requestJSON)
+}
+
+// checkClientRequest được gọi đầu mỗi giao dịch ghi dữ liệu, trước mọi kiểm tra khác, để gửi lại
+// một request đã thành công trả về kết quả gốc thay vì lỗi (VD: INVALID_STATE vì đơn đã đổi trạng thái):
+//   - không có request ID: trả về nil, giao dịch chạy bình thường
+//   - request ID mới: ghi nhận (cùng write set với giao dịch, nên chỉ tồn tại nếu giao dịch thành công)
+//   - request ID đã xử lý với cùng tham số: trả về bản ghi cũ; hàm có kết quả giải mã lại bằng
+//     prior.decodeResult (kết quả được lưu bởi recordClientResult)
+//   - request ID đã dùng cho tham số khác: lỗi REQUEST_ID_CONFLICT
+func checkClientRequest(ctx contractapi.TransactionContextInterface) (*ClientRequest, error) {
+	requestID, err := getRequestID(ctx)
+	if err != nil || requestID == "" {
+		return nil, err
+	}
+
+	function, paramsHash := requestFingerprint(ctx)
+	prior, err := getClientRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if prior != nil {
+		if prior.Function != function || prior.ParamsHash != paramsHash {
+			return nil, newError(ctx, ErrRequestIDConflict, errorDetails{"requestID": requestID, "function": prior.Function, "txID": prior.TxID})
+		}
+		return prior, nil
+	}
+
+	request, err := newClientRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return nil, saveClientRequest(ctx, request)
+}
+
+// newClientRequest tạo bản ghi request ID cho giao dịch hiện tại
+func newClientRequest(ctx contractapi.TransactionContextInterface, requestID string) (*ClientRequest, error) {
+	actorOrg, err := getActorOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txTime, err := getTimeNow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	function, paramsHash := requestFingerprint(ctx)
+	return &ClientRequest{
+		RequestID:  requestID,
+		ActorOrg:   actorOrg,
+		Function:   function,
+		ParamsHash: paramsHash,
+		TxID:       ctx.GetStub().GetTxID(),
+		CreatedAt:  txTime,
+	}, nil
+}
+
+// recordClientResult lưu kết quả trả về của giao dịch vào bản ghi request ID (nếu có).
+// Bản ghi được tạo lại thay vì đọc, vì GetState không thấy dữ liệu ghi trong cùng giao dịch.
+func recordClientResult(ctx contractapi.TransactionContextInterface, result interface{}) error {
+	requestID, err := getRequestID(ctx)
+	if err != nil || requestID == "" {
+		return err
+	}
+	request, err := newClientRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	request.Result = string(resultJSON)
+	return saveClientRequest(ctx, request)
+}
+
+// decodeResult giải mã kết quả đã lưu của một request vào out
+func (r *ClientRequest) decodeResult(ctx contractapi.TransactionContextInterface, out interface{}) error {
+	if r.Result == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(r.Result), out); err != nil {
+		return errLedger(ctx, "unmarshal", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetClientRequest: Client tra cứu kết quả của một request ID đã gửi
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetClientRequest(ctx contractapi.TransactionContextInterface, requestID string) (*ClientRequest, error) {
+	request, err := getClientRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, newError(ctx, ErrRequestNotFound, errorDetails{"requestID": requestID})
+	}
+	return request, nil
+}
@@ -0,0 +1,377 @@
+// my-ecommerce-chaincode/import.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Nạp đơn lịch sử của Seller mới gia nhập: mỗi đơn mang đủ chủ sở hữu, dòng hàng và chuỗi
+// trạng thái đã qua kèm thời điểm. Chuỗi trạng thái được kiểm tra theo đúng máy trạng thái
+// của các giao dịch gốc (importTransitions); lịch sử của đơn được dựng lại với tên giao dịch
+// gốc và thời điểm lịch sử, thêm một dòng ImportOrders cho giao dịch nạp.
+// Đơn nạp được đánh dấu imported: true. Chưa hỗ trợ nạp đơn đang trả hàng (không có ReturnCase).
+
+// maxImportBatch giới hạn số đơn trong một giao dịch ImportOrders
+const maxImportBatch = 100
+
+// ImportOrder là một đơn lịch sử trong lô của ImportOrders
+type ImportOrder struct {
+	OrderID          string         `json:"orderID"`
+	PaymentMethod    PaymentMethod  `json:"paymentMethod"`
+	SellerID         string         `json:"sellerID"` // MSP của Seller sở hữu đơn
+	SellerCompanyID  string         `json:"sellerCompanyID"`
+	ShipperCompanyID string         `json:"shipperCompanyID"`
+	SellerDataBlob   string         `json:"sellerDataBlob"`
+	ShipperDataBlob  string         `json:"shipperDataBlob"`
+	Lines            []OrderLine    `json:"lines"`
+	Statuses         []ImportStatus `json:"statuses"`      // Bắt đầu bằng CREATED, theo thứ tự thời gian
+	CodRemittedAt    *time.Time     `json:"codRemittedAt"` // COD: thời điểm nộp tiền thu hộ (bắt buộc khi SETTLED)
+	PayoutAmount     *int64         `json:"payoutAmount"`  // SETTLED: số tiền đã trả Seller (mặc định theo biểu phí hiện hành)
+}
+
+// ImportStatus là một trạng thái mà đơn đã qua
+type ImportStatus struct {
+	Status Status    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// ImportResult là kết quả của một lô: đơn lỗi không làm hỏng cả lô
+type ImportResult struct {
+	Imported []string        `json:"imported"`
+	Failed   []ImportFailure `json:"failed,omitempty" metadata:",optional"`
+}
+
+// ImportFailure là một đơn không được nạp và lý do (mã lỗi như lỗi của giao dịch)
+type ImportFailure struct {
+	Index   int       `json:"index"` // Vị trí trong lô
+	OrderID string    `json:"orderID"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// importTransition là một bước chuyển trạng thái hợp lệ và giao dịch gốc tương ứng
+type importTransition struct {
+	from   Status
+	to     Status
+	method PaymentMethod // Rỗng = mọi phương thức thanh toán
+	action string
+	actor  OrgRole // Tổ chức thực hiện giao dịch gốc
+}
+
+// importTransitions: máy trạng thái của đơn (smartcontract.go, expiry.go), không gồm trả hàng
+var importTransitions = []importTransition{
+	{StatusCreated, StatusPaid, PaymentPrepaid, "ConfirmPayment", RolePlatform},
+	{StatusCreated, StatusShipped, PaymentCOD, "ShipOrder", RoleShipper},
+	{StatusCreated, StatusCancelled, "", "CancelOrder", RolePlatform},
+	{StatusCreated, StatusExpired, PaymentPrepaid, "ExpireUnpaidOrders", RolePlatform},
+	{StatusPaid, StatusShipped, PaymentPrepaid, "ShipOrder", RoleShipper},
+	{StatusPaid, StatusCancelled, PaymentPrepaid, "CancelOrder", RolePlatform},
+	{StatusShipped, StatusDelivered, PaymentPrepaid, "ConfirmDelivery", RoleShipper},
+	{StatusShipped, StatusDelivered, PaymentCOD, "ConfirmCODDelivery", RoleShipper},
+	{StatusDelivered, StatusSettled, "", "PayoutToSeller", RolePlatform},
+}
+
+// findImportTransition tìm bước from -> to cho phương thức thanh toán; trả về kèm các trạng
+// thái kế tiếp hợp lệ để báo lỗi
+func findImportTransition(from Status, to Status, method PaymentMethod) (*importTransition, []Status) {
+	var next []Status
+	for i := range importTransitions {
+		transition := &importTransitions[i]
+		if transition.from != from || (transition.method != "" && transition.method != method) {
+			continue
+		}
+		if transition.to == to {
+			return transition, nil
+		}
+		next = append(next, transition.to)
+	}
+	return nil, next
+}
+
+// buildImportedOrder kiểm tra một đơn lịch sử và dựng Order tương ứng. Lỗi trả về là lỗi của
+// riêng đơn này (trừ ErrLedger).
+func buildImportedOrder(ctx contractapi.TransactionContextInterface, item *ImportOrder, actorOrg string, txTime time.Time, fees *FeeSchedule) (*Order, error) {
+	// 1. Định dạng (cùng quy tắc với CreateOrder trong validation.go)
+	if !idPattern.MatchString(item.OrderID) {
+		return nil, errInvalidArgument(ctx, "orderID", idPattern.String())
+	}
+	if !validPaymentMethods[item.PaymentMethod] {
+		return nil, errInvalidArgument(ctx, "paymentMethod", paymentMethodCheck(string(item.PaymentMethod)))
+	}
+	if !companyIDPattern.MatchString(item.ShipperCompanyID) {
+		return nil, errInvalidArgument(ctx, "shipperCompanyID", companyIDPattern.String())
+	}
+	if item.SellerCompanyID != "" && !companyIDPattern.MatchString(item.SellerCompanyID) {
+		return nil, errInvalidArgument(ctx, "sellerCompanyID", companyIDPattern.String())
+	}
+	if len(item.SellerDataBlob) > maxBlobSize || len(item.ShipperDataBlob) > maxBlobSize {
+		return nil, errInvalidArgument(ctx, "sellerDataBlob|shipperDataBlob", fmt.Sprintf("<= %d bytes", maxBlobSize))
+	}
+
+	// 2. Chủ sở hữu: Seller phải có trong sổ đăng ký vai trò, hãng vận chuyển xác định như CreateOrder
+	registry, err := getRoleRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if registry.Orgs[item.SellerID] != RoleSeller {
+		return nil, errInvalidArgument(ctx, "sellerID", "SELLER organization in role registry")
+	}
+	if err := checkCompanyOrg(ctx, item.SellerCompanyID, item.SellerID); err != nil {
+		return nil, err
+	}
+	shipperOrg, err := resolveShipperOrg(ctx, item.ShipperCompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := append([]OrderLine(nil), item.Lines...)
+	totalAmount, err := checkOrderLines(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Chuỗi trạng thái: bắt đầu bằng CREATED, mỗi bước là một bước chuyển hợp lệ, thời điểm
+	// không giảm và không sau giao dịch nạp
+	if len(item.Statuses) == 0 || item.Statuses[0].Status != StatusCreated {
+		return nil, errInvalidArgument(ctx, "statuses[0].status", string(StatusCreated))
+	}
+	codStatus := CodNone
+	if item.PaymentMethod == PaymentCOD {
+		codStatus = CodNotCollected
+	}
+	order := &Order{
+		DocType:              "Order",
+		OrderID:              item.OrderID,
+		Status:               StatusCreated,
+		PaymentMethod:        item.PaymentMethod,
+		CodStatus:            codStatus,
+		SellerID:             item.SellerID,
+		SellerCompanyID:      item.SellerCompanyID,
+		ShipperID:            shipperOrg,
+		ShipperCompanyID:     item.ShipperCompanyID,
+		CreatedAt:            item.Statuses[0].At,
+		UpdatedAt:            txTime,
+		SellerSensitiveData:  item.SellerDataBlob,
+		ShipperSensitiveData: item.ShipperDataBlob,
+		Lines:                lines,
+		TotalAmount:          totalAmount,
+		Imported:             true,
+	}
+	actors := map[OrgRole]string{RoleSeller: item.SellerID, RoleShipper: shipperOrg, RolePlatform: actorOrg}
+
+	previous := time.Time{}
+	for i, step := range item.Statuses {
+		field := fmt.Sprintf("statuses[%d].at", i)
+		switch {
+		case step.At.IsZero():
+			return nil, errInvalidArgument(ctx, field, "required")
+		case step.At.Before(previous):
+			return nil, errInvalidArgument(ctx, field, ">= previous status")
+		case step.At.After(txTime):
+			return nil, errInvalidArgument(ctx, field, "<= transaction time")
+		}
+		previous = step.At
+
+		action, actor := "CreateOrder", RoleSeller
+		if i > 0 {
+			transition, next := findImportTransition(order.Status, step.Status, order.PaymentMethod)
+			if transition == nil {
+				return nil, errInvalidState(ctx, order.Status, anyOf(next...))
+			}
+			action, actor = transition.action, transition.actor
+		}
+
+		// Tiền thu hộ được nộp giữa lúc giao và lúc thanh toán cho Seller
+		if step.Status == StatusSettled && item.CodRemittedAt != nil {
+			if err := importCodRemittance(ctx, order, item.CodRemittedAt, step.At, actorOrg); err != nil {
+				return nil, err
+			}
+		}
+
+		order.Status = step.Status
+		order.History = append(order.History, HistoryEntry{
+			TxID:      ctx.GetStub().GetTxID(),
+			Timestamp: step.At,
+			Action:    action,
+			ActorOrg:  actors[actor],
+		})
+		switch step.Status {
+		case StatusDelivered:
+			order.DeliveryTimestamp = step.At
+			if order.PaymentMethod == PaymentCOD {
+				order.CodStatus = CodPendingRemittance
+			}
+		case StatusSettled:
+			if order.PaymentMethod == PaymentCOD && order.CodStatus != CodRemitted {
+				return nil, errInvalidArgument(ctx, "codRemittedAt", "required for SETTLED COD orders")
+			}
+			payout := payoutAmount(order, fees)
+			if item.PayoutAmount != nil {
+				payout = *item.PayoutAmount
+			}
+			if payout < 0 || payout > order.TotalAmount {
+				return nil, errInvalidArgument(ctx, "payoutAmount", fmt.Sprintf("0..%d", order.TotalAmount))
+			}
+			order.PayoutAmount = payout
+			order.PlatformFee = order.TotalAmount - payout
+		}
+	}
+
+	// 4. Đơn COD đã giao nhưng chưa thanh toán cho Seller vẫn có thể đã nộp tiền thu hộ
+	if item.CodRemittedAt != nil && order.CodStatus != CodRemitted {
+		if err := importCodRemittance(ctx, order, item.CodRemittedAt, txTime, actorOrg); err != nil {
+			return nil, err
+		}
+	}
+	if item.PayoutAmount != nil && order.Status != StatusSettled {
+		return nil, errInvalidArgument(ctx, "payoutAmount", "SETTLED orders only")
+	}
+
+	order.History = append(order.History, newHistoryEntry(ctx, "ImportOrders", actorOrg, txTime))
+	return order, nil
+}
+
+// importCodRemittance ghi bước RemitCOD của đơn COD đã giao, tại thời điểm remittedAt
+// (không trước lúc giao, không sau mốc before)
+func importCodRemittance(ctx contractapi.TransactionContextInterface, order *Order, remittedAt *time.Time, before time.Time, actorOrg string) error {
+	if order.PaymentMethod != PaymentCOD {
+		return errPaymentMethod(ctx, order.PaymentMethod, PaymentCOD)
+	}
+	if order.CodStatus != CodPendingRemittance {
+		return errInvalidState(ctx, order.CodStatus, CodPendingRemittance)
+	}
+	if remittedAt.Before(order.DeliveryTimestamp) || remittedAt.After(before) {
+		return errInvalidArgument(ctx, "codRemittedAt", "between delivery and payout")
+	}
+	order.CodStatus = CodRemitted
+	order.History = append(order.History, HistoryEntry{
+		TxID:      ctx.GetStub().GetTxID(),
+		Timestamp: *remittedAt,
+		Action:    "RemitCOD",
+		ActorOrg:  actorOrg,
+	})
+	return nil
+}
+
+// hasHistoryAction cho biết đơn đã từng qua giao dịch action
+func hasHistoryAction(order *Order, action string) bool {
+	for _, entry := range order.History {
+		if entry.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] ImportOrders: Sàn nạp một lô đơn lịch sử (JSON: [ImportOrder]), tối đa maxImportBatch
+// đơn. Đơn không hợp lệ, đã tồn tại hoặc đang bị đóng băng được trả về trong failed, các đơn
+// còn lại vẫn được nạp. Chính sách chứng thực của đơn như khi tạo (Seller + Sàn), thêm hãng
+// vận chuyển nếu đơn đã giao cho vận chuyển. Một sự kiện OrderChanged gộp mọi đơn đã nạp.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) ImportOrders(ctx contractapi.TransactionContextInterface, ordersJSON string) (*ImportResult, error) {
+	if prior, err := checkClientRequest(ctx); err != nil {
+		return nil, err
+	} else if prior != nil {
+		result := &ImportResult{}
+		return result, prior.decodeResult(ctx, result)
+	}
+
+	// 1. Kiểm tra ACL
+	actorOrg, err := requireRole(ctx, RolePlatform)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Đọc lô
+	var items []ImportOrder
+	if err := json.Unmarshal([]byte(ordersJSON), &items); err != nil {
+		return nil, errInvalidArgument(ctx, "ordersJSON", err.Error())
+	}
+	if len(items) == 0 || len(items) > maxImportBatch {
+		return nil, errInvalidArgument(ctx, "ordersJSON", fmt.Sprintf("1..%d orders", maxImportBatch))
+	}
+
+	txTime, err := getTimeNow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config, err := getPolicyConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Nạp từng đơn; lỗi của riêng một đơn được ghi vào failed, lỗi sổ cái dừng cả lô
+	result := &ImportResult{Imported: []string{}}
+	var event *OrderEvent
+	seen := make(map[string]bool)
+	for i := range items {
+		item := &items[i]
+		order, err := s.importOrder(ctx, item, seen, actorOrg, txTime, &config.Fees)
+		if err != nil {
+			contractErr, ok := err.(*ContractError)
+			if !ok || contractErr.Code == ErrLedger {
+				return nil, err
+			}
+			result.Failed = append(result.Failed, ImportFailure{
+				Index: i, OrderID: item.OrderID, Code: contractErr.Code, Message: contractErr.Message,
+			})
+			continue
+		}
+		result.Imported = append(result.Imported, order.OrderID)
+		if event == nil {
+			event = newOrderEvent(ctx, order, "")
+		} else {
+			event.Orders = append(event.Orders, newOrderEventEntry(order, ""))
+		}
+	}
+
+	// 4. Một giao dịch chỉ giữ một sự kiện: gộp mọi đơn đã nạp
+	if event != nil {
+		if err := setOrderEvent(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return result, recordClientResult(ctx, result)
+}
+
+// importOrder kiểm tra và ghi một đơn của lô. Mọi kiểm tra chạy trước lệnh ghi đầu tiên nên
+// đơn bị từ chối không để lại gì trên sổ cái.
+func (s *SmartContract) importOrder(ctx contractapi.TransactionContextInterface, item *ImportOrder, seen map[string]bool, actorOrg string, txTime time.Time, fees *FeeSchedule) (*Order, error) {
+	if seen[item.OrderID] {
+		return nil, errInvalidArgument(ctx, "orderID", "duplicate in batch")
+	}
+	seen[item.OrderID] = true
+
+	order, err := buildImportedOrder(ctx, item, actorOrg, txTime, fees)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.orderExists(ctx, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, newError(ctx, ErrOrderAlreadyExists, errorDetails{"orderID": order.OrderID})
+	}
+	if err := checkOrderNotFrozen(ctx, order); err != nil {
+		return nil, err
+	}
+
+	endorsers, err := initialOrderEndorsers(ctx, order.SellerID)
+	if err != nil {
+		return nil, err
+	}
+	if hasHistoryAction(order, "ShipOrder") {
+		endorsers = append(endorsers, order.ShipperID)
+	}
+	if err := setOrderEndorsers(ctx, order.OrderID, endorsers...); err != nil {
+		return nil, err
+	}
+	return order, saveOrderState(ctx, order)
+}
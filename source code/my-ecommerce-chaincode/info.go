@@ -0,0 +1,70 @@
+// my-ecommerce-chaincode/info.go
+
+package main
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Thông tin bản build, được gán lúc build:
+//
+//	go build -ldflags "-X main.contractVersion=1.0 -X main.gitCommit=$(git rev-parse --short HEAD)"
+//
+// Khi peer tự build từ gói mã nguồn, ecom-deploy (my-ecommerce-client) sinh buildinfo_generated.go gán hai biến này.
+var (
+	contractVersion = "1.0"
+	gitCommit       = "unknown"
+)
+
+// ContractInfo là ảnh chụp trạng thái vận hành của hợp đồng cho giám sát
+type ContractInfo struct {
+	ContractVersion string            `json:"contractVersion"` // Phiên bản của chaincode đang chạy
+	GitCommit       string            `json:"gitCommit"`
+	SchemaVersion   int               `json:"schemaVersion"`                           // Phiên bản lược đồ Order mà chaincode ghi ra
+	Metadata        *ContractMetadata `json:"metadata,omitempty" metadata:",optional"` // Lần InitLedger đầu tiên / gần nhất
+	Policy          *PolicyConfig     `json:"policy"`
+	Roles           *RoleRegistry     `json:"roles"`
+	Frozen          bool              `json:"frozen"` // Đang đóng băng toàn cục
+	Freezes         []*FreezeRecord   `json:"freezes"`
+	OrderCounts     map[string]int64  `json:"orderCounts"` // Số đơn theo trạng thái (counters.go)
+	TotalOrders     int64             `json:"totalOrders"`
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetContractInfo: Phiên bản, cấu hình hiện hành, tình trạng đóng băng và số đơn
+// theo trạng thái. Chỉ đọc bản ghi cấu hình và bộ đếm, không quét đơn hàng.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetContractInfo(ctx contractapi.TransactionContextInterface) (*ContractInfo, error) {
+	info := &ContractInfo{
+		ContractVersion: contractVersion,
+		GitCommit:       gitCommit,
+		SchemaVersion:   currentOrderSchemaVersion,
+	}
+
+	var err error
+	if info.Metadata, err = getContractMetadata(ctx); err != nil {
+		return nil, err
+	}
+	if info.Policy, err = getPolicyConfig(ctx); err != nil {
+		return nil, err
+	}
+	if info.Roles, err = getRoleRegistry(ctx); err != nil {
+		return nil, err
+	}
+	if info.Freezes, err = s.GetFreezes(ctx); err != nil {
+		return nil, err
+	}
+	for _, record := range info.Freezes {
+		if record.Scope == FreezeGlobal {
+			info.Frozen = true
+		}
+	}
+
+	if info.OrderCounts, err = getOrderCounts(ctx); err != nil {
+		return nil, err
+	}
+	for _, count := range info.OrderCounts {
+		info.TotalOrders += count
+	}
+	return info, nil
+}
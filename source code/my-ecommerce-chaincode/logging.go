@@ -0,0 +1,94 @@
+// my-ecommerce-chaincode/logging.go
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log của chaincode là JSON, mỗi dòng một bản ghi, ghi ra stderr để peer / Docker / Kubernetes
+// thu thập như log container. Mức log chọn qua CHAINCODE_LOG_LEVEL (debug | info | warn | error,
+// mặc định info).
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var logLevelNames = map[logLevel]string{
+	levelDebug: "debug",
+	levelInfo:  "info",
+	levelWarn:  "warn",
+	levelError: "error",
+}
+
+// logFields là các trường thêm vào bản ghi (txID, function, mspID, ...)
+type logFields map[string]interface{}
+
+// jsonLogger ghi bản ghi JSON; an toàn khi gọi từ nhiều giao dịch song song
+type jsonLogger struct {
+	mu       sync.Mutex
+	out      io.Writer
+	minLevel logLevel
+}
+
+func newJSONLogger(out io.Writer, minLevel logLevel) *jsonLogger {
+	return &jsonLogger{out: out, minLevel: minLevel}
+}
+
+// newLoggerFromEnv tạo logger ghi ra stderr với mức log trong CHAINCODE_LOG_LEVEL
+func newLoggerFromEnv() *jsonLogger {
+	logger := newJSONLogger(os.Stderr, levelInfo)
+	if value := os.Getenv("CHAINCODE_LOG_LEVEL"); value != "" {
+		found := false
+		for level, name := range logLevelNames {
+			if strings.EqualFold(value, name) {
+				logger.minLevel, found = level, true
+			}
+		}
+		if !found {
+			logger.Warn("CHAINCODE_LOG_LEVEL không hợp lệ, dùng info", logFields{"value": value})
+		}
+	}
+	return logger
+}
+
+func (l *jsonLogger) Debug(msg string, fields logFields) { l.write(levelDebug, msg, fields) }
+func (l *jsonLogger) Info(msg string, fields logFields)  { l.write(levelInfo, msg, fields) }
+func (l *jsonLogger) Warn(msg string, fields logFields)  { l.write(levelWarn, msg, fields) }
+func (l *jsonLogger) Error(msg string, fields logFields) { l.write(levelError, msg, fields) }
+
+// Fatal ghi bản ghi mức error rồi thoát tiến trình với mã 1
+func (l *jsonLogger) Fatal(msg string, fields logFields) {
+	l.write(levelError, msg, fields)
+	os.Exit(1)
+}
+
+func (l *jsonLogger) write(level logLevel, msg string, fields logFields) {
+	if level < l.minLevel {
+		return
+	}
+	record := make(logFields, len(fields)+3)
+	for key, value := range fields {
+		record[key] = value
+	}
+	record["ts"] = time.Now().UTC().Format(time.RFC3339Nano)
+	record["level"] = logLevelNames[level]
+	record["msg"] = msg
+	line, err := json.Marshal(record)
+	if err != nil {
+		line, _ = json.Marshal(logFields{"ts": record["ts"], "level": "error", "msg": "không mã hóa được bản ghi log", "cause": err.Error()})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(append(line, '\n'))
+}
@@ -1,18 +1,125 @@
 package main
 
 import (
-	"log"
+	"fmt"
+	"os"
+	"strconv"
 
+	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
+// Chế độ chaincode-as-a-service (CCAAS): khi có CHAINCODE_SERVER_ADDRESS, chaincode tự mở
+// cổng gRPC và peer kết nối tới (deployCCAAS.sh, Kubernetes, chạy trong debugger trên máy).
+// Không có biến này thì chaincode chạy như cũ: peer build và khởi động container.
+//
+//	CHAINCODE_SERVER_ADDRESS  Địa chỉ lắng nghe, VD: 0.0.0.0:9999
+//	CHAINCODE_ID              Package ID trả về khi install (hoặc CORE_CHAINCODE_ID_NAME)
+//	CHAINCODE_TLS_DISABLED    "false" để bật TLS (mặc định tắt, khớp "tls_required" trong connection.json)
+//	CHAINCODE_TLS_KEY         Đường dẫn khóa riêng TLS (PEM)
+//	CHAINCODE_TLS_CERT        Đường dẫn chứng chỉ TLS (PEM)
+//	CHAINCODE_CLIENT_CA_CERT  Đường dẫn CA của peer, bật xác thực client (mutual TLS) - tùy chọn
+//	CHAINCODE_METRICS_ADDRESS Cổng Prometheus /metrics, VD: 0.0.0.0:9443 (chỉ bản build -tags ccaas)
+//
+// Ở cả hai chế độ, mỗi giao dịch ghi một dòng log JSON ra stderr (metrics.go, logging.go);
+// CHAINCODE_LOG_LEVEL chọn mức log (debug | info | warn | error, mặc định info).
 func main() {
+	logger := newLoggerFromEnv()
 	orderChaincode, err := contractapi.NewChaincode(&SmartContract{})
 	if err != nil {
-		log.Panicf("Lỗi khi tạo chaincode: %v", err)
+		logger.Fatal("lỗi khi tạo chaincode", logFields{"cause": err.Error()})
 	}
+	observed := newObservedChaincode(orderChaincode, logger, startMetrics(logger))
 
-	if err := orderChaincode.Start(); err != nil {
-		log.Panicf("Lỗi khi khởi động chaincode: %v", err)
+	address := os.Getenv("CHAINCODE_SERVER_ADDRESS")
+	if address == "" {
+		if err := shim.Start(observed); err != nil {
+			logger.Fatal("lỗi khi khởi động chaincode", logFields{"cause": err.Error()})
+		}
+		return
 	}
+
+	server, err := newChaincodeServer(observed, address)
+	if err != nil {
+		logger.Fatal("lỗi cấu hình chaincode server", logFields{"cause": err.Error()})
+	}
+	logger.Info("chaincode server lắng nghe", logFields{
+		"ccid": server.CCID, "version": contractVersion, "commit": gitCommit,
+		"address": address, "tls": !server.TLSProps.Disabled,
+	})
+	if err := server.Start(); err != nil {
+		logger.Fatal("lỗi khi khởi động chaincode server", logFields{"cause": err.Error()})
+	}
+}
+
+// newChaincodeServer tạo shim.ChaincodeServer từ biến môi trường
+func newChaincodeServer(cc shim.Chaincode, address string) (*shim.ChaincodeServer, error) {
+	ccid := os.Getenv("CHAINCODE_ID")
+	if ccid == "" {
+		ccid = os.Getenv("CORE_CHAINCODE_ID_NAME")
+	}
+	if ccid == "" {
+		return nil, fmt.Errorf("thiếu CHAINCODE_ID")
+	}
+
+	tlsProps, err := getTLSProperties()
+	if err != nil {
+		return nil, err
+	}
+	return &shim.ChaincodeServer{
+		CCID:     ccid,
+		Address:  address,
+		CC:       cc,
+		TLSProps: tlsProps,
+	}, nil
+}
+
+// getTLSProperties đọc cấu hình TLS của chaincode server
+func getTLSProperties() (shim.TLSProperties, error) {
+	disabled := true
+	if value := os.Getenv("CHAINCODE_TLS_DISABLED"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return shim.TLSProperties{}, fmt.Errorf("CHAINCODE_TLS_DISABLED không hợp lệ: %q", value)
+		}
+		disabled = parsed
+	}
+	if disabled {
+		return shim.TLSProperties{Disabled: true}, nil
+	}
+
+	key, err := readPEMFile("CHAINCODE_TLS_KEY", true)
+	if err != nil {
+		return shim.TLSProperties{}, err
+	}
+	cert, err := readPEMFile("CHAINCODE_TLS_CERT", true)
+	if err != nil {
+		return shim.TLSProperties{}, err
+	}
+	clientCACerts, err := readPEMFile("CHAINCODE_CLIENT_CA_CERT", false)
+	if err != nil {
+		return shim.TLSProperties{}, err
+	}
+	return shim.TLSProperties{
+		Disabled:      false,
+		Key:           key,
+		Cert:          cert,
+		ClientCACerts: clientCACerts,
+	}, nil
+}
+
+// readPEMFile đọc file có đường dẫn trong biến môi trường envName
+func readPEMFile(envName string, required bool) ([]byte, error) {
+	path := os.Getenv(envName)
+	if path == "" {
+		if required {
+			return nil, fmt.Errorf("thiếu %s khi bật TLS", envName)
+		}
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("không đọc được %s (%s): %w", envName, path, err)
+	}
+	return data, nil
 }
@@ -0,0 +1,160 @@
+// my-ecommerce-chaincode/metrics.go
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Mỗi giao dịch (cả submit lẫn evaluate) đi qua observedChaincode: một dòng log JSON gắn txID,
+// function, MSP và companyCode của người gọi, kết quả (mã lỗi ContractError hoặc OK) và thời
+// gian chạy, cùng số liệu cho Metrics. Bản build "ccaas" xuất số liệu qua Prometheus
+// (metrics_ccaas.go); bản thường bỏ qua (metrics_noccaas.go).
+//
+// Số liệu được đo lúc endorse trên peer này: giao dịch đã endorse vẫn có thể bị invalidate khi
+// commit (MVCC, thiếu endorsement), và cùng một giao dịch được đếm trên mỗi peer endorse nó.
+
+// Metrics nhận số liệu của các giao dịch
+type Metrics interface {
+	// ObserveTransaction ghi một giao dịch: code là mã lỗi ContractError hoặc "OK"
+	ObserveTransaction(function string, code string, duration time.Duration)
+	// ObserveStatusChange ghi một lần đơn chuyển trạng thái (from rỗng = đơn mới) trong giao dịch thành công
+	ObserveStatusChange(from Status, to Status)
+}
+
+// noMetrics bỏ qua mọi số liệu
+type noMetrics struct{}
+
+func (noMetrics) ObserveTransaction(string, string, time.Duration) {}
+func (noMetrics) ObserveStatusChange(Status, Status)               {}
+
+const (
+	txCodeOK      = "OK"
+	txCodeUnknown = "UNKNOWN" // Lỗi không phải ContractError (VD: contractapi báo sai số tham số)
+
+	// unknownFunction thay tên hàm không có trong hợp đồng, để nhãn của số liệu không tăng vô hạn
+	unknownFunction = "unknown"
+)
+
+// observedChaincode bọc chaincode của contractapi để ghi log và số liệu cho từng giao dịch
+type observedChaincode struct {
+	cc        shim.Chaincode
+	logger    *jsonLogger
+	metrics   Metrics
+	functions map[string]bool
+}
+
+func newObservedChaincode(cc shim.Chaincode, logger *jsonLogger, metrics Metrics) *observedChaincode {
+	return &observedChaincode{cc: cc, logger: logger, metrics: metrics, functions: contractFunctions()}
+}
+
+// contractFunctions là tên các giao dịch của SmartContract (kể cả GetMetadata của contractapi)
+func contractFunctions() map[string]bool {
+	functions := map[string]bool{"Init": true, "GetMetadata": true}
+	t := reflect.TypeOf(&SmartContract{})
+	for i := 0; i < t.NumMethod(); i++ {
+		functions[t.Method(i).Name] = true
+	}
+	return functions
+}
+
+func (o *observedChaincode) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return o.observe(stub, o.cc.Init)
+}
+
+func (o *observedChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	return o.observe(stub, o.cc.Invoke)
+}
+
+func (o *observedChaincode) observe(stub shim.ChaincodeStubInterface, call func(shim.ChaincodeStubInterface) peer.Response) peer.Response {
+	start := time.Now()
+	tx := &observedStub{ChaincodeStubInterface: stub}
+	response := call(tx)
+	duration := time.Since(start)
+
+	function, _ := stub.GetFunctionAndParameters()
+	if idx := strings.LastIndex(function, ":"); idx >= 0 {
+		function = function[idx+1:]
+	}
+	if function == "" {
+		function = "Init"
+	}
+	label := function
+	if !o.functions[function] {
+		label = unknownFunction
+	}
+	code, message := responseCode(response)
+
+	o.metrics.ObserveTransaction(label, code, duration)
+	if code == txCodeOK {
+		for _, change := range tx.statusChanges {
+			o.metrics.ObserveStatusChange(change.from, change.to)
+		}
+	}
+
+	fields := logFields{
+		"txID":       stub.GetTxID(),
+		"channel":    stub.GetChannelID(),
+		"function":   function,
+		"code":       code,
+		"durationMs": float64(duration.Microseconds()) / 1000,
+	}
+	if identity, err := cid.New(stub); err == nil {
+		fields["mspID"], _ = identity.GetMSPID()
+		if company, found, _ := identity.GetAttributeValue("companyCode"); found {
+			fields["company"] = company
+		}
+	}
+	if len(tx.statusChanges) > 0 {
+		fields["statusChanges"] = len(tx.statusChanges)
+	}
+	switch code {
+	case txCodeOK:
+		o.logger.Info("giao dịch thành công", fields)
+	case string(ErrLedger), txCodeUnknown:
+		fields["error"] = message
+		o.logger.Error("giao dịch lỗi", fields)
+	default:
+		fields["error"] = message
+		o.logger.Warn("giao dịch bị từ chối", fields)
+	}
+	return response
+}
+
+// responseCode trả về mã kết quả và thông báo lỗi của response
+func responseCode(response peer.Response) (string, string) {
+	if response.Status < shim.ERRORTHRESHOLD {
+		return txCodeOK, ""
+	}
+	var contractErr ContractError
+	if err := json.Unmarshal([]byte(response.Message), &contractErr); err != nil || contractErr.Code == "" {
+		return txCodeUnknown, response.Message
+	}
+	return string(contractErr.Code), contractErr.Message
+}
+
+// observedStub là stub của một giao dịch đang chạy, giữ lại các lần chuyển trạng thái đơn để
+// chỉ đếm khi giao dịch thành công
+type observedStub struct {
+	shim.ChaincodeStubInterface
+	statusChanges []statusChange
+}
+
+type statusChange struct {
+	from Status
+	to   Status
+}
+
+// noteStatusChange ghi lại một lần chuyển trạng thái cho số liệu (recordStatusChange gọi)
+func noteStatusChange(stub shim.ChaincodeStubInterface, from Status, to Status) {
+	if tx, ok := stub.(*observedStub); ok {
+		tx.statusChanges = append(tx.statusChanges, statusChange{from: from, to: to})
+	}
+}
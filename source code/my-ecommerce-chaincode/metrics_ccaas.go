@@ -0,0 +1,90 @@
+// my-ecommerce-chaincode/metrics_ccaas.go
+
+//go:build ccaas
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Bản build chaincode-as-a-service (go build -tags ccaas, xem Dockerfile) mở cổng HTTP riêng
+// cho Prometheus tại CHAINCODE_METRICS_ADDRESS (VD: 0.0.0.0:9443), đường dẫn /metrics.
+// Bảng điều khiển Grafana: my-ecommerce-network/prometheus-grafana/grafana/provisioning/
+// dashboards/ecommerce-chaincode.json.
+
+// prometheusMetrics xuất số liệu giao dịch theo chuẩn Prometheus
+type prometheusMetrics struct {
+	transactions  *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	statusChanges *prometheus.CounterVec
+}
+
+func newPrometheusMetrics(registry prometheus.Registerer) *prometheusMetrics {
+	m := &prometheusMetrics{
+		transactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "ecommerce",
+			Subsystem: "chaincode",
+			Name:      "transactions_total",
+			Help:      "Số giao dịch đã chạy (lúc endorse) theo hàm và mã kết quả (OK hoặc mã lỗi).",
+		}, []string{"function", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "ecommerce",
+			Subsystem: "chaincode",
+			Name:      "transaction_duration_seconds",
+			Help:      "Thời gian chạy giao dịch trong chaincode theo hàm.",
+			Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+		}, []string{"function"}),
+		statusChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "ecommerce",
+			Subsystem: "chaincode",
+			Name:      "order_status_changes_total",
+			Help:      "Số lần đơn chuyển trạng thái trong các giao dịch thành công (from rỗng = đơn mới).",
+		}, []string{"from", "to"}),
+	}
+	registry.MustRegister(m.transactions, m.duration, m.statusChanges)
+	return m
+}
+
+func (m *prometheusMetrics) ObserveTransaction(function string, code string, duration time.Duration) {
+	m.transactions.WithLabelValues(function, code).Inc()
+	m.duration.WithLabelValues(function).Observe(duration.Seconds())
+}
+
+func (m *prometheusMetrics) ObserveStatusChange(from Status, to Status) {
+	m.statusChanges.WithLabelValues(string(from), string(to)).Inc()
+}
+
+// startMetrics mở /metrics tại CHAINCODE_METRICS_ADDRESS; không có biến này thì bỏ qua số liệu
+func startMetrics(logger *jsonLogger) Metrics {
+	address := os.Getenv("CHAINCODE_METRICS_ADDRESS")
+	if address == "" {
+		logger.Info("không có CHAINCODE_METRICS_ADDRESS, tắt /metrics", nil)
+		return noMetrics{}
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	metrics := newPrometheusMetrics(registry)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry}))
+	server := &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("lỗi khi mở cổng /metrics", logFields{"address": address, "cause": err.Error()})
+		}
+	}()
+	logger.Info("mở /metrics", logFields{"address": address})
+	return metrics
+}
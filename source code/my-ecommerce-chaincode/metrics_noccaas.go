@@ -0,0 +1,16 @@
+// my-ecommerce-chaincode/metrics_noccaas.go
+
+//go:build !ccaas
+
+package main
+
+import "os"
+
+// startMetrics: bản build thường (peer build chaincode) không có Prometheus, chỉ ghi log.
+// Build với -tags ccaas để có /metrics (metrics_ccaas.go).
+func startMetrics(logger *jsonLogger) Metrics {
+	if address := os.Getenv("CHAINCODE_METRICS_ADDRESS"); address != "" {
+		logger.Warn("bản build không có Prometheus (cần -tags ccaas), bỏ qua CHAINCODE_METRICS_ADDRESS", logFields{"address": address})
+	}
+	return noMetrics{}
+}
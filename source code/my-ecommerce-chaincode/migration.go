@@ -0,0 +1,167 @@
+// my-ecommerce-chaincode/migration.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Phiên bản lược đồ JSON của Order. Khi đổi model.go theo cách làm bản ghi cũ
+// không còn giải mã đúng (đổi tên/kiểu trường, trường bắt buộc mới...), tăng
+// currentOrderSchemaVersion và thêm một bước vào orderUpgrades.
+// Bản ghi chưa có trường schemaVersion được coi là phiên bản 0.
+const currentOrderSchemaVersion = 1
+
+// maxMigrateBatch giới hạn số đơn được đọc trong một giao dịch MigrateOrders
+const maxMigrateBatch = 200
+
+// orderUpgrade nâng một bản ghi (dạng JSON thô) lên phiên bản kế tiếp
+type orderUpgrade func(raw map[string]interface{})
+
+// orderUpgrades[v] nâng bản ghi từ phiên bản v lên v+1
+var orderUpgrades = []orderUpgrade{
+	upgradeOrderV0,
+}
+
+// upgradeOrderV0: Đơn tạo trước khi có phiên bản lược đồ (trước dòng hàng, chỉ mục trạng thái...).
+// Lịch sử rỗng được ghi là null nhưng là trường bắt buộc trong metadata của hợp đồng.
+// Phiên bản 0 nhận paymentMethod tùy ý và chỉ áp dụng nghiệp vụ thu hộ cho đúng "COD", nên
+// mọi giá trị khác được chuyển thành PREPAID (không có trạng thái COD) để giải mã được.
+func upgradeOrderV0(raw map[string]interface{}) {
+	if raw["history"] == nil {
+		raw["history"] = []interface{}{}
+	}
+	if raw["docType"] == "Order" && raw["paymentMethod"] != string(PaymentCOD) {
+		raw["paymentMethod"] = string(PaymentPrepaid)
+		raw["codStatus"] = string(CodNone)
+	}
+}
+
+// decodeOrder giải mã bản ghi Order ở bất kỳ phiên bản nào và nâng lên phiên bản hiện tại
+// trong bộ nhớ. Trả về kèm phiên bản đang lưu trên sổ cái.
+func decodeOrder(orderJSON []byte) (*Order, int, error) {
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(orderJSON, &header); err != nil {
+		return nil, 0, err
+	}
+	storedVersion := header.SchemaVersion
+	if storedVersion > currentOrderSchemaVersion {
+		// Ghi lại bản ghi mới hơn bằng chaincode cũ sẽ làm mất dữ liệu
+		return nil, storedVersion, fmt.Errorf("schemaVersion %d mới hơn phiên bản chaincode hỗ trợ (%d)", storedVersion, currentOrderSchemaVersion)
+	}
+
+	if storedVersion < currentOrderSchemaVersion {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(orderJSON, &raw); err != nil {
+			return nil, storedVersion, err
+		}
+		for version := storedVersion; version < currentOrderSchemaVersion; version++ {
+			orderUpgrades[version](raw)
+		}
+		raw["schemaVersion"] = currentOrderSchemaVersion
+		upgraded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, storedVersion, err
+		}
+		orderJSON = upgraded
+	}
+
+	var order Order
+	if err := json.Unmarshal(orderJSON, &order); err != nil {
+		return nil, storedVersion, err
+	}
+	order.indexedStatus = order.Status
+	return &order, storedVersion, nil
+}
+
+// MigrationResult là kết quả của một lượt MigrateOrders
+type MigrationResult struct {
+	Scanned  int      `json:"scanned"`                                 // Số đơn đã đọc trong lượt này
+	Migrated []string `json:"migrated"`                                // Đơn đã được ghi lại theo lược đồ hiện tại
+	Skipped  []string `json:"skipped,omitempty" metadata:",optional"`  // Đơn cũ đang bị đóng băng, chưa ghi lại (chạy lại từ đầu)
+	Bookmark string   `json:"bookmark,omitempty" metadata:",optional"` // fromKey cho lượt tiếp theo, rỗng khi đã hết
+	Version  int      `json:"schemaVersion"`                           // Phiên bản lược đồ hiện tại
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] MigrateOrders: Sàn ghi lại các đơn cũ theo lược đồ hiện tại, từng lượt tối đa
+// limit đơn bắt đầu từ khóa fromKey (rỗng = từ đầu). Gọi lại với bookmark trả về cho
+// đến khi bookmark rỗng. Ghi lại đơn cũng bổ sung chỉ mục trạng thái còn thiếu. Nếu có
+// lượt trả về Skipped (đơn đang bị đóng băng), chạy lại từ fromKey rỗng sau khi gỡ đóng băng.
+// Lưu ý: mỗi đơn có chính sách chứng thực riêng (endorsement.go), nên giao dịch cần
+// được gửi tới peer của mọi tổ chức liên quan đến các đơn trong lượt.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) MigrateOrders(ctx contractapi.TransactionContextInterface, fromKey string, limit int) (*MigrationResult, error) {
+	if prior, err := checkClientRequest(ctx); err != nil {
+		return nil, err
+	} else if prior != nil {
+		result := &MigrationResult{}
+		return result, prior.decodeResult(ctx, result)
+	}
+
+	// 1. Kiểm tra ACL
+	if _, err := requireRole(ctx, RolePlatform); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxMigrateBatch {
+		return nil, errInvalidArgument(ctx, "limit", fmt.Sprintf("1..%d", maxMigrateBatch))
+	}
+
+	// 2. Quét khóa đơn trong phạm vi khóa đơn giản (firstSimpleKey..lastSimpleKey, như
+	// RebuildOrderCounters). Đọc thêm một khóa để biết lượt sau bắt đầu từ đâu.
+	startKey := fromKey
+	if startKey == "" {
+		startKey = firstSimpleKey
+	}
+	resultsIterator, err := ctx.GetStub().GetStateByRange(startKey, lastSimpleKey)
+	if err != nil {
+		return nil, errLedger(ctx, "GetStateByRange", err)
+	}
+	result := &MigrationResult{Migrated: []string{}, Version: currentOrderSchemaVersion}
+	var outdated []*Order
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			resultsIterator.Close()
+			return nil, errLedger(ctx, "iterator.Next", err)
+		}
+		if result.Scanned == limit {
+			result.Bookmark = queryResponse.Key
+			break
+		}
+		result.Scanned++
+
+		order, storedVersion, err := decodeOrder(queryResponse.Value)
+		if err != nil {
+			resultsIterator.Close()
+			return nil, errLedger(ctx, "unmarshal "+queryResponse.Key, err)
+		}
+		if order.DocType == "Order" && storedVersion < currentOrderSchemaVersion {
+			outdated = append(outdated, order)
+		}
+	}
+	resultsIterator.Close()
+
+	// 3. Ghi lại sau khi đóng iterator. Đơn đang bị đóng băng không được ghi và nằm trong Skipped;
+	// bookmark đã đi qua chúng nên phải chạy lại từ fromKey rỗng sau khi gỡ đóng băng
+	for _, order := range outdated {
+		freeze, err := getOrderFreeze(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		if freeze != nil {
+			result.Skipped = append(result.Skipped, order.OrderID)
+			continue
+		}
+		if err := saveOrderState(ctx, order); err != nil {
+			return nil, err
+		}
+		result.Migrated = append(result.Migrated, order.OrderID)
+	}
+	return result, recordClientResult(ctx, result)
+}
@@ -0,0 +1,154 @@
+// my-ecommerce-chaincode/migration_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// putLegacyOrder ghi thẳng một đơn phiên bản 0 (chưa có schemaVersion) vào sổ cái
+func putLegacyOrder(t *testing.T, sim *Simulator, orderID string, paymentMethod string) {
+	t.Helper()
+	orderJSON := fmt.Sprintf(`{"docType":"Order","orderID":%q,"status":"DELIVERED","paymentMethod":%q,`+
+		`"sellerID":"SellerOrgMSP","shipperID":"ShipperOrgMSP","sellerCompanyID":"Shop_ABC","shipperCompanyID":"GHN",`+
+		`"totalAmount":100000,"history":null}`, orderID, paymentMethod)
+	sim.stub.MockTransactionStart("legacy-" + orderID)
+	if err := sim.stub.PutState(orderID, []byte(orderJSON)); err != nil {
+		t.Fatal(err)
+	}
+	sim.stub.MockTransactionEnd("legacy-" + orderID)
+}
+
+// storedSchemaVersion đọc schemaVersion đang lưu trên sổ cái (0 nếu chưa có)
+func storedSchemaVersion(t *testing.T, sim *Simulator, orderID string) int {
+	t.Helper()
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(sim.State(orderID), &header); err != nil {
+		t.Fatalf("đơn %s: %v", orderID, err)
+	}
+	return header.SchemaVersion
+}
+
+func TestDecodeOrderUpgradesV0(t *testing.T) {
+	tests := []struct {
+		paymentMethod string
+		want          PaymentMethod
+		codStatus     CodStatus
+	}{
+		{"COD", PaymentCOD, ""},
+		{"BANK_TRANSFER", PaymentPrepaid, CodNone},
+		{"", PaymentPrepaid, CodNone},
+	}
+	for _, tt := range tests {
+		orderJSON := fmt.Sprintf(`{"docType":"Order","orderID":"V0","status":"DELIVERED","paymentMethod":%q,"history":null}`, tt.paymentMethod)
+		order, storedVersion, err := decodeOrder([]byte(orderJSON))
+		if err != nil {
+			t.Fatalf("paymentMethod %q: %v", tt.paymentMethod, err)
+		}
+		if storedVersion != 0 || order.SchemaVersion != currentOrderSchemaVersion {
+			t.Errorf("phiên bản = %d -> %d, muốn 0 -> %d", storedVersion, order.SchemaVersion, currentOrderSchemaVersion)
+		}
+		if order.PaymentMethod != tt.want || order.CodStatus != tt.codStatus {
+			t.Errorf("paymentMethod %q -> %s/%q, muốn %s/%q", tt.paymentMethod, order.PaymentMethod, order.CodStatus, tt.want, tt.codStatus)
+		}
+		if order.History == nil {
+			t.Errorf("paymentMethod %q: history null chưa được thay bằng danh sách rỗng", tt.paymentMethod)
+		}
+	}
+
+	// Bản ghi mới hơn chaincode không được giải mã (ghi lại sẽ làm mất dữ liệu)
+	newer := fmt.Sprintf(`{"docType":"Order","orderID":"V9","schemaVersion":%d}`, currentOrderSchemaVersion+1)
+	if _, _, err := decodeOrder([]byte(newer)); err == nil {
+		t.Error("giải mã được đơn có schemaVersion mới hơn")
+	}
+}
+
+func TestGetOrderStateUpgradesV0InMemory(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	putLegacyOrder(t, sim, "V0001", "BANK_TRANSFER")
+
+	result, err := sim.Evaluate("platform", "QueryOrder", "V0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order Order
+	if err := json.Unmarshal(result.Payload, &order); err != nil {
+		t.Fatal(err)
+	}
+	if order.SchemaVersion != currentOrderSchemaVersion || order.PaymentMethod != PaymentPrepaid || order.Status != StatusDelivered {
+		t.Errorf("QueryOrder = v%d %s %s, muốn v%d PREPAID DELIVERED", order.SchemaVersion, order.PaymentMethod, order.Status, currentOrderSchemaVersion)
+	}
+	// Đọc không ghi lại: sổ cái vẫn giữ bản phiên bản 0
+	if version := storedSchemaVersion(t, sim, "V0001"); version != 0 {
+		t.Errorf("schemaVersion trên sổ cái = %d sau khi đọc, muốn 0", version)
+	}
+}
+
+func migrate(t *testing.T, sim *Simulator, fromKey string, limit int) *MigrationResult {
+	t.Helper()
+	result, err := sim.Submit("platform", "MigrateOrders", fromKey, fmt.Sprint(limit))
+	if err != nil {
+		t.Fatalf("MigrateOrders(%q, %d): %v", fromKey, limit, err)
+	}
+	var migrated MigrationResult
+	if err := json.Unmarshal(result.Payload, &migrated); err != nil {
+		t.Fatal(err)
+	}
+	return &migrated
+}
+
+func TestMigrateOrdersFollowsBookmarks(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	// Đơn mới tạo kèm khóa composite (chỉ mục, bộ đếm, chính sách...) nằm xen giữa các đơn cũ
+	mustSubmit(t, sim, "seller", "CreateOrder", "V0002", "COD", "GHN", "", "", "Shop_ABC",
+		`[{"lineID":"L1","sku":"A","quantity":1,"unitPrice":100000}]`)
+	for _, orderID := range []string{"V0001", "V0003", "V0004"} {
+		putLegacyOrder(t, sim, orderID, "COD")
+	}
+
+	var migrated []string
+	pages := 0
+	for fromKey := ""; pages == 0 || fromKey != ""; pages++ {
+		result := migrate(t, sim, fromKey, 2)
+		migrated = append(migrated, result.Migrated...)
+		fromKey = result.Bookmark
+	}
+	if pages != 2 {
+		t.Errorf("số lượt = %d, muốn 2", pages)
+	}
+	if want := []string{"V0001", "V0003", "V0004"}; !reflect.DeepEqual(migrated, want) {
+		t.Errorf("đã ghi lại %v, muốn %v", migrated, want)
+	}
+	for _, orderID := range []string{"V0001", "V0002", "V0003", "V0004"} {
+		if version := storedSchemaVersion(t, sim, orderID); version != currentOrderSchemaVersion {
+			t.Errorf("schemaVersion của %s = %d, muốn %d", orderID, version, currentOrderSchemaVersion)
+		}
+	}
+}
+
+func TestMigrateOrdersSkipsFrozenOrdersUntilRerun(t *testing.T) {
+	sim := newEndorsementSimulator(t)
+	putLegacyOrder(t, sim, "V0001", "COD")
+	putLegacyOrder(t, sim, "V0002", "COD")
+	mustSubmit(t, sim, "platform", "Freeze", string(FreezeOrder), "V0001", "tranh chấp")
+
+	result := migrate(t, sim, "", 10)
+	if !reflect.DeepEqual(result.Skipped, []string{"V0001"}) || !reflect.DeepEqual(result.Migrated, []string{"V0002"}) {
+		t.Fatalf("MigrateOrders = migrated %v, skipped %v; muốn [V0002], [V0001]", result.Migrated, result.Skipped)
+	}
+	if version := storedSchemaVersion(t, sim, "V0001"); version != 0 {
+		t.Errorf("đơn bị đóng băng đã bị ghi lại (schemaVersion %d)", version)
+	}
+
+	// Sau khi gỡ đóng băng, chạy lại từ đầu ghi nốt đơn bị bỏ qua
+	mustSubmit(t, sim, "platform", "Unfreeze", string(FreezeOrder), "V0001", "đã xử lý")
+	result = migrate(t, sim, "", 10)
+	if !reflect.DeepEqual(result.Migrated, []string{"V0001"}) || len(result.Skipped) != 0 {
+		t.Errorf("chạy lại = migrated %v, skipped %v; muốn [V0001]", result.Migrated, result.Skipped)
+	}
+}
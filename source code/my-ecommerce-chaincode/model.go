@@ -6,10 +6,11 @@ import "time"
 
 type Order struct {
 	DocType           string    `json:"docType"`
+	SchemaVersion     int       `json:"schemaVersion"` // Phiên bản lược đồ JSON, xem migration.go
 	OrderID           string    `json:"orderID"`
-	Status            string    `json:"status"`
-	PaymentMethod     string    `json:"paymentMethod"`
-	CodStatus         string    `json:"codStatus"`
+	Status            Status        `json:"status"`
+	PaymentMethod     PaymentMethod `json:"paymentMethod"`
+	CodStatus         CodStatus     `json:"codStatus"`
 
 	// --- CẤP ĐỘ TỔ CHỨC (MSP) ---
 	SellerID  string `json:"sellerID"`  // Lưu MSP của Seller (VD: "SellerOrgMSP")
@@ -23,9 +24,55 @@ type Order struct {
 	CreatedAt           time.Time      `json:"createdAt"`
 	UpdatedAt           time.Time      `json:"updatedAt"`
 	DeliveryTimestamp   time.Time      `json:"deliveryTimestamp"`
-	SellerSensitiveData string         `json:"seller_sensitive_data,omitempty"`
-	ShipperSensitiveData string        `json:"shipper_sensitive_data,omitempty"`
+	SellerSensitiveData string         `json:"seller_sensitive_data,omitempty" metadata:",optional"`
+	ShipperSensitiveData string        `json:"shipper_sensitive_data,omitempty" metadata:",optional"`
+
+	// --- DÒNG HÀNG & SỐ TIỀN ---
+	Lines            []OrderLine `json:"lines,omitempty" metadata:",optional"`
+	TotalAmount      int64       `json:"totalAmount"`      // Tổng tiền hàng tính từ các dòng
+	RefundableAmount int64       `json:"refundableAmount"` // Tổng tiền của các dòng đang/đã trả lại
+	PayoutAmount     int64       `json:"payoutAmount"`     // Số tiền thực trả cho Seller khi SETTLED
+	PlatformFee      int64       `json:"platformFee"`      // Phí Sàn đã khấu trừ khi SETTLED (FeeSchedule)
+	RefundedAmount   int64       `json:"refundedAmount"`   // Tổng tiền đã hoàn cho người mua (RefundReturn)
+	ReturnIDs        []string    `json:"returnIDs,omitempty" metadata:",optional"`
+	Imported         bool        `json:"imported,omitempty" metadata:",optional"` // Đơn lịch sử nạp bằng ImportOrders (import.go)
+
 	History             []HistoryEntry `json:"history"`
+
+	// Trạng thái đã đọc từ sổ cái, dùng để cập nhật chỉ mục (status, createdAt); không lưu
+	indexedStatus Status
+}
+
+// OrderLine là một dòng sản phẩm trong đơn hàng
+type OrderLine struct {
+	LineID           string `json:"lineID"`
+	SKU              string `json:"sku"`
+	Quantity         int    `json:"quantity"`
+	UnitPrice        int64  `json:"unitPrice"`
+	ReturnedQuantity int    `json:"returnedQuantity"` // Số lượng đã nằm trong các yêu cầu trả hàng
+}
+
+// ReturnCase là một yêu cầu trả hàng (toàn phần hoặc một phần) của đơn hàng.
+// Vòng đời: RETURN_REQUESTED -> RETURN_IN_TRANSIT -> RETURN_RECEIVED -> RETURN_INSPECTED -> REFUNDED
+type ReturnCase struct {
+	DocType      string         `json:"docType"`
+	ReturnID     string         `json:"returnID"`
+	OrderID      string         `json:"orderID"`
+	Status       Status         `json:"status"`
+	FullReturn   bool           `json:"fullReturn"` // Trả toàn bộ đơn -> trạng thái đơn đi theo yêu cầu trả
+	Lines        []ReturnLine   `json:"lines,omitempty" metadata:",optional"`
+	RefundAmount int64          `json:"refundAmount"`
+	Accepted     bool           `json:"accepted"` // Kết quả kiểm hàng của Seller
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	History      []HistoryEntry `json:"history"`
+}
+
+// ReturnLine là một dòng hàng được trả trong ReturnCase
+type ReturnLine struct {
+	LineID   string `json:"lineID"`
+	Quantity int    `json:"quantity"`
+	Amount   int64  `json:"amount"`
 }
 
 // HistoryEntry lưu lại lịch sử tóm tắt của các thay đổi
@@ -36,6 +83,21 @@ type HistoryEntry struct {
 	ActorOrg  string    `json:"actorOrg"` // MSP ID của tổ chức gọi
 }
 
+// TrackingEvent là một mốc hành trình (checkpoint) do hãng vận chuyển ghi nhận
+// trong khoảng SHIPPED -> DELIVERED. Lưu theo khóa composite TrackingEvent~orderID~sequence.
+type TrackingEvent struct {
+	DocType          string    `json:"docType"`
+	OrderID          string    `json:"orderID"`
+	Sequence         int       `json:"sequence"`
+	CheckpointCode   string    `json:"checkpointCode"`
+	Location         string    `json:"location"`
+	Timestamp        time.Time `json:"timestamp"`  // Thời điểm xảy ra do hãng vận chuyển cung cấp
+	RecordedAt       time.Time `json:"recordedAt"` // Thời điểm ghi lên sổ cái (timestamp của giao dịch)
+	TxID             string    `json:"txID"`
+	ActorOrg         string    `json:"actorOrg"`
+	ShipperCompanyID string    `json:"shipperCompanyID"`
+}
+
 type QueryResult struct {
 	Key 	string 	`json:"Key"`
 	Record 	*Order 	`json:"Record"`
@@ -0,0 +1,148 @@
+// my-ecommerce-chaincode/permissions.go
+
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Phân quyền bên trong một tổ chức theo attribute 'role' trong chứng chỉ người dùng
+// (VD: role=finance, hoặc nhiều vai trò: role=finance,support). Vai trò tổ chức (MSP)
+// vẫn được kiểm tra riêng trong từng giao dịch qua requireRole.
+//
+// Chứng chỉ do cryptogen cấp (mạng gốc) không có attribute: Admin (OU=admin) là admin, các
+// user khác (VD: User1) nhận vai trò mặc định của tổ chức trong defaultUserRoles.
+const roleAttribute = "role"
+
+// UserRole là vai trò của một người dùng trong tổ chức của họ
+type UserRole string
+
+const (
+	UserAdmin     UserRole = "admin"     // Toàn quyền trong tổ chức, gồm cả cấu hình
+	UserFinance   UserRole = "finance"   // Thanh toán, thu hộ, đối soát
+	UserSupport   UserRole = "support"   // Chăm sóc khách hàng: tạo/hủy đơn, yêu cầu trả hàng
+	UserWarehouse UserRole = "warehouse" // Kho/vận hành: lấy hàng, giao hàng, nhận hàng hoàn
+	UserApprover  UserRole = "approver"  // Duyệt thanh toán/hoàn tiền lớn (người thứ hai)
+)
+
+// defaultUserRoles là vai trò của người dùng không có attribute 'role' và không phải admin,
+// theo vai trò của tổ chức: quyền thấp nhất đủ cho nghiệp vụ thường ngày của tổ chức đó.
+// Tổ chức chưa đăng ký vai trò không có vai trò mặc định.
+var defaultUserRoles = map[OrgRole]UserRole{
+	RolePlatform: UserSupport,
+	RoleSeller:   UserSupport,
+	RoleShipper:  UserWarehouse,
+}
+
+// transactionPermissions: vai trò người dùng được phép gọi từng giao dịch ghi.
+// admin luôn được phép; giao dịch không có trong bảng (truy vấn) không yêu cầu vai trò.
+var transactionPermissions = map[string][]UserRole{
+	"CreateOrder":           {UserSupport},
+	"ConfirmPayment":        {UserFinance},
+	"CancelOrder":           {UserSupport},
+	"ShipOrder":             {UserWarehouse},
+	"ConfirmDelivery":       {UserWarehouse},
+	"ConfirmCODDelivery":    {UserWarehouse},
+	"RemitCOD":              {UserFinance},
+	"PayoutToSeller":        {UserFinance},
+	"RefundReturn":          {UserFinance},
+	"ApproveAction":         {UserApprover},
+	"RequestReturn":         {UserSupport},
+	"ShipReturn":            {UserWarehouse},
+	"ConfirmReturnReceived": {UserWarehouse},
+	"InspectReturn":         {UserWarehouse},
+	"AddTrackingEvent":      {UserWarehouse},
+	"ExpireUnpaidOrders":    {UserSupport},
+	"SetPolicyConfig":       {},
+	"SetRoleRegistry":       {},
+	"Freeze":                {},
+	"Unfreeze":              {},
+	"MigrateOrders":         {},
+	"ImportOrders":          {},
+	"RebuildOrderCounters":  {},
+	"InitLedger":            {},
+}
+
+// getCallerUserRoles đọc các vai trò của người gọi từ attribute 'role'.
+// Chứng chỉ không có attribute: admin của tổ chức (OU=admin, VD: Admin@ecommerce.com do
+// cryptogen cấp) được coi là admin, người dùng khác nhận defaultUserRoles của tổ chức.
+func getCallerUserRoles(ctx contractapi.TransactionContextInterface) (map[UserRole]bool, error) {
+	roles := make(map[UserRole]bool)
+	value, found, err := ctx.GetClientIdentity().GetAttributeValue(roleAttribute)
+	if err != nil {
+		return nil, newError(ctx, ErrIdentity, errorDetails{"cause": err.Error()})
+	}
+	if found {
+		for _, role := range strings.Split(value, ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles[UserRole(role)] = true
+			}
+		}
+		return roles, nil
+	}
+
+	cert, err := ctx.GetClientIdentity().GetX509Certificate()
+	if err != nil {
+		return nil, newError(ctx, ErrIdentity, errorDetails{"cause": err.Error()})
+	}
+	if cert != nil {
+		for _, ou := range cert.Subject.OrganizationalUnit {
+			if ou == "admin" {
+				roles[UserAdmin] = true
+				return roles, nil
+			}
+		}
+	}
+
+	_, orgRole, err := getActorRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if role, ok := defaultUserRoles[orgRole]; ok {
+		roles[role] = true
+	}
+	return roles, nil
+}
+
+// requireUserRole: Người gọi phải có một trong các vai trò (admin luôn được phép)
+func requireUserRole(ctx contractapi.TransactionContextInterface, allowed ...UserRole) error {
+	roles, err := getCallerUserRoles(ctx)
+	if err != nil {
+		return err
+	}
+	if roles[UserAdmin] {
+		return nil
+	}
+	for _, role := range allowed {
+		if roles[role] {
+			return nil
+		}
+	}
+
+	required := []string{string(UserAdmin)}
+	for _, role := range allowed {
+		required = append(required, string(role))
+	}
+	held := make([]string, 0, len(roles))
+	for role := range roles {
+		held = append(held, string(role))
+	}
+	sort.Strings(held)
+	return newError(ctx, ErrRoleRequired, errorDetails{
+		"requiredRole": strings.Join(required, "|"),
+		"callerRoles":  strings.Join(held, ","),
+	})
+}
+
+// checkTransactionPermission kiểm tra vai trò người gọi theo transactionPermissions
+func checkTransactionPermission(ctx contractapi.TransactionContextInterface) error {
+	function, _ := currentFunction(ctx)
+	allowed, ok := transactionPermissions[function]
+	if !ok {
+		return nil
+	}
+	return requireUserRole(ctx, allowed...)
+}
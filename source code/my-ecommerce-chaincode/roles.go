@@ -0,0 +1,216 @@
+// my-ecommerce-chaincode/roles.go
+
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// OrgRole là vai trò nghiệp vụ của một tổ chức (MSP) trên kênh
+type OrgRole string
+
+const (
+	RolePlatform OrgRole = "PLATFORM" // Sàn: xác nhận thanh toán, đối soát, trả hàng
+	RoleSeller   OrgRole = "SELLER"   // Người bán: tạo đơn, nhận hàng hoàn
+	RoleShipper  OrgRole = "SHIPPER"  // Đơn vị vận chuyển: giao hàng, thu hộ
+)
+
+var validOrgRoles = map[OrgRole]bool{
+	RolePlatform: true,
+	RoleSeller:   true,
+	RoleShipper:  true,
+}
+
+// RoleRegistry ánh xạ MSP ID -> vai trò, lưu trên sổ cái để thêm tổ chức mới
+// (VD: Org3 theo addOrg3) mà không cần sửa chaincode.
+// Companies gán mã công ty (Shop / hãng vận chuyển) cho MSP riêng của công ty đó;
+// công ty không có trong danh sách dùng chung MSP mặc định của vai trò.
+type RoleRegistry struct {
+	Orgs      map[string]OrgRole `json:"orgs"`
+	Companies map[string]string  `json:"companies,omitempty" metadata:",optional"` // companyID -> MSP ID
+}
+
+// defaultRoleRegistry là sổ đăng ký của mạng gốc, dùng khi sổ cái chưa có bản ghi
+func defaultRoleRegistry() *RoleRegistry {
+	return &RoleRegistry{Orgs: map[string]OrgRole{
+		"ECommercePlatformOrgMSP": RolePlatform,
+		"SellerOrgMSP":            RoleSeller,
+		"ShipperOrgMSP":           RoleShipper,
+	}}
+}
+
+// validate kiểm tra vai trò hợp lệ và luôn còn ít nhất một tổ chức Sàn
+func (r *RoleRegistry) validate(ctx contractapi.TransactionContextInterface) error {
+	hasPlatform := false
+	for mspID, role := range r.Orgs {
+		if mspID == "" {
+			return errInvalidArgument(ctx, "orgs", "empty MSP ID")
+		}
+		if !validOrgRoles[role] {
+			return errInvalidArgument(ctx, "orgs["+mspID+"]", "PLATFORM|SELLER|SHIPPER")
+		}
+		hasPlatform = hasPlatform || role == RolePlatform
+	}
+	if !hasPlatform {
+		return errInvalidArgument(ctx, "orgs", "at least one PLATFORM organization")
+	}
+	for companyID, mspID := range r.Companies {
+		if role := r.Orgs[mspID]; role != RoleSeller && role != RoleShipper {
+			return errInvalidArgument(ctx, "companies["+companyID+"]", "MSP with role SELLER|SHIPPER")
+		}
+	}
+	return nil
+}
+
+// orgsWithRole trả về các MSP có vai trò cho trước (đã sắp xếp để kết quả tất định)
+func (r *RoleRegistry) orgsWithRole(role OrgRole) []string {
+	var mspIDs []string
+	for mspID, orgRole := range r.Orgs {
+		if orgRole == role {
+			mspIDs = append(mspIDs, mspID)
+		}
+	}
+	sort.Strings(mspIDs)
+	return mspIDs
+}
+
+func roleRegistryKey(ctx contractapi.TransactionContextInterface) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(configObjectType, []string{"roles"})
+}
+
+// getRoleRegistry: Đọc sổ đăng ký vai trò, trả về mặc định nếu chưa được thiết lập
+func getRoleRegistry(ctx contractapi.TransactionContextInterface) (*RoleRegistry, error) {
+	key, err := roleRegistryKey(ctx)
+	if err != nil {
+		return nil, errLedger(ctx, "CreateCompositeKey", err)
+	}
+	registryJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, errLedger(ctx, "GetState", err)
+	}
+	if registryJSON == nil {
+		return defaultRoleRegistry(), nil
+	}
+
+	var registry RoleRegistry
+	if err := json.Unmarshal(registryJSON, &registry); err != nil {
+		return nil, errLedger(ctx, "unmarshal", err)
+	}
+	return &registry, nil
+}
+
+// saveRoleRegistry: Lưu sổ đăng ký vai trò vào sổ cái
+func saveRoleRegistry(ctx contractapi.TransactionContextInterface, registry *RoleRegistry) error {
+	key, err := roleRegistryKey(ctx)
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	registryJSON, err := json.Marshal(registry)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	return ctx.GetStub().PutState(key, registryJSON)
+}
+
+// getActorRole trả về MSP của người gọi và vai trò của MSP đó (rỗng nếu chưa đăng ký)
+func getActorRole(ctx contractapi.TransactionContextInterface) (string, OrgRole, error) {
+	actorOrg, err := getActorOrg(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	registry, err := getRoleRegistry(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return actorOrg, registry.Orgs[actorOrg], nil
+}
+
+// requireRole: Chỉ cho phép tổ chức có một trong các vai trò được liệt kê, trả về MSP người gọi
+func requireRole(ctx contractapi.TransactionContextInterface, allowed ...OrgRole) (string, error) {
+	actorOrg, role, err := getActorRole(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, allowedRole := range allowed {
+		if role == allowedRole {
+			return actorOrg, nil
+		}
+	}
+
+	names := make([]string, len(allowed))
+	for i, allowedRole := range allowed {
+		names[i] = string(allowedRole)
+	}
+	return "", errAccessDenied(ctx, actorOrg, strings.Join(names, "|"))
+}
+
+// resolveShipperOrg: Xác định MSP vận chuyển cho đơn của hãng shipperCompanyID.
+// Hãng có MSP riêng được tra trong Companies; các hãng còn lại dùng chung tổ chức
+// SHIPPER duy nhất (phân biệt bằng companyCode như mạng gốc).
+func resolveShipperOrg(ctx contractapi.TransactionContextInterface, shipperCompanyID string) (string, error) {
+	registry, err := getRoleRegistry(ctx)
+	if err != nil {
+		return "", err
+	}
+	if mspID, ok := registry.Companies[shipperCompanyID]; ok {
+		if registry.Orgs[mspID] != RoleShipper {
+			return "", errInvalidArgument(ctx, "shipperCompanyID", shipperCompanyID+" is not a carrier")
+		}
+		return mspID, nil
+	}
+	shipperOrgs := registry.orgsWithRole(RoleShipper)
+	if len(shipperOrgs) != 1 {
+		return "", errInvalidArgument(ctx, "shipperCompanyID", "cannot resolve a single SHIPPER organization for "+shipperCompanyID)
+	}
+	return shipperOrgs[0], nil
+}
+
+// checkCompanyOrg: Mã công ty đã được gán MSP riêng thì chỉ MSP đó được dùng mã này
+func checkCompanyOrg(ctx contractapi.TransactionContextInterface, companyID string, actorOrg string) error {
+	registry, err := getRoleRegistry(ctx)
+	if err != nil {
+		return err
+	}
+	if mspID, ok := registry.Companies[companyID]; ok && mspID != actorOrg {
+		return errAccessDenied(ctx, actorOrg, mspID)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] SetRoleRegistry: Sàn cập nhật sổ đăng ký MSP -> vai trò (JSON của RoleRegistry)
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) SetRoleRegistry(ctx contractapi.TransactionContextInterface, registryJSON string) error {
+	if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+		return err
+	}
+
+	actorOrg, err := requireRole(ctx, RolePlatform)
+	if err != nil {
+		return err
+	}
+
+	var registry RoleRegistry
+	if err := json.Unmarshal([]byte(registryJSON), &registry); err != nil {
+		return errInvalidArgument(ctx, "registryJSON", err.Error())
+	}
+	if err := registry.validate(ctx); err != nil {
+		return err
+	}
+	// Không cho Sàn tự tước quyền của chính mình trong cùng một giao dịch
+	if registry.Orgs[actorOrg] != RolePlatform {
+		return errInvalidArgument(ctx, "orgs["+actorOrg+"]", string(RolePlatform))
+	}
+	return saveRoleRegistry(ctx, &registry)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetRoleRegistry: Xem sổ đăng ký vai trò hiện hành
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetRoleRegistry(ctx contractapi.TransactionContextInterface) (*RoleRegistry, error) {
+	return getRoleRegistry(ctx)
+}
@@ -0,0 +1,409 @@
+// my-ecommerce-chaincode/scenario_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kịch bản YAML chạy trên Simulator: mỗi bước gọi một giao dịch với một định danh (hoặc dời
+// đồng hồ) và kiểm tra kết quả / mã lỗi. Mọi file scenarios/*.yaml chạy trong TestScenarios:
+//
+//	go test -run TestScenarios -v          # Tất cả kịch bản, in từng bước
+//	go test -run TestScenarios/user_roles  # Một kịch bản
+//
+// Ví dụ:
+//
+//	name: Giao hàng rồi đối soát
+//	start: 2026-01-01T08:00:00Z
+//	identities:                      # Bổ sung vào DefaultSimIdentities
+//	  seller2: {mspID: SellerOrgMSP, companyCode: Shop_XYZ}
+//	steps:
+//	  - as: seller
+//	    call: CreateOrder
+//	    args: {orderID: SO001, paymentMethod: PREPAID, shipperCompanyID: GHN,
+//	           linesJSON: [{lineID: L1, sku: A, quantity: 1, unitPrice: 100000}]}
+//	    expect: {event: OrderChanged}
+//	  - advance: 7d
+//	  - as: seller2
+//	    query: QueryOrder
+//	    args: [SO001]
+//	    expect: {error: COMPANY_MISMATCH}
+//
+// args là danh sách theo vị trí hoặc map theo tên tham số (transactionArgRules); giá trị không
+// phải chuỗi được ghi thành JSON, ${now} / ${now-1h} là thời điểm của đồng hồ (RFC3339).
+
+// Scenario là một kịch bản
+type Scenario struct {
+	Name       string                 `yaml:"name"`
+	Start      time.Time              `yaml:"start"` // Mặc định 2026-01-01T00:00:00Z
+	Identities map[string]SimIdentity `yaml:"identities"`
+	Steps      []ScenarioStep         `yaml:"steps"`
+}
+
+// ScenarioStep là một bước: đúng một trong call, query, advance
+type ScenarioStep struct {
+	Name      string            `yaml:"name"`
+	As        string            `yaml:"as"`
+	Call      string            `yaml:"call"`  // Giao dịch ghi (submit)
+	Query     string            `yaml:"query"` // Truy vấn (evaluate)
+	Args      interface{}       `yaml:"args"`
+	Transient map[string]string `yaml:"transient"`
+	Advance   string            `yaml:"advance"` // VD: 5m, 7d, 1d12h
+	Expect    ScenarioExpect    `yaml:"expect"`
+}
+
+// ScenarioExpect là điều kiện của một bước; không khai báo error thì giao dịch phải thành công
+type ScenarioExpect struct {
+	Error  ErrorCode   `yaml:"error"`  // Mã lỗi của hợp đồng
+	Result interface{} `yaml:"result"` // So khớp một phần: chỉ các trường được khai báo
+	Event  string      `yaml:"event"`  // Tên sự kiện chaincode
+}
+
+// defaultScenarioStart là thời điểm bắt đầu khi kịch bản không khai báo start
+var defaultScenarioStart = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// LoadScenario đọc kịch bản từ file YAML
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var scenario Scenario
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&scenario); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if scenario.Name == "" {
+		scenario.Name = path
+	}
+	return &scenario, nil
+}
+
+// TestScenarios chạy mỗi file scenarios/*.yaml như một kiểm thử con (tên theo tên file)
+func TestScenarios(t *testing.T) {
+	files, err := filepath.Glob("scenarios/*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("không có kịch bản nào trong scenarios/")
+	}
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".yaml"), func(t *testing.T) {
+			var log strings.Builder
+			err := RunScenarioFile(file, &log)
+			t.Log("\n" + log.String())
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// RunScenarioFile đọc và chạy một file kịch bản
+func RunScenarioFile(path string, log io.Writer) error {
+	scenario, err := LoadScenario(path)
+	if err != nil {
+		return err
+	}
+	return RunScenario(scenario, log)
+}
+
+// RunScenario chạy kịch bản trên một Simulator mới, dừng ở bước sai đầu tiên. log nhận một
+// dòng cho mỗi bước (nil = không ghi).
+func RunScenario(scenario *Scenario, log io.Writer) error {
+	if log == nil {
+		log = io.Discard
+	}
+	start := scenario.Start
+	if start.IsZero() {
+		start = defaultScenarioStart
+	}
+	sim, err := NewSimulator(start)
+	if err != nil {
+		return err
+	}
+	for name, identity := range scenario.Identities {
+		if err := sim.AddIdentity(name, identity); err != nil {
+			return fmt.Errorf("%s: %w", scenario.Name, err)
+		}
+	}
+
+	fmt.Fprintf(log, "=== %s\n", scenario.Name)
+	for i := range scenario.Steps {
+		step := &scenario.Steps[i]
+		label := fmt.Sprintf("bước %d", i+1)
+		if step.Name != "" {
+			label += " (" + step.Name + ")"
+		}
+		summary, err := runScenarioStep(sim, step)
+		if err != nil {
+			fmt.Fprintf(log, "FAIL %s: %s\n", label, summary)
+			return fmt.Errorf("%s, %s: %w", scenario.Name, label, err)
+		}
+		fmt.Fprintf(log, "ok   %s: %s\n", label, summary)
+	}
+	return nil
+}
+
+// runScenarioStep chạy một bước, trả về mô tả ngắn cho log
+func runScenarioStep(sim *Simulator, step *ScenarioStep) (string, error) {
+	actions := 0
+	for _, set := range []bool{step.Call != "", step.Query != "", step.Advance != ""} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return "", fmt.Errorf("mỗi bước cần đúng một trong call, query, advance")
+	}
+
+	if step.Advance != "" {
+		d, err := parseScenarioDuration(step.Advance)
+		if err != nil {
+			return "", err
+		}
+		sim.Advance(d)
+		return fmt.Sprintf("đồng hồ +%s -> %s", step.Advance, sim.Now().Format(time.RFC3339)), nil
+	}
+
+	function, evaluate := step.Call, false
+	if step.Query != "" {
+		function, evaluate = step.Query, true
+	}
+	summary := fmt.Sprintf("%s bởi %s", function, step.As)
+	args, err := scenarioArgs(function, step.Args, sim.Now())
+	if err != nil {
+		return summary, err
+	}
+	call := SimCall{As: step.As, Function: function, Args: args, Evaluate: evaluate}
+	for key, value := range step.Transient {
+		if call.Transient == nil {
+			call.Transient = make(map[string][]byte)
+		}
+		call.Transient[key] = []byte(value)
+	}
+
+	result, err := sim.Invoke(call)
+	return summary, checkScenarioExpect(&step.Expect, result, err)
+}
+
+func checkScenarioExpect(expect *ScenarioExpect, result *SimResult, err error) error {
+	if err != nil {
+		contractErr, ok := err.(*ContractError)
+		switch {
+		case expect.Error == "":
+			return fmt.Errorf("giao dịch lỗi: %v", err)
+		case !ok:
+			return fmt.Errorf("mong đợi lỗi %s, nhận lỗi: %v", expect.Error, err)
+		case contractErr.Code != expect.Error:
+			return fmt.Errorf("mong đợi lỗi %s, nhận %s: %s", expect.Error, contractErr.Code, contractErr.Message)
+		}
+		return nil
+	}
+	if expect.Error != "" {
+		return fmt.Errorf("mong đợi lỗi %s nhưng giao dịch thành công", expect.Error)
+	}
+
+	if expect.Event != "" {
+		if result.Event == nil {
+			return fmt.Errorf("mong đợi sự kiện %s, giao dịch không phát sự kiện", expect.Event)
+		}
+		if result.Event.Name != expect.Event {
+			return fmt.Errorf("mong đợi sự kiện %s, nhận %s", expect.Event, result.Event.Name)
+		}
+	}
+	if expect.Result != nil {
+		expected, err := normalizeJSON(expect.Result)
+		if err != nil {
+			return fmt.Errorf("expect.result không hợp lệ: %w", err)
+		}
+		var actual interface{}
+		if err := json.Unmarshal(result.Payload, &actual); err != nil {
+			return fmt.Errorf("kết quả không phải JSON: %q", result.Payload)
+		}
+		if err := matchSubset("result", expected, actual); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchSubset so khớp expected với actual: map chỉ xét các khóa của expected, danh sách phải
+// cùng độ dài, giá trị đơn phải bằng nhau
+func matchSubset(path string, expected interface{}, actual interface{}) error {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: mong đợi object, nhận %s", path, compactJSON(actual))
+		}
+		for key, value := range expected {
+			actualValue, ok := actualMap[key]
+			if !ok {
+				return fmt.Errorf("%s.%s: không có trong kết quả", path, key)
+			}
+			if err := matchSubset(path+"."+key, value, actualValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		actualList, ok := actual.([]interface{})
+		if !ok || len(actualList) != len(expected) {
+			return fmt.Errorf("%s: mong đợi %d phần tử, nhận %s", path, len(expected), compactJSON(actual))
+		}
+		for i := range expected {
+			if err := matchSubset(fmt.Sprintf("%s[%d]", path, i), expected[i], actualList[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("%s: mong đợi %s, nhận %s", path, compactJSON(expected), compactJSON(actual))
+	}
+	return nil
+}
+
+// scenarioArgs chuyển args của bước thành tham số theo vị trí
+func scenarioArgs(function string, args interface{}, now time.Time) ([]string, error) {
+	switch args := args.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		values := make([]string, len(args))
+		for i, arg := range args {
+			value, err := scenarioArgValue(arg, now)
+			if err != nil {
+				return nil, fmt.Errorf("args[%d]: %w", i, err)
+			}
+			values[i] = value
+		}
+		return values, nil
+	case map[string]interface{}:
+		rules, ok := transactionArgRules[function]
+		if !ok {
+			return nil, fmt.Errorf("%s không có tên tham số trong transactionArgRules, dùng danh sách theo vị trí", function)
+		}
+		values := make([]string, len(rules))
+		used := 0
+		for i, rule := range rules {
+			arg, ok := args[rule.name]
+			if !ok {
+				continue
+			}
+			used++
+			value, err := scenarioArgValue(arg, now)
+			if err != nil {
+				return nil, fmt.Errorf("args.%s: %w", rule.name, err)
+			}
+			values[i] = value
+		}
+		if used != len(args) {
+			names := make([]string, len(rules))
+			for i, rule := range rules {
+				names[i] = rule.name
+			}
+			return nil, fmt.Errorf("args có tham số không thuộc %s(%s)", function, strings.Join(names, ", "))
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("args phải là danh sách hoặc map")
+}
+
+// nowPattern khớp ${now}, ${now+2h}, ${now-30m}...
+var nowPattern = regexp.MustCompile(`\$\{now([+-][0-9a-z.]+)?\}`)
+
+func scenarioArgValue(arg interface{}, now time.Time) (string, error) {
+	switch arg := arg.(type) {
+	case nil:
+		return "", nil
+	case string:
+		var err error
+		value := nowPattern.ReplaceAllStringFunc(arg, func(match string) string {
+			offset := nowPattern.FindStringSubmatch(match)[1]
+			t := now
+			if offset != "" {
+				d, parseErr := parseScenarioDuration(offset[1:])
+				if parseErr != nil {
+					err = parseErr
+					return match
+				}
+				if offset[0] == '-' {
+					d = -d
+				}
+				t = now.Add(d)
+			}
+			return t.UTC().Format(time.RFC3339)
+		})
+		return value, err
+	case bool:
+		return strconv.FormatBool(arg), nil
+	case int:
+		return strconv.Itoa(arg), nil
+	case float64:
+		return strconv.FormatFloat(arg, 'f', -1, 64), nil
+	case time.Time:
+		return arg.UTC().Format(time.RFC3339), nil
+	}
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseScenarioDuration nhận thêm đơn vị ngày: 7d, 1d12h
+func parseScenarioDuration(value string) (time.Duration, error) {
+	var days time.Duration
+	if before, after, found := strings.Cut(value, "d"); found {
+		n, err := strconv.Atoi(before)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("khoảng thời gian không hợp lệ: %q", value)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		if after == "" {
+			return days, nil
+		}
+		value = after
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("khoảng thời gian không hợp lệ: %q", value)
+	}
+	return days + d, nil
+}
+
+// normalizeJSON đưa giá trị đọc từ YAML về cùng kiểu với json.Unmarshal (số là float64...)
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func compactJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
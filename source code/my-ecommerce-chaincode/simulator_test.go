@@ -0,0 +1,350 @@
+// my-ecommerce-chaincode/simulator_test.go
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Simulator chạy SmartContract ngay trong tiến trình với world state trong bộ nhớ, định danh
+// của cả ba tổ chức và đồng hồ điều khiển được, để chạy kịch bản (scenario_test.go) hoặc viết
+// kiểm thử mà không cần mạng Docker:
+//
+//	sim, _ := NewSimulator(time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC))
+//	sim.Submit("seller", "CreateOrder", "SO001", "PREPAID", "GHN", "", "", "", "")
+//	sim.Advance(7 * 24 * time.Hour)
+//	_, err := sim.Submit("platform", "PayoutToSeller", "SO001")
+//
+// Giống peer thật: giao dịch lỗi không ghi gì; các lệnh đọc trong giao dịch thấy trạng thái đã
+// commit (không thấy chính các lệnh ghi của nó); chỉ sự kiện của giao dịch thành công được phát.
+// Mock không có CouchDB nên QueryOrdersByString và GetHistoryForKey không dùng được.
+type Simulator struct {
+	stub       *shimtest.MockStub
+	chaincode  *observedChaincode // Như main: log (bỏ đi) và số liệu đi qua cùng lớp bọc
+	now        time.Time
+	identities map[string]*simIdentity
+	txCount    int
+	events     []SimEvent
+}
+
+// SimIdentity mô tả một người dùng: MSP của tổ chức và các attribute trong chứng chỉ
+type SimIdentity struct {
+	MSPID       string `yaml:"mspID"`
+	CompanyCode string `yaml:"companyCode"` // Rỗng = chứng chỉ không có companyCode
+	Role        string `yaml:"role"`        // Vai trò trong tổ chức (permissions.go), rỗng = không có attribute như user của cryptogen
+}
+
+// DefaultSimIdentities là các định danh có sẵn, khớp mạng gốc và init_bootstrap.json.
+// platform / seller / shipper là admin của tổ chức; approver là người duyệt thứ hai của Sàn
+// (approvals.go); các định danh còn lại chỉ có một vai trò, hoặc không có attribute role
+// (platform_user, seller_user, shipper_user như User1 của cryptogen) để thử đường bị từ chối.
+var DefaultSimIdentities = map[string]SimIdentity{
+	"platform":         {MSPID: "ECommercePlatformOrgMSP", Role: string(UserAdmin)},
+	"approver":         {MSPID: "ECommercePlatformOrgMSP", Role: string(UserApprover)},
+	"platform_finance": {MSPID: "ECommercePlatformOrgMSP", Role: string(UserFinance)},
+	"platform_user":    {MSPID: "ECommercePlatformOrgMSP"},
+	"seller":           {MSPID: "SellerOrgMSP", CompanyCode: "Shop_ABC", Role: string(UserAdmin)},
+	"seller_support":   {MSPID: "SellerOrgMSP", CompanyCode: "Shop_ABC", Role: string(UserSupport)},
+	"seller_user":      {MSPID: "SellerOrgMSP", CompanyCode: "Shop_ABC"},
+	"shipper":          {MSPID: "ShipperOrgMSP", CompanyCode: "GHN", Role: string(UserAdmin)},
+	"shipper_user":     {MSPID: "ShipperOrgMSP", CompanyCode: "GHN"},
+}
+
+type simIdentity struct {
+	SimIdentity
+	creator []byte // msp.SerializedIdentity
+}
+
+// SimResult là kết quả của một giao dịch thành công
+type SimResult struct {
+	TxID    string
+	Payload []byte
+	Event   *SimEvent // nil nếu giao dịch không phát sự kiện
+}
+
+// SimEvent là sự kiện chaincode của một giao dịch đã commit
+type SimEvent struct {
+	TxID    string
+	Name    string
+	Payload []byte
+}
+
+// SimCall là một lần gọi giao dịch
+type SimCall struct {
+	As        string // Tên định danh
+	Function  string
+	Args      []string
+	Transient map[string][]byte // VD: requestID, locale
+	Evaluate  bool              // Chỉ truy vấn (evaluate): không ghi sổ cái kể cả khi thành công
+}
+
+// NewSimulator tạo sổ cái rỗng với đồng hồ bắt đầu tại start và các định danh mặc định
+func NewSimulator(start time.Time) (*Simulator, error) {
+	chaincode, err := contractapi.NewChaincode(&SmartContract{})
+	if err != nil {
+		return nil, err
+	}
+	sim := &Simulator{
+		stub:       shimtest.NewMockStub("ecommerce", chaincode),
+		chaincode:  newObservedChaincode(chaincode, newJSONLogger(io.Discard, levelError), noMetrics{}),
+		now:        start,
+		identities: make(map[string]*simIdentity),
+	}
+	for name, identity := range DefaultSimIdentities {
+		if err := sim.AddIdentity(name, identity); err != nil {
+			return nil, err
+		}
+	}
+	return sim, nil
+}
+
+// AddIdentity thêm (hoặc thay) định danh name với chứng chỉ tự ký mới
+func (s *Simulator) AddIdentity(name string, identity SimIdentity) error {
+	if identity.MSPID == "" {
+		return fmt.Errorf("định danh %s thiếu mspID", name)
+	}
+	creator, err := newSimCreator(name, identity)
+	if err != nil {
+		return fmt.Errorf("không tạo được chứng chỉ cho %s: %w", name, err)
+	}
+	s.identities[name] = &simIdentity{SimIdentity: identity, creator: creator}
+	return nil
+}
+
+// Now trả về thời điểm hiện tại của đồng hồ (timestamp của giao dịch kế tiếp)
+func (s *Simulator) Now() time.Time {
+	return s.now
+}
+
+// Advance dời đồng hồ, VD: qua hết thời hạn trả hàng
+func (s *Simulator) Advance(d time.Duration) {
+	s.now = s.now.Add(d)
+}
+
+// Events trả về các sự kiện đã phát, theo thứ tự commit
+func (s *Simulator) Events() []SimEvent {
+	return s.events
+}
+
+// State đọc giá trị đã commit của một khóa (nil nếu không có)
+func (s *Simulator) State(key string) []byte {
+	return s.stub.State[key]
+}
+
+// Submit gửi giao dịch ghi; lỗi của hợp đồng là *ContractError
+func (s *Simulator) Submit(as string, function string, args ...string) (*SimResult, error) {
+	return s.Invoke(SimCall{As: as, Function: function, Args: args})
+}
+
+// Evaluate gọi truy vấn; mọi lệnh ghi bị bỏ
+func (s *Simulator) Evaluate(as string, function string, args ...string) (*SimResult, error) {
+	return s.Invoke(SimCall{As: as, Function: function, Args: args, Evaluate: true})
+}
+
+// Invoke chạy một giao dịch. Lỗi của hợp đồng là *ContractError (lỗi của contractapi, VD: sai
+// số tham số, là error thường).
+func (s *Simulator) Invoke(call SimCall) (*SimResult, error) {
+	identity, ok := s.identities[call.As]
+	if !ok {
+		return nil, fmt.Errorf("không có định danh %q", call.As)
+	}
+	s.txCount++
+	txID := simTxID(s.txCount)
+
+	s.stub.Creator = identity.creator
+	s.stub.MockTransactionStart(txID)
+	defer s.stub.MockTransactionEnd(txID)
+	if len(call.Transient) > 0 {
+		if err := s.stub.SetTransient(call.Transient); err != nil {
+			return nil, err
+		}
+	}
+	defer func() { s.stub.TransientMap = nil }()
+
+	tx := &simStub{MockStub: s.stub, now: s.now, function: call.Function, params: call.Args}
+	response := s.chaincode.Invoke(tx)
+	if response.Status != 200 {
+		return nil, parseContractError(response.Message)
+	}
+
+	result := &SimResult{TxID: txID, Payload: response.Payload}
+	if call.Evaluate {
+		return result, nil
+	}
+	if err := tx.commit(); err != nil {
+		return nil, err
+	}
+	if tx.event != nil {
+		event := SimEvent{TxID: txID, Name: tx.event.Name, Payload: tx.event.Payload}
+		s.events = append(s.events, event)
+		result.Event = &event
+	}
+	return result, nil
+}
+
+// parseContractError đọc lại lỗi JSON {code, message, details} của hợp đồng
+func parseContractError(message string) error {
+	var contractErr ContractError
+	if err := json.Unmarshal([]byte(message), &contractErr); err != nil || contractErr.Code == "" {
+		return errors.New(message)
+	}
+	return &contractErr
+}
+
+// simStub là một giao dịch đang chạy: đọc trạng thái đã commit của MockStub, giữ các lệnh ghi,
+// chính sách endorse và sự kiện lại tới khi giao dịch thành công
+type simStub struct {
+	*shimtest.MockStub
+	now      time.Time
+	function string
+	params   []string
+	writes   map[string][]byte // nil = xóa
+	policies map[string][]byte
+	event    *SimEvent
+}
+
+func (t *simStub) GetTxTimestamp() (*timestamppb.Timestamp, error) {
+	return timestamppb.New(t.now), nil
+}
+
+func (t *simStub) GetFunctionAndParameters() (string, []string) {
+	return t.function, t.params
+}
+
+func (t *simStub) GetStringArgs() []string {
+	return append([]string{t.function}, t.params...)
+}
+
+func (t *simStub) GetArgs() [][]byte {
+	args := make([][]byte, 0, len(t.params)+1)
+	for _, arg := range t.GetStringArgs() {
+		args = append(args, []byte(arg))
+	}
+	return args
+}
+
+func (t *simStub) PutState(key string, value []byte) error {
+	if key == "" {
+		return errors.New("key must not be an empty string")
+	}
+	if t.writes == nil {
+		t.writes = make(map[string][]byte)
+	}
+	t.writes[key] = append([]byte{}, value...)
+	return nil
+}
+
+func (t *simStub) DelState(key string) error {
+	if t.writes == nil {
+		t.writes = make(map[string][]byte)
+	}
+	t.writes[key] = nil
+	return nil
+}
+
+func (t *simStub) SetStateValidationParameter(key string, policy []byte) error {
+	if t.policies == nil {
+		t.policies = make(map[string][]byte)
+	}
+	t.policies[key] = policy
+	return nil
+}
+
+func (t *simStub) SetEvent(name string, payload []byte) error {
+	if name == "" {
+		return errors.New("event name can not be empty string")
+	}
+	t.event = &SimEvent{Name: name, Payload: payload}
+	return nil
+}
+
+// commit ghi write-set vào MockStub theo thứ tự khóa như peer
+func (t *simStub) commit() error {
+	keys := make([]string, 0, len(t.writes))
+	for key := range t.writes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var err error
+		if value := t.writes[key]; value == nil {
+			err = t.MockStub.DelState(key)
+		} else {
+			err = t.MockStub.PutState(key, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for key, policy := range t.policies {
+		if err := t.MockStub.SetStateValidationParameter(key, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// simTxID sinh TxID cố định theo thứ tự giao dịch (64 ký tự hex như TxID thật)
+func simTxID(n int) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("simulator-tx-%d", n)))
+	return hex.EncodeToString(hash[:])
+}
+
+// attributeOID là extension chứa attribute của Fabric CA (đọc bởi cid.GetAttributeValue)
+var attributeOID = asn1.ObjectIdentifier{1, 2, 3, 4, 5, 6, 7, 8, 1}
+
+// newSimCreator tạo chứng chỉ tự ký với attribute role / companyCode (nếu có), đóng gói như creator
+// của proposal. Mỗi tên có CN riêng nên là một người dùng riêng (ID khác nhau khi duyệt).
+func newSimCreator(name string, identity SimIdentity) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	attrs := map[string]string{}
+	if identity.Role != "" {
+		attrs[roleAttribute] = identity.Role
+	}
+	if identity.CompanyCode != "" {
+		attrs["companyCode"] = identity.CompanyCode
+	}
+	attrsJSON, err := json.Marshal(map[string]interface{}{"attrs": attrs})
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(time.Now().UnixNano()),
+		Subject:         pkix.Name{CommonName: name, Organization: []string{identity.MSPID}},
+		NotBefore:       time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:        time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExtraExtensions: []pkix.Extension{{Id: attributeOID, Value: attrsJSON}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&msp.SerializedIdentity{
+		Mspid:   identity.MSPID,
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+}
@@ -14,6 +14,9 @@ type SmartContract struct {
     contractapi.Contract
 }
 
+// Loại đối tượng dùng cho khóa composite (tách biệt với khóa orderID thường)
+const returnCaseObjectType = "ReturnCase"
+
 
 // ===================================================================================
 // CÁC HÀM HELPER (Hỗ trợ)
@@ -23,7 +26,7 @@ type SmartContract struct {
 func getActorOrg(ctx contractapi.TransactionContextInterface) (string, error) {
     mspID, err := ctx.GetClientIdentity().GetMSPID()
     if err != nil {
-        return "", fmt.Errorf("không thể lấy MSPID: %v", err)
+        return "", newError(ctx, ErrIdentity, errorDetails{"cause": err.Error()})
     }
     return mspID, nil
 }
@@ -32,7 +35,7 @@ func getActorOrg(ctx contractapi.TransactionContextInterface) (string, error) {
 func getCallerCompanyID(ctx contractapi.TransactionContextInterface) (string, error) {
     val, found, err := ctx.GetClientIdentity().GetAttributeValue("companyCode")
     if err != nil {
-        return "", fmt.Errorf("lỗi đọc attribute: %v", err)
+        return "", newError(ctx, ErrIdentity, errorDetails{"cause": err.Error()})
     }
     if !found {
         return "", nil 
@@ -44,34 +47,49 @@ func getCallerCompanyID(ctx contractapi.TransactionContextInterface) (string, er
 func getOrderState(ctx contractapi.TransactionContextInterface, orderID string) (*Order, error) {
     orderJSON, err := ctx.GetStub().GetState(orderID)
     if err != nil {
-        return nil, fmt.Errorf("lỗi đọc world state: %v", err)
+        return nil, errLedger(ctx, "GetState", err)
     }
     if orderJSON == nil {
-        return nil, fmt.Errorf("đơn hàng %s không tồn tại", orderID)
+        return nil, newError(ctx, ErrOrderNotFound, errorDetails{"orderID": orderID})
     }
 
-    var order Order
-    err = json.Unmarshal(orderJSON, &order)
+    // Bản ghi cũ được nâng lên lược đồ hiện tại trong bộ nhớ (xem migration.go)
+    order, _, err := decodeOrder(orderJSON)
     if err != nil {
-        return nil, fmt.Errorf("lỗi unmarshal JSON: %v", err)
+        return nil, errLedger(ctx, "unmarshal", err)
     }
-    return &order, nil
+    return order, nil
 }
 
-// saveOrderState: Lưu order vào sổ cái
+// saveOrderState: Lưu order vào sổ cái (kèm cập nhật chỉ mục trạng thái và sự kiện OrderChanged).
+// Đơn đang bị đóng băng (đơn, Shop, hãng hoặc toàn cục) không được ghi.
 func saveOrderState(ctx contractapi.TransactionContextInterface, order *Order) error {
+    if err := checkOrderNotFrozen(ctx, order); err != nil {
+        return err
+    }
+    order.SchemaVersion = currentOrderSchemaVersion
     orderJSON, err := json.Marshal(order)
     if err != nil {
-        return fmt.Errorf("lỗi marshal JSON: %v", err)
+        return errLedger(ctx, "marshal", err)
+    }
+    previousStatus := order.indexedStatus
+    if err := updateStatusIndex(ctx, order); err != nil {
+        return err
+    }
+    if err := ctx.GetStub().PutState(order.OrderID, orderJSON); err != nil {
+        return errLedger(ctx, "PutState", err)
     }
-    return ctx.GetStub().PutState(order.OrderID, orderJSON)
+    if event := newOrderEvent(ctx, order, previousStatus); event != nil {
+        return setOrderEvent(ctx, event)
+    }
+    return nil
 }
 
 // orderExists kiểm tra xem orderID đã tồn tại hay chưa
 func (s *SmartContract) orderExists(ctx contractapi.TransactionContextInterface, orderID string) (bool, error) {
     assetJSON, err := ctx.GetStub().GetState(orderID)
     if err != nil {
-        return false, fmt.Errorf("lỗi đọc world state: %v", err)
+        return false, errLedger(ctx, "GetState", err)
     }
     return assetJSON != nil, nil
 }
@@ -80,13 +98,235 @@ func (s *SmartContract) orderExists(ctx contractapi.TransactionContextInterface,
 func getTimeNow(ctx contractapi.TransactionContextInterface) (time.Time, error) {
     txTime, err := ctx.GetStub().GetTxTimestamp()
     if err != nil {
-        return time.Time{}, fmt.Errorf("không thể lấy timestamp: %v", err)
+        return time.Time{}, errLedger(ctx, "GetTxTimestamp", err)
     }
     return time.Unix(txTime.GetSeconds(), int64(txTime.GetNanos())), nil
 }
 
-func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
-	return nil
+// getReturnCaseState: Lấy yêu cầu trả hàng từ sổ cái (khóa composite ReturnCase~orderID~returnID)
+func getReturnCaseState(ctx contractapi.TransactionContextInterface, orderID string, returnID string) (*ReturnCase, error) {
+    key, err := ctx.GetStub().CreateCompositeKey(returnCaseObjectType, []string{orderID, returnID})
+    if err != nil {
+        return nil, errLedger(ctx, "CreateCompositeKey", err)
+    }
+    caseJSON, err := ctx.GetStub().GetState(key)
+    if err != nil {
+        return nil, errLedger(ctx, "GetState", err)
+    }
+    if caseJSON == nil {
+        return nil, newError(ctx, ErrReturnNotFound, errorDetails{"orderID": orderID, "returnID": returnID})
+    }
+
+    var returnCase ReturnCase
+    if err := json.Unmarshal(caseJSON, &returnCase); err != nil {
+        return nil, errLedger(ctx, "unmarshal", err)
+    }
+    return &returnCase, nil
+}
+
+// saveReturnCase: Lưu yêu cầu trả hàng vào sổ cái
+func saveReturnCase(ctx contractapi.TransactionContextInterface, returnCase *ReturnCase) error {
+    key, err := ctx.GetStub().CreateCompositeKey(returnCaseObjectType, []string{returnCase.OrderID, returnCase.ReturnID})
+    if err != nil {
+        return errLedger(ctx, "CreateCompositeKey", err)
+    }
+    caseJSON, err := json.Marshal(returnCase)
+    if err != nil {
+        return errLedger(ctx, "marshal", err)
+    }
+    return ctx.GetStub().PutState(key, caseJSON)
+}
+
+// parseOrderLines: Đọc danh sách dòng hàng (JSON) khi tạo đơn và tính tổng tiền.
+// Chuỗi rỗng được chấp nhận cho các client cũ chưa gửi dòng hàng.
+func parseOrderLines(ctx contractapi.TransactionContextInterface, linesJSON string) ([]OrderLine, int64, error) {
+    if linesJSON == "" {
+        return nil, 0, nil
+    }
+
+    var lines []OrderLine
+    if err := json.Unmarshal([]byte(linesJSON), &lines); err != nil {
+        return nil, 0, errInvalidArgument(ctx, "linesJSON", err.Error())
+    }
+    total, err := checkOrderLines(ctx, lines)
+    if err != nil {
+        return nil, 0, err
+    }
+    return lines, total, nil
+}
+
+// checkOrderLines: Kiểm tra mã dòng / số lượng / đơn giá và tính tổng tiền (dùng chung với ImportOrders)
+func checkOrderLines(ctx contractapi.TransactionContextInterface, lines []OrderLine) (int64, error) {
+    var total int64
+    seen := make(map[string]bool)
+    for i := range lines {
+        line := &lines[i]
+        if line.LineID == "" {
+            return 0, errInvalidArgument(ctx, fmt.Sprintf("lines[%d].lineID", i), "required")
+        }
+        if seen[line.LineID] {
+            return 0, errInvalidArgument(ctx, "lines["+line.LineID+"].lineID", "duplicate")
+        }
+        seen[line.LineID] = true
+        if line.Quantity <= 0 {
+            return 0, errInvalidArgument(ctx, "lines["+line.LineID+"].quantity", "> 0")
+        }
+        if line.UnitPrice < 0 {
+            return 0, errInvalidArgument(ctx, "lines["+line.LineID+"].unitPrice", ">= 0")
+        }
+        line.ReturnedQuantity = 0
+        total += int64(line.Quantity) * line.UnitPrice
+    }
+    return total, nil
+}
+
+// buildReturnLines: Xác định các dòng hàng được trả và số tiền hoàn tương ứng.
+// returnLinesJSON rỗng nghĩa là trả toàn bộ phần hàng chưa nằm trong yêu cầu trả nào.
+func buildReturnLines(ctx contractapi.TransactionContextInterface, order *Order, returnLinesJSON string) ([]ReturnLine, int64, error) {
+    requested := make(map[string]int)
+    var lineIDs []string
+    if returnLinesJSON == "" {
+        for _, line := range order.Lines {
+            if remaining := line.Quantity - line.ReturnedQuantity; remaining > 0 {
+                requested[line.LineID] = remaining
+                lineIDs = append(lineIDs, line.LineID)
+            }
+        }
+    } else {
+        var items []ReturnLine
+        if err := json.Unmarshal([]byte(returnLinesJSON), &items); err != nil {
+            return nil, 0, errInvalidArgument(ctx, "returnLinesJSON", err.Error())
+        }
+        if len(items) == 0 {
+            return nil, 0, errInvalidArgument(ctx, "returnLinesJSON", "non-empty")
+        }
+        for _, item := range items {
+            if item.Quantity <= 0 {
+                return nil, 0, errInvalidArgument(ctx, "returnLines["+item.LineID+"].quantity", "> 0")
+            }
+            if _, dup := requested[item.LineID]; dup {
+                return nil, 0, errInvalidArgument(ctx, "returnLines["+item.LineID+"].lineID", "duplicate")
+            }
+            requested[item.LineID] = item.Quantity
+            lineIDs = append(lineIDs, item.LineID)
+        }
+    }
+
+    var returnLines []ReturnLine
+    var refund int64
+    for _, lineID := range lineIDs {
+        qty := requested[lineID]
+        idx := findOrderLine(order, lineID)
+        if idx < 0 {
+            return nil, 0, errInvalidArgument(ctx, "returnLines["+lineID+"].lineID", "unknown line of order "+order.OrderID)
+        }
+        line := order.Lines[idx]
+        if remaining := line.Quantity - line.ReturnedQuantity; qty > remaining {
+            return nil, 0, errInvalidArgument(ctx, "returnLines["+lineID+"].quantity", fmt.Sprintf("<= %d", remaining))
+        }
+        amount := int64(qty) * line.UnitPrice
+        returnLines = append(returnLines, ReturnLine{LineID: lineID, Quantity: qty, Amount: amount})
+        refund += amount
+    }
+    return returnLines, refund, nil
+}
+
+// findOrderLine trả về vị trí của dòng hàng trong đơn, -1 nếu không có
+func findOrderLine(order *Order, lineID string) int {
+    for i, line := range order.Lines {
+        if line.LineID == lineID {
+            return i
+        }
+    }
+    return -1
+}
+
+// allLinesReturned kiểm tra toàn bộ sản phẩm của đơn đã nằm trong các yêu cầu trả hàng
+func allLinesReturned(order *Order) bool {
+    for _, line := range order.Lines {
+        if line.ReturnedQuantity < line.Quantity {
+            return false
+        }
+    }
+    return true
+}
+
+// checkSellerOwnership: Kiểm tra người gọi là Seller sở hữu đơn (MSP + mã Shop gửi lên)
+func checkSellerOwnership(ctx contractapi.TransactionContextInterface, order *Order, verificationCompanyID string) (string, error) {
+    actorOrg, err := requireRole(ctx, RoleSeller)
+    if err != nil {
+        return "", err
+    }
+    if order.SellerID != actorOrg {
+        return "", errAccessDenied(ctx, actorOrg, order.SellerID)
+    }
+	if order.SellerCompanyID != "" && order.SellerCompanyID != verificationCompanyID {
+		return "", errCompanyMismatch(ctx, order.SellerCompanyID, verificationCompanyID)
+	}
+    return actorOrg, nil
+}
+
+// checkShipperOwnership: Kiểm tra người gọi là tổ chức vận chuyển của đơn (MSP + mã hãng gửi lên)
+func checkShipperOwnership(ctx contractapi.TransactionContextInterface, order *Order, verificationCompanyID string) (string, error) {
+    actorOrg, err := requireRole(ctx, RoleShipper)
+    if err != nil {
+        return "", err
+    }
+    if order.ShipperID != actorOrg {
+        return "", errAccessDenied(ctx, actorOrg, order.ShipperID)
+    }
+    if order.ShipperCompanyID != verificationCompanyID {
+        return "", errCompanyMismatch(ctx, order.ShipperCompanyID, verificationCompanyID)
+    }
+    return actorOrg, nil
+}
+
+// checkOrderAccess: Phân quyền xem đơn hàng (Visibility) theo MSP và companyCode của người gọi
+func checkOrderAccess(ctx contractapi.TransactionContextInterface, order *Order) error {
+    // Lấy thông tin người gọi
+    actorOrg, role, err := getActorRole(ctx)
+    if err != nil {
+        return err
+    }
+    callerCompany, _ := getCallerCompanyID(ctx)
+
+    switch role {
+    // 1. Admin Sàn: Xem hết
+    case RolePlatform:
+        return nil
+
+    // 2. Seller: Chỉ xem đơn của Shop mình
+    case RoleSeller:
+        if order.SellerID != actorOrg {
+            return errAccessDenied(ctx, actorOrg, order.SellerID)
+        }
+        if order.SellerCompanyID != "" && order.SellerCompanyID != callerCompany {
+            return errCompanyMismatch(ctx, order.SellerCompanyID, callerCompany)
+        }
+        return nil
+
+    // 3. Shipper: Chỉ xem đơn của Hãng mình
+    case RoleShipper:
+        if order.ShipperID != actorOrg {
+            return errAccessDenied(ctx, actorOrg, order.ShipperID)
+        }
+        if order.ShipperCompanyID != "" && order.ShipperCompanyID != callerCompany {
+            return errCompanyMismatch(ctx, order.ShipperCompanyID, callerCompany)
+        }
+        return nil
+    }
+
+    return errAccessDenied(ctx, actorOrg, "PLATFORM|SELLER|SHIPPER")
+}
+
+// newHistoryEntry tạo một dòng lịch sử cho giao dịch hiện tại
+func newHistoryEntry(ctx contractapi.TransactionContextInterface, action string, actorOrg string, txTime time.Time) HistoryEntry {
+    return HistoryEntry{
+        TxID:      ctx.GetStub().GetTxID(),
+        Timestamp: txTime,
+        Action:    action,
+        ActorOrg:  actorOrg,
+    }
 }
 
 // ===================================================================================
@@ -101,22 +341,34 @@ func (s *SmartContract) CreateOrder(ctx contractapi.TransactionContextInterface,
     shipperCompanyID string,
     sellerDataBlob string, 
     shipperDataBlob string,
-    sellerCompanyID string) error {
+    sellerCompanyID string,
+    linesJSON string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
 
-    // 1. Kiểm tra đầu vào
-    if shipperCompanyID == "" {
-        return fmt.Errorf("lỗi: đơn hàng phải có ShipperCompanyID (Đơn vị vận chuyển)")
+    // 1. Kiểm tra đầu vào (định dạng tham số đã kiểm tra trong validateTransactionArgs)
+    lines, totalAmount, err := parseOrderLines(ctx, linesJSON)
+    if err != nil {
+        return err
     }
 
     // 2. Lấy định danh người gọi
-    actorOrg, err := getActorOrg(ctx)
+    //callerCompanyID, _ := getCallerCompanyID(ctx)
+
+    // Chỉ Seller được tạo đơn
+    actorOrg, err := requireRole(ctx, RoleSeller)
     if err != nil { return err }
 
-    //callerCompanyID, _ := getCallerCompanyID(ctx)
+    // Shop có MSP riêng thì chỉ MSP đó được tạo đơn cho Shop
+    if err := checkCompanyOrg(ctx, sellerCompanyID, actorOrg); err != nil {
+        return err
+    }
 
-    // Chỉ Seller hoặc Sàn được tạo đơn
-    if actorOrg != "SellerOrgMSP"{
-        return fmt.Errorf("lỗi: tổ chức '%s' không có quyền tạo đơn", actorOrg)
+    // Tổ chức vận chuyển phụ trách đơn lấy theo hãng được chọn
+    shipperOrg, err := resolveShipperOrg(ctx, shipperCompanyID)
+    if err != nil {
+        return err
     }
 
     // 3. Kiểm tra trùng lặp
@@ -125,7 +377,7 @@ func (s *SmartContract) CreateOrder(ctx contractapi.TransactionContextInterface,
         return err
     }
     if exists {
-        return fmt.Errorf("đơn hàng %s đã tồn tại", orderID)
+        return newError(ctx, ErrOrderAlreadyExists, errorDetails{"orderID": orderID})
     }
 
     // 3. Lấy thời gian
@@ -135,17 +387,18 @@ func (s *SmartContract) CreateOrder(ctx contractapi.TransactionContextInterface,
     }
 
     // 4. Khởi tạo trạng thái ban đầu
-    codStatus := ""
-    if paymentMethod == "COD" {
-        codStatus = "NOT_COLLECTED"
+    method := PaymentMethod(paymentMethod) // Đã được kiểm tra trong validateTransactionArgs
+    codStatus := CodNone
+    if method == PaymentCOD {
+        codStatus = CodNotCollected
     }
 
     // 5. Tạo đối tượng Order
     order := Order{ 
         DocType:       "Order",
         OrderID:       orderID,
-        Status:        "CREATED",
-        PaymentMethod: paymentMethod,
+        Status:        StatusCreated,
+        PaymentMethod: method,
         CodStatus:     codStatus,
         
         // Gán thông tin chủ sở hữu
@@ -153,13 +406,15 @@ func (s *SmartContract) CreateOrder(ctx contractapi.TransactionContextInterface,
         SellerCompanyID: sellerCompanyID, 
         
         // Gán thông tin vận chuyển
-        ShipperID:        "ShipperOrgMSP", 
+        ShipperID:        shipperOrg,
         ShipperCompanyID: shipperCompanyID,
 
         CreatedAt:            txTime,
         UpdatedAt:            txTime,
             SellerSensitiveData:  sellerDataBlob,
             ShipperSensitiveData: shipperDataBlob,
+        Lines:       lines,
+        TotalAmount: totalAmount,
         History: []HistoryEntry{
             {
                 TxID:      ctx.GetStub().GetTxID(),
@@ -170,7 +425,16 @@ func (s *SmartContract) CreateOrder(ctx contractapi.TransactionContextInterface,
         },
     }
 
-    // 6. Lưu vào sổ cái
+    // 6. Chính sách chứng thực của đơn: Seller sở hữu + Sàn
+    endorsers, err := initialOrderEndorsers(ctx, actorOrg)
+    if err != nil {
+        return err
+    }
+    if err := setOrderEndorsers(ctx, orderID, endorsers...); err != nil {
+        return err
+    }
+
+    // 7. Lưu vào sổ cái
     return saveOrderState(ctx, &order)
 }
 
@@ -178,14 +442,15 @@ func (s *SmartContract) CreateOrder(ctx contractapi.TransactionContextInterface,
 // [HÀM 2] ConfirmPayment: Sàn xác nhận thanh toán
 // -----------------------------------------------------------------------------------
 func (s *SmartContract) ConfirmPayment(ctx contractapi.TransactionContextInterface, orderID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     // 1. Kiểm tra ACL
-    actorOrg, err := getActorOrg(ctx)
+    actorOrg, err := requireRole(ctx, RolePlatform)
     if err != nil {
         return err
     }
-    if actorOrg != "ECommercePlatformOrgMSP" {
-        return fmt.Errorf("chỉ Sàn TMĐT mới được xác nhận thanh toán")
-    }
 
     // 2. Lấy đơn hàng
     order, err := getOrderState(ctx, orderID)
@@ -194,11 +459,11 @@ func (s *SmartContract) ConfirmPayment(ctx contractapi.TransactionContextInterfa
     }
 
     // 3. Kiểm tra Pre-condition (Logic)
-    if order.PaymentMethod != "PREPAID" {
-        return fmt.Errorf("lỗi: chỉ đơn hàng 'PREPAID' mới cần 'ConfirmPayment'")
+    if order.PaymentMethod != PaymentPrepaid {
+        return errPaymentMethod(ctx, order.PaymentMethod, PaymentPrepaid)
     }
-    if order.Status != "CREATED" {
-        return fmt.Errorf("lỗi: chỉ đơn hàng 'CREATED' mới có thể 'ConfirmPayment'")
+    if order.Status != StatusCreated {
+        return errInvalidState(ctx, order.Status, StatusCreated)
     }
 
     // 4. Lấy thời gian
@@ -208,7 +473,7 @@ func (s *SmartContract) ConfirmPayment(ctx contractapi.TransactionContextInterfa
     }
 
     // 5. Cập nhật trạng thái
-    order.Status = "PAID"
+    order.Status = StatusPaid
     order.UpdatedAt = txTime
     order.History = append(order.History, HistoryEntry{
         TxID:      ctx.GetStub().GetTxID(),
@@ -226,14 +491,15 @@ func (s *SmartContract) ConfirmPayment(ctx contractapi.TransactionContextInterfa
 // Chính sách (EP): OR('ECommercePlatformOrg.member')
 // -----------------------------------------------------------------------------------
 func (s *SmartContract) CancelOrder(ctx contractapi.TransactionContextInterface, orderID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     // 1. Kiểm tra ACL
-    actorOrg, err := getActorOrg(ctx)
+    actorOrg, err := requireRole(ctx, RolePlatform)
     if err != nil {
         return err
     }
-    if actorOrg != "ECommercePlatformOrgMSP" {
-        return fmt.Errorf("lỗi: chỉ tổ chức 'ECommercePlatformOrgMSP' mới được hủy đơn")
-    }
 
     // 2. Lấy đơn hàng
     order, err := getOrderState(ctx, orderID)
@@ -242,8 +508,8 @@ func (s *SmartContract) CancelOrder(ctx contractapi.TransactionContextInterface,
     }
 
     // 3. Kiểm tra Pre-condition (Logic)
-    if order.Status != "CREATED" && order.Status != "PAID" {
-        return fmt.Errorf("lỗi: chỉ đơn hàng 'CREATED' hoặc 'PAID' mới có thể hủy. Trạng thái hiện tại: %s", order.Status)
+    if order.Status != StatusCreated && order.Status != StatusPaid {
+        return errInvalidState(ctx, order.Status, anyOf(StatusCreated, StatusPaid))
     }
 
     // 4. Lấy thời gian
@@ -253,7 +519,7 @@ func (s *SmartContract) CancelOrder(ctx contractapi.TransactionContextInterface,
     }
 
     // 5. Cập nhật trạng thái
-    order.Status = "CANCELLED"
+    order.Status = StatusCancelled
     order.UpdatedAt = txTime
     order.History = append(order.History, HistoryEntry{
         TxID:      ctx.GetStub().GetTxID(),
@@ -268,32 +534,27 @@ func (s *SmartContract) CancelOrder(ctx contractapi.TransactionContextInterface,
 
 // [HÀM 4 - UPDATED] ShipOrder: Nhận verificationCompanyID để check quyền
 func (s *SmartContract) ShipOrder(ctx contractapi.TransactionContextInterface, orderID string, verificationCompanyID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     order, err := getOrderState(ctx, orderID)
 	if err != nil {
 		return err
 	}
 
-    actorOrg, _ := getActorOrg(ctx)
-    
-    // 1. KIỂM TRA QUYỀN (CHỈ CHO 1 ORG LÀ SHIPPER GỌI)
-    if actorOrg != "ShipperOrgMSP" {
-        return fmt.Errorf("lỗi: chỉ tổ chức vận chuyển 'ShipperOrgMSP' mới được phép xác nhận lấy hàng")
-    }
-
-	// 2. Check quyền sở hữu bằng tham số gửi lên
-	if verificationCompanyID == "" {
-		return fmt.Errorf("lỗi: thiếu mã xác thực công ty vận chuyển")
-    }
-	if order.ShipperCompanyID != verificationCompanyID {
-		return fmt.Errorf("LỖI QUYỀN: Đơn hàng thuộc về '%s', nhưng mã xác thực gửi lên là '%s'", order.ShipperCompanyID, verificationCompanyID)
+    // 1. KIỂM TRA QUYỀN (CHỈ CHO ĐÚNG ORG SHIPPER CỦA ĐƠN GỌI, KÈM MÃ HÃNG)
+    actorOrg, err := checkShipperOwnership(ctx, order, verificationCompanyID)
+    if err != nil {
+        return err
     }
 
 	// 3. Logic nghiệp vụ
-    if order.PaymentMethod == "PREPAID" && order.Status != "PAID" {
-        return fmt.Errorf("lỗi: đơn PREPAID phải thanh toán (PAID) mới được giao. Trạng thái hiện tại: %s", order.Status)
+    if order.PaymentMethod == PaymentPrepaid && order.Status != StatusPaid {
+        return errInvalidState(ctx, order.Status, StatusPaid)
     }
-    if order.PaymentMethod == "COD" && order.Status != "CREATED" {
-        return fmt.Errorf("lỗi: đơn COD phải ở trạng thái CREATED mới được giao. Trạng thái hiện tại: %s", order.Status)
+    if order.PaymentMethod == PaymentCOD && order.Status != StatusCreated {
+        return errInvalidState(ctx, order.Status, StatusCreated)
     }
 
     // 4. Lấy thời gian
@@ -303,7 +564,7 @@ func (s *SmartContract) ShipOrder(ctx contractapi.TransactionContextInterface, o
     }
 
     // 5. Cập nhật trạng thái
-    order.Status = "SHIPPED"
+    order.Status = StatusShipped
     order.UpdatedAt = txTime
     order.History = append(order.History, HistoryEntry{
         TxID:      ctx.GetStub().GetTxID(),
@@ -312,31 +573,36 @@ func (s *SmartContract) ShipOrder(ctx contractapi.TransactionContextInterface, o
         ActorOrg:  actorOrg,
     })
 
-    // 6. Lưu
+    // 6. Từ khi nhận hàng, mọi thay đổi của đơn phải có peer của hãng vận chuyển chứng thực
+    if err := addOrderEndorsers(ctx, orderID, order.ShipperID); err != nil {
+        return err
+    }
+
+    // 7. Lưu
     return saveOrderState(ctx, order)
 }
 
 // [HÀM 5 - UPDATED] ConfirmDelivery
 func (s *SmartContract) ConfirmDelivery(ctx contractapi.TransactionContextInterface, orderID string, verificationCompanyID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     order, err := getOrderState(ctx, orderID)
 	if err != nil {
 		return err
 	}
 
-    actorOrg, _ := getActorOrg(ctx)
-
-    if actorOrg != "ShipperOrgMSP" {
-        return fmt.Errorf("chỉ ShipperOrg mới được xác nhận giao hàng")
-    }
-	if order.ShipperCompanyID != verificationCompanyID {
-		return fmt.Errorf("sai đơn vị vận chuyển: đơn là '%s', mã gửi lên là '%s'", order.ShipperCompanyID, verificationCompanyID)
+    actorOrg, err := checkShipperOwnership(ctx, order, verificationCompanyID)
+    if err != nil {
+        return err
     }
 
-    if order.PaymentMethod != "PREPAID" {
-        return fmt.Errorf("hàm này chỉ cho đơn PREPAID")
+    if order.PaymentMethod != PaymentPrepaid {
+        return errPaymentMethod(ctx, order.PaymentMethod, PaymentPrepaid)
     }
-    if order.Status != "SHIPPED" {
-        return fmt.Errorf("đơn phải đang SHIPPED")
+    if order.Status != StatusShipped {
+        return errInvalidState(ctx, order.Status, StatusShipped)
     }
 
     // 4. Lấy thời gian
@@ -346,7 +612,7 @@ func (s *SmartContract) ConfirmDelivery(ctx contractapi.TransactionContextInterf
     }
 
     // 5. Cập nhật trạng thái
-    order.Status = "DELIVERED"
+    order.Status = StatusDelivered
     order.DeliveryTimestamp = txTime
     order.UpdatedAt = txTime
     order.History = append(order.History, HistoryEntry{
@@ -356,33 +622,38 @@ func (s *SmartContract) ConfirmDelivery(ctx contractapi.TransactionContextInterf
         ActorOrg:  actorOrg,
     })
 
-    // 6. Lưu
+    // 6. Thêm peer của hãng vận chuyển vào chính sách chứng thực (no-op nếu đã có)
+    if err := addOrderEndorsers(ctx, orderID, order.ShipperID); err != nil {
+        return err
+    }
+
+    // 7. Lưu
     return saveOrderState(ctx, order)
 }
 
 // [HÀM 6 - UPDATED] ConfirmCODDelivery
 func (s *SmartContract) ConfirmCODDelivery(ctx contractapi.TransactionContextInterface, orderID string, verificationCompanyID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     order, err := getOrderState(ctx, orderID)
 	if err != nil {
 		return err
 	}
 
-    actorOrg, _ := getActorOrg(ctx)
-    
     // Check quyền
-    if actorOrg != "ShipperOrgMSP" {
-        return fmt.Errorf("chỉ ShipperOrg mới được gọi")
-    }
-	if order.ShipperCompanyID != verificationCompanyID {
-		return fmt.Errorf("sai đơn vị vận chuyển: đơn là '%s', mã gửi lên là '%s'", order.ShipperCompanyID, verificationCompanyID)
+    actorOrg, err := checkShipperOwnership(ctx, order, verificationCompanyID)
+    if err != nil {
+        return err
     }
 
     // Check Logic
-    if order.PaymentMethod != "COD" {
-        return fmt.Errorf("hàm này chỉ cho đơn COD")
+    if order.PaymentMethod != PaymentCOD {
+        return errPaymentMethod(ctx, order.PaymentMethod, PaymentCOD)
     }
-    if order.Status != "SHIPPED" {
-        return fmt.Errorf("đơn phải đang SHIPPED")
+    if order.Status != StatusShipped {
+        return errInvalidState(ctx, order.Status, StatusShipped)
     }
 
     // 4. Lấy thời gian
@@ -392,8 +663,8 @@ func (s *SmartContract) ConfirmCODDelivery(ctx contractapi.TransactionContextInt
     }
 
     // 5. Cập nhật trạng thái
-    order.Status = "DELIVERED"
-    order.CodStatus = "PENDING_REMITTANCE"
+    order.Status = StatusDelivered
+    order.CodStatus = CodPendingRemittance
     order.DeliveryTimestamp = txTime
     order.UpdatedAt = txTime
     order.History = append(order.History, HistoryEntry{
@@ -403,20 +674,26 @@ func (s *SmartContract) ConfirmCODDelivery(ctx contractapi.TransactionContextInt
         ActorOrg:  actorOrg,
     })
 
-    // 6. Lưu
+    // 6. Thêm peer của hãng vận chuyển vào chính sách chứng thực (no-op nếu đã có)
+    if err := addOrderEndorsers(ctx, orderID, order.ShipperID); err != nil {
+        return err
+    }
+
+    // 7. Lưu
     return saveOrderState(ctx, order)
 }
 
 // [HÀM 7] RemitCOD
 func (s *SmartContract) RemitCOD(ctx contractapi.TransactionContextInterface, orderID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     // 1. Kiểm tra ACL
-    actorOrg, err := getActorOrg(ctx)
+    actorOrg, err := requireRole(ctx, RolePlatform)
     if err != nil {
         return err
     }
-    if actorOrg != "ECommercePlatformOrgMSP" {
-		return fmt.Errorf("chỉ Sàn TMĐT mới xác nhận đã nhận tiền COD")
-    }
 
     // 2. Lấy đơn hàng
     order, err := getOrderState(ctx, orderID)
@@ -425,8 +702,8 @@ func (s *SmartContract) RemitCOD(ctx contractapi.TransactionContextInterface, or
     }
 
     // 3. Kiểm tra Pre-condition (Logic)
-    if order.CodStatus != "PENDING_REMITTANCE" {
-        return fmt.Errorf("CodStatus phải là PENDING_REMITTANCE")
+    if order.CodStatus != CodPendingRemittance {
+        return errInvalidState(ctx, order.CodStatus, CodPendingRemittance)
     }
 
     // 4. Lấy thời gian
@@ -436,7 +713,7 @@ func (s *SmartContract) RemitCOD(ctx contractapi.TransactionContextInterface, or
     }
 
     // 5. Cập nhật trạng thái
-    order.CodStatus = "REMITTED"
+    order.CodStatus = CodRemitted
     order.UpdatedAt = txTime
     order.History = append(order.History, HistoryEntry{
         TxID:      ctx.GetStub().GetTxID(),
@@ -452,80 +729,141 @@ func (s *SmartContract) RemitCOD(ctx contractapi.TransactionContextInterface, or
 // -----------------------------------------------------------------------------------
 // [HÀM 8] PayoutToSeller: Thanh toán cho Seller
 // Logic: Giữ nguyên kiểm tra PREPAID/COD và 5 phút
+// Yêu cầu trả hàng đang mở không chặn thanh toán: Seller nhận ngay phần hàng không bị trả;
+// dòng hàng bị Seller từ chối nhận lại (InspectReturn) được thanh toán bổ sung bằng một lần gọi
+// PayoutToSeller nữa trên đơn đã SETTLED.
+// Số tiền lớn hơn ngưỡng duyệt chỉ tạo đề xuất, thanh toán khi được duyệt (xem ApproveAction);
+// kết quả cho biết đã thanh toán (EXECUTED) hay đang chờ duyệt (PENDING + approvalID)
 // -----------------------------------------------------------------------------------
-func (s *SmartContract) PayoutToSeller(ctx contractapi.TransactionContextInterface, orderID string) error {
+func (s *SmartContract) PayoutToSeller(ctx contractapi.TransactionContextInterface, orderID string) (*SettlementResult, error) {
+    if prior, err := checkClientRequest(ctx); err != nil {
+        return nil, err
+    } else if prior != nil {
+        result := &SettlementResult{}
+        return result, prior.decodeResult(ctx, result)
+    }
+
     // 1. Kiểm tra ACL
-    actorOrg, err := getActorOrg(ctx)
+    actorOrg, err := requireRole(ctx, RolePlatform)
     if err != nil {
-        return err
-    }
-    if actorOrg != "ECommercePlatformOrgMSP" {
-        return fmt.Errorf("lỗi: chỉ 'ECommercePlatformOrgMSP'mới được gọi hàm này")
+        return nil, err
     }
 
     // 2. Lấy đơn hàng
     order, err := getOrderState(ctx, orderID)
     if err != nil {
-        return err
+        return nil, err
     }
 
     // 3. Lấy thời gian hiện tại
     txTime, err := getTimeNow(ctx)
     if err != nil {
-        return err
+        return nil, err
     }
 
-    // 4. Kiểm tra Pre-condition (Logic nghiệp vụ - Trạng thái) - GIỮ NGUYÊN
-    if order.PaymentMethod == "PREPAID" && order.Status != "DELIVERED" {
-        return fmt.Errorf("chỉ có thể thanh toán cho đơn PREPAID đã 'DELIVERED'")
+    // 4. Kiểm tra trạng thái và thời gian chờ sau giao hàng
+    if err := checkPayoutAllowed(ctx, order, txTime); err != nil {
+        return nil, err
     }
-    if order.PaymentMethod == "COD" && (order.Status != "DELIVERED" || order.CodStatus != "REMITTED") {
-        return fmt.Errorf("chỉ có thể thanh toán cho đơn COD đã 'DELIVERED' và 'REMITTED'")
+
+    // 5. Số tiền lớn: tạo đề xuất chờ người thứ hai duyệt (maker-checker), chưa thanh toán
+    config, err := getPolicyConfig(ctx)
+    if err != nil {
+        return nil, err
     }
-    if order.Status == "SETTLED" {
-        return fmt.Errorf("đơn hàng đã được thanh toán (SETTLED) từ trước")
+    amount := unpaidPayout(order, &config.Fees)
+    approval, err := requireApproval(ctx, approvalPayout, order, "", amount, txTime)
+    if err != nil {
+        return nil, err
     }
 
-    // 5. KIỂM TRA LOGIC THỜI GIAN (DEMO: 5 PHÚT)
+    // 6. Cập nhật trạng thái và lưu (trừ khi chờ duyệt)
+    if approval == nil {
+        if err := settlePayout(ctx, order, actorOrg, txTime); err != nil {
+            return nil, err
+        }
+    }
+    result := newSettlementResult(approvalPayout, orderID, "", amount, approval)
+    return result, recordClientResult(ctx, result)
+}
+
+// payoutAmount: Phần hàng đang/đã trả lại (trả một phần) không được tính vào số tiền trả cho Seller,
+// phí Sàn theo biểu phí hiện hành được khấu trừ
+func payoutAmount(order *Order, fees *FeeSchedule) int64 {
+    net := order.TotalAmount - order.RefundableAmount
+    return net - fees.platformFee(order, net)
+}
+
+// unpaidPayout: Phần còn phải trả cho Seller (lần đầu là toàn bộ payoutAmount; sau khi SETTLED là
+// tiền của dòng hàng trả bị từ chối). Phí tính lại trên tổng nên phí cố định không bị thu hai lần.
+func unpaidPayout(order *Order, fees *FeeSchedule) int64 {
+    return payoutAmount(order, fees) - order.PayoutAmount
+}
+
+// checkPayoutAllowed: Điều kiện thanh toán cho Seller (dùng lại khi đề xuất được duyệt)
+func checkPayoutAllowed(ctx contractapi.TransactionContextInterface, order *Order, txTime time.Time) error {
+    // Kiểm tra Pre-condition (Logic nghiệp vụ - Trạng thái) - GIỮ NGUYÊN
+    // SETTLED chỉ hợp lệ khi còn tiền chưa trả (dòng hàng trả bị từ chối sau lần thanh toán đầu)
+    payable := order.Status == StatusDelivered || order.Status == StatusSettled
+    if order.PaymentMethod == PaymentPrepaid && !payable {
+        return errInvalidState(ctx, order.Status, StatusDelivered)
+    }
+    if order.PaymentMethod == PaymentCOD && (!payable || order.CodStatus != CodRemitted) {
+        return errInvalidState(ctx, string(order.Status)+"/"+string(order.CodStatus), string(StatusDelivered)+"/"+string(CodRemitted))
+    }
+
+    // KIỂM TRA LOGIC THỜI GIAN (DEMO: 5 PHÚT)
     if order.DeliveryTimestamp.IsZero() {
-        return fmt.Errorf("lỗi: không tìm thấy mốc thời gian giao hàng (deliveryTimestamp)")
+        return newError(ctx, ErrInvalidState, errorDetails{"currentStatus": order.Status, "requiredStatus": StatusDelivered, "missing": "deliveryTimestamp"})
     }
 
-    // Thay vì 7 ngày (time.Hour * 24 * 7), ta dùng 5 phút (time.Minute * 5)
-    // Nhưng thông báo lỗi vẫn giữ nguyên là "7 ngày"
-    payoutUnlockTime := order.DeliveryTimestamp.Add(time.Minute * 5) 
+    // Thời gian giữ tiền lấy từ PolicyConfig (mặc định DEMO: 5 phút thay vì 7 ngày)
+    config, err := getPolicyConfig(ctx)
+    if err != nil {
+        return err
+    }
+    if order.Status == StatusSettled && unpaidPayout(order, &config.Fees) <= 0 {
+        return errInvalidState(ctx, order.Status, StatusDelivered)
+    }
+    payoutUnlockTime := order.DeliveryTimestamp.Add(time.Duration(config.PayoutHoldSeconds) * time.Second)
 
     if txTime.Before(payoutUnlockTime) {
-        return fmt.Errorf("chưa đủ 7 ngày kể từ khi giao hàng. Không thể thanh toán. Mở khóa lúc: %v", payoutUnlockTime)
+        return newError(ctx, ErrWindowNotElapsed, errorDetails{"unlockTime": payoutUnlockTime})
     }
+    return nil
+}
 
-    // 6. Cập nhật trạng thái
-    order.Status = "SETTLED"
+// settlePayout: Chuyển đơn sang SETTLED, cộng dồn số tiền đã trả cho Seller và lưu lại sổ cái
+func settlePayout(ctx contractapi.TransactionContextInterface, order *Order, actorOrg string, txTime time.Time) error {
+    config, err := getPolicyConfig(ctx)
+    if err != nil {
+        return err
+    }
+    order.Status = StatusSettled
+    order.PayoutAmount = payoutAmount(order, &config.Fees)
+    order.PlatformFee = order.TotalAmount - order.RefundableAmount - order.PayoutAmount
     order.UpdatedAt = txTime
-    order.History = append(order.History, HistoryEntry{
-        TxID:      ctx.GetStub().GetTxID(),
-        Timestamp: txTime,
-        Action:    "PayoutToSeller",
-        ActorOrg:  actorOrg,
-    })
-
-    // 7. Lưu lại sổ cái
+    order.History = append(order.History, newHistoryEntry(ctx, "PayoutToSeller", actorOrg, txTime))
     return saveOrderState(ctx, order)
 }
 
 // -----------------------------------------------------------------------------------
 // [HÀM 9] RequestReturn: Sàn yêu cầu trả hàng (trong 7 ngày -> DEMO: 5 phút)
 // Chính sách (EP): OR('ECommercePlatformOrg.member')
+// Tạo một ReturnCase cho danh sách dòng hàng/số lượng được trả (JSON: [{"lineID","quantity"}]).
+// returnLinesJSON rỗng = trả toàn bộ phần hàng còn lại. Trả một phần thì đơn vẫn giữ
+// trạng thái DELIVERED để phần còn lại được thanh toán cho Seller.
 // -----------------------------------------------------------------------------------
-func (s *SmartContract) RequestReturn(ctx contractapi.TransactionContextInterface, orderID string) error {
+func (s *SmartContract) RequestReturn(ctx contractapi.TransactionContextInterface, orderID string, returnID string, returnLinesJSON string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     // 1. Kiểm tra ACL
-    actorOrg, err := getActorOrg(ctx)
+    actorOrg, err := requireRole(ctx, RolePlatform)
     if err != nil {
         return err
     }
-    if actorOrg != "ECommercePlatformOrgMSP" {
-        return fmt.Errorf("lỗi: chỉ tổ chức 'ECommercePlatformOrgMSP' mới được yêu cầu trả hàng")
-    }
 
     // 2. Lấy đơn hàng
     order, err := getOrderState(ctx, orderID)
@@ -540,54 +878,103 @@ func (s *SmartContract) RequestReturn(ctx contractapi.TransactionContextInterfac
     }
 
     // 4. Kiểm tra Pre-condition (Logic nghiệp vụ - Trạng thái)
-    if order.Status != "DELIVERED" {
-        return fmt.Errorf("lỗi: chỉ có thể trả hàng khi đơn ở trạng thái 'DELIVERED'. Trạng thái hiện tại: %s", order.Status)
+    if order.Status != StatusDelivered {
+        return errInvalidState(ctx, order.Status, StatusDelivered)
+    }
+    // Chỉ "chưa có" mới được tạo mới; lỗi sổ cái / dữ liệu hỏng phải trả về nguyên vẹn
+    if _, err := getReturnCaseState(ctx, orderID, returnID); err == nil {
+        return newError(ctx, ErrReturnAlreadyExists, errorDetails{"orderID": orderID, "returnID": returnID})
+    } else if !hasErrorCode(err, ErrReturnNotFound) {
+        return err
     }
 
     // 5. KIỂM TRA LOGIC THỜI GIAN (DEMO: 5 PHÚT)
     if order.DeliveryTimestamp.IsZero() {
-        return fmt.Errorf("lỗi: không tìm thấy mốc thời gian giao hàng (deliveryTimestamp)")
+        return newError(ctx, ErrInvalidState, errorDetails{"currentStatus": order.Status, "requiredStatus": StatusDelivered, "missing": "deliveryTimestamp"})
     }
 
-    // Thời hạn trả hàng cũng giảm xuống 5 phút để test case "hết hạn trả hàng"
-    returnDeadline := order.DeliveryTimestamp.Add(time.Minute * 5)
+    // Thời hạn trả hàng lấy từ PolicyConfig (mặc định DEMO: 5 phút để test case "hết hạn trả hàng")
+    config, err := getPolicyConfig(ctx)
+    if err != nil {
+        return err
+    }
+    returnDeadline := order.DeliveryTimestamp.Add(time.Duration(config.ReturnWindowSeconds) * time.Second)
 
     if txTime.After(returnDeadline) {
-        return fmt.Errorf("đã quá 7 ngày kể từ khi giao hàng. Không thể trả hàng. Hạn chót: %v", returnDeadline)
+        return newError(ctx, ErrWindowExpired, errorDetails{"deadline": returnDeadline})
     }
 
-    // 6. Cập nhật trạng thái
-    order.Status = "RETURN_REQUESTED"
+    // 6. Xác định dòng hàng trả và số tiền hoàn
+    // Đơn cũ không có dòng hàng chỉ hỗ trợ trả toàn bộ đơn
+    var returnLines []ReturnLine
+    refundAmount := order.TotalAmount
+    if len(order.Lines) > 0 {
+        returnLines, refundAmount, err = buildReturnLines(ctx, order, returnLinesJSON)
+        if err != nil {
+            return err
+        }
+        if len(returnLines) == 0 {
+            return newError(ctx, ErrNothingToReturn, errorDetails{"orderID": orderID})
+        }
+        for _, rl := range returnLines {
+            order.Lines[findOrderLine(order, rl.LineID)].ReturnedQuantity += rl.Quantity
+        }
+    } else if returnLinesJSON != "" {
+        return errInvalidArgument(ctx, "returnLinesJSON", "empty (order has no lines)")
+    }
+    fullReturn := allLinesReturned(order)
+
+    returnCase := ReturnCase{
+        DocType:      returnCaseObjectType,
+        ReturnID:     returnID,
+        OrderID:      orderID,
+        Status:       StatusReturnRequested,
+        FullReturn:   fullReturn,
+        Lines:        returnLines,
+        RefundAmount: refundAmount,
+        CreatedAt:    txTime,
+        UpdatedAt:    txTime,
+        History:      []HistoryEntry{newHistoryEntry(ctx, "RequestReturn", actorOrg, txTime)},
+    }
+
+    // 7. Cập nhật đơn hàng
+    if fullReturn {
+        order.Status = StatusReturnRequested
+    }
+    order.RefundableAmount += refundAmount
+    order.ReturnIDs = append(order.ReturnIDs, returnID)
     order.UpdatedAt = txTime
-    order.History = append(order.History, HistoryEntry{
-        TxID:      ctx.GetStub().GetTxID(),
-        Timestamp: txTime,
-        Action:    "RequestReturn",
-        ActorOrg:  actorOrg,
-    })
+    order.History = append(order.History, newHistoryEntry(ctx, "RequestReturn", actorOrg, txTime))
 
-    // 7. Lưu lại sổ cái
+    // 8. Lưu lại sổ cái
+    if err := saveReturnCase(ctx, &returnCase); err != nil {
+        return err
+    }
     return saveOrderState(ctx, order)
 }
 
-// [HÀM 10 - UPDATED] ShipReturn
-func (s *SmartContract) ShipReturn(ctx contractapi.TransactionContextInterface, orderID string, verificationCompanyID string) error {
+// [HÀM 10 - UPDATED] ShipReturn: Shipper lấy hàng trả của một ReturnCase
+func (s *SmartContract) ShipReturn(ctx contractapi.TransactionContextInterface, orderID string, returnID string, verificationCompanyID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     order, err := getOrderState(ctx, orderID)
 	if err != nil {
 		return err
 	}
-
-    actorOrg, _ := getActorOrg(ctx)
-
-    if actorOrg != "ShipperOrgMSP" {
-        return fmt.Errorf("chỉ Shipper mới được lấy hàng trả")
+    returnCase, err := getReturnCaseState(ctx, orderID, returnID)
+    if err != nil {
+        return err
     }
-	if order.ShipperCompanyID != verificationCompanyID {
-		return fmt.Errorf("sai đơn vị vận chuyển: đơn của '%s', bạn là '%s'", order.ShipperCompanyID, verificationCompanyID)
+
+    actorOrg, err := checkShipperOwnership(ctx, order, verificationCompanyID)
+    if err != nil {
+        return err
     }
 
-    if order.Status != "RETURN_REQUESTED" {
-        return fmt.Errorf("trạng thái phải là RETURN_REQUESTED")
+    if returnCase.Status != StatusReturnRequested {
+        return errInvalidState(ctx, returnCase.Status, StatusReturnRequested)
     }
 
     // 4. Lấy thời gian
@@ -596,45 +983,50 @@ func (s *SmartContract) ShipReturn(ctx contractapi.TransactionContextInterface,
         return err
     }
 
-    // 5. Cập nhật trạng thái
-    order.Status = "RETURN_IN_TRANSIT"
+    // 5. Cập nhật trạng thái (trả toàn bộ thì đơn đi cùng trạng thái với ReturnCase)
+    returnCase.Status = StatusReturnInTransit
+    returnCase.UpdatedAt = txTime
+    returnCase.History = append(returnCase.History, newHistoryEntry(ctx, "ShipReturn", actorOrg, txTime))
+    if returnCase.FullReturn {
+        order.Status = StatusReturnInTransit
+    }
     order.UpdatedAt = txTime
-    order.History = append(order.History, HistoryEntry{
-        TxID:      ctx.GetStub().GetTxID(),
-        Timestamp: txTime,
-        Action:    "ShipReturn",
-        ActorOrg:  actorOrg,
-    })
+    order.History = append(order.History, newHistoryEntry(ctx, "ShipReturn", actorOrg, txTime))
 
-    // 6. Lưu
+    // 6. Thêm peer của hãng vận chuyển vào chính sách chứng thực (no-op nếu đã có)
+    if err := addOrderEndorsers(ctx, orderID, order.ShipperID); err != nil {
+        return err
+    }
+
+    // 7. Lưu
+    if err := saveReturnCase(ctx, returnCase); err != nil {
+        return err
+    }
     return saveOrderState(ctx, order)
 }
 
-// [HÀM 11 - UPDATED] ConfirmReturnReceived
-func (s *SmartContract) ConfirmReturnReceived(ctx contractapi.TransactionContextInterface, orderID string, verificationCompanyID string) error {
+// [HÀM 11 - UPDATED] ConfirmReturnReceived: Seller xác nhận đã nhận hàng trả của một ReturnCase
+func (s *SmartContract) ConfirmReturnReceived(ctx contractapi.TransactionContextInterface, orderID string, returnID string, verificationCompanyID string) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
     order, err := getOrderState(ctx, orderID)
 	if err != nil {
 		return err
 	}
-
-    actorOrg, _ := getActorOrg(ctx)
-
-    if actorOrg != "SellerOrgMSP" {
-        return fmt.Errorf("chỉ Seller mới được xác nhận nhận hàng")
+    returnCase, err := getReturnCaseState(ctx, orderID, returnID)
+    if err != nil {
+        return err
     }
-    if order.SellerID != actorOrg {
-        return fmt.Errorf("không phải đơn của tổ chức bạn")
+
+    actorOrg, err := checkSellerOwnership(ctx, order, verificationCompanyID)
+    if err != nil {
+        return err
     }
-	// Check Seller Company ID gửi lên
-	if verificationCompanyID == "" {
-		return fmt.Errorf("thiếu mã xác thực Shop")
-	}
-	if order.SellerCompanyID != "" && order.SellerCompanyID != verificationCompanyID {
-		return fmt.Errorf("LỖI QUYỀN: Đơn hàng thuộc Shop '%s', nhưng mã xác thực gửi lên là '%s'", order.SellerCompanyID, verificationCompanyID)
-	}
 
-    if order.Status != "RETURN_IN_TRANSIT" {
-        return fmt.Errorf("trạng thái phải là RETURN_IN_TRANSIT")
+    if returnCase.Status != StatusReturnInTransit {
+        return errInvalidState(ctx, returnCase.Status, StatusReturnInTransit)
     }
 
     // 4. Lấy thời gian
@@ -644,16 +1036,164 @@ func (s *SmartContract) ConfirmReturnReceived(ctx contractapi.TransactionContext
     }
 
     // 5. Cập nhật trạng thái
-    order.Status = "RETURNED"
+    returnCase.Status = StatusReturnReceived
+    returnCase.UpdatedAt = txTime
+    returnCase.History = append(returnCase.History, newHistoryEntry(ctx, "ConfirmReturnReceived", actorOrg, txTime))
+    if returnCase.FullReturn {
+        order.Status = StatusReturned
+    }
     order.UpdatedAt = txTime
-    order.History = append(order.History, HistoryEntry{
-        TxID:      ctx.GetStub().GetTxID(),
-        Timestamp: txTime,
-        Action:    "ConfirmReturnReceived",
-        ActorOrg:  actorOrg,
-    })
+    order.History = append(order.History, newHistoryEntry(ctx, "ConfirmReturnReceived", actorOrg, txTime))
 
     // 6. Lưu
+    if err := saveReturnCase(ctx, returnCase); err != nil {
+        return err
+    }
+    return saveOrderState(ctx, order)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM 12] InspectReturn: Seller kiểm tra hàng trả đã nhận
+// accepted = false (hàng không đạt điều kiện trả) -> số tiền của ReturnCase được tính lại
+// vào phần thanh toán cho Seller, số lượng được mở lại trên dòng hàng và đơn RETURNED quay về
+// DELIVERED để PayoutToSeller thanh toán được (đơn đã SETTLED được thanh toán bổ sung).
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) InspectReturn(ctx contractapi.TransactionContextInterface, orderID string, returnID string, verificationCompanyID string, accepted bool) error {
+    if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+        return err
+    }
+
+    order, err := getOrderState(ctx, orderID)
+    if err != nil {
+        return err
+    }
+    returnCase, err := getReturnCaseState(ctx, orderID, returnID)
+    if err != nil {
+        return err
+    }
+
+    actorOrg, err := checkSellerOwnership(ctx, order, verificationCompanyID)
+    if err != nil {
+        return err
+    }
+
+    if returnCase.Status != StatusReturnReceived {
+        return errInvalidState(ctx, returnCase.Status, StatusReturnReceived)
+    }
+    // Trả một phần: đơn DELIVERED hoặc đã SETTLED (Seller được trả phần còn lại trước);
+    // đã nhận hàng của yêu cầu trả toàn bộ: đơn đang RETURNED
+    if order.Status != StatusDelivered && order.Status != StatusSettled && order.Status != StatusReturned {
+        return errInvalidState(ctx, order.Status, anyOf(StatusDelivered, StatusSettled, StatusReturned))
+    }
+
+    txTime, err := getTimeNow(ctx)
+    if err != nil {
+        return err
+    }
+
+    returnCase.Status = StatusReturnInspected
+    returnCase.Accepted = accepted
+    returnCase.UpdatedAt = txTime
+    returnCase.History = append(returnCase.History, newHistoryEntry(ctx, "InspectReturn", actorOrg, txTime))
+
+    if !accepted {
+        order.RefundableAmount -= returnCase.RefundAmount
+        for _, rl := range returnCase.Lines {
+            if idx := findOrderLine(order, rl.LineID); idx >= 0 {
+                order.Lines[idx].ReturnedQuantity -= rl.Quantity
+            }
+        }
+        // Đơn RETURNED có dòng hàng được mở lại (kể cả khi từ chối một yêu cầu trả một phần tạo
+        // trước yêu cầu trả toàn bộ) quay về DELIVERED để phần đó được thanh toán cho Seller
+        if order.Status == StatusReturned && (returnCase.FullReturn || !allLinesReturned(order)) {
+            order.Status = StatusDelivered
+        }
+    }
+    order.UpdatedAt = txTime
+    order.History = append(order.History, newHistoryEntry(ctx, "InspectReturn", actorOrg, txTime))
+
+    if err := saveReturnCase(ctx, returnCase); err != nil {
+        return err
+    }
+    return saveOrderState(ctx, order)
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM 13] RefundReturn: Sàn hoàn tiền cho người mua theo một ReturnCase đã được Seller chấp nhận
+// Số tiền lớn hơn ngưỡng duyệt cần người thứ hai duyệt (xem ApproveAction); kết quả như PayoutToSeller.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) RefundReturn(ctx contractapi.TransactionContextInterface, orderID string, returnID string) (*SettlementResult, error) {
+    if prior, err := checkClientRequest(ctx); err != nil {
+        return nil, err
+    } else if prior != nil {
+        result := &SettlementResult{}
+        return result, prior.decodeResult(ctx, result)
+    }
+
+    // 1. Kiểm tra ACL
+    actorOrg, err := requireRole(ctx, RolePlatform)
+    if err != nil {
+        return nil, err
+    }
+
+    // 2. Lấy đơn hàng và yêu cầu trả hàng
+    order, err := getOrderState(ctx, orderID)
+    if err != nil {
+        return nil, err
+    }
+    returnCase, err := getReturnCaseState(ctx, orderID, returnID)
+    if err != nil {
+        return nil, err
+    }
+    if err := checkRefundAllowed(ctx, returnCase); err != nil {
+        return nil, err
+    }
+
+    txTime, err := getTimeNow(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    // 3. Số tiền lớn: tạo đề xuất chờ duyệt
+    approval, err := requireApproval(ctx, approvalRefund, order, returnID, returnCase.RefundAmount, txTime)
+    if err != nil {
+        return nil, err
+    }
+
+    // 4. Cập nhật trạng thái và lưu (trừ khi chờ duyệt)
+    if approval == nil {
+        if err := settleRefund(ctx, order, returnCase, actorOrg, txTime); err != nil {
+            return nil, err
+        }
+    }
+    result := newSettlementResult(approvalRefund, orderID, returnID, returnCase.RefundAmount, approval)
+    return result, recordClientResult(ctx, result)
+}
+
+// checkRefundAllowed: Chỉ hoàn tiền cho ReturnCase đã kiểm hàng và được chấp nhận
+func checkRefundAllowed(ctx contractapi.TransactionContextInterface, returnCase *ReturnCase) error {
+    if returnCase.Status != StatusReturnInspected {
+        return errInvalidState(ctx, returnCase.Status, StatusReturnInspected)
+    }
+    if !returnCase.Accepted {
+        return newError(ctx, ErrInvalidState, errorDetails{"currentStatus": returnCase.Status, "requiredStatus": StatusReturnInspected, "missing": "accepted"})
+    }
+    return nil
+}
+
+// settleRefund: Ghi nhận đã hoàn tiền cho ReturnCase và lưu cả ReturnCase lẫn đơn hàng
+func settleRefund(ctx contractapi.TransactionContextInterface, order *Order, returnCase *ReturnCase, actorOrg string, txTime time.Time) error {
+    returnCase.Status = StatusRefunded
+    returnCase.UpdatedAt = txTime
+    returnCase.History = append(returnCase.History, newHistoryEntry(ctx, "RefundReturn", actorOrg, txTime))
+
+    order.RefundedAmount += returnCase.RefundAmount
+    order.UpdatedAt = txTime
+    order.History = append(order.History, newHistoryEntry(ctx, "RefundReturn", actorOrg, txTime))
+
+    if err := saveReturnCase(ctx, returnCase); err != nil {
+        return err
+    }
     return saveOrderState(ctx, order)
 }
 
@@ -670,35 +1210,59 @@ func (s *SmartContract) QueryOrder(ctx contractapi.TransactionContextInterface,
     if err != nil {
         return nil, err
     }
-    
-    // Lấy thông tin người gọi
-    actorOrg, _ := getActorOrg(ctx)
-    callerCompany, _ := getCallerCompanyID(ctx)
 
     // LOGIC PHÂN QUYỀN XEM (Visibility)
-    
-    // 1. Admin Sàn: Xem hết
-    if actorOrg == "ECommercePlatformOrgMSP" {
-        return order, nil
+    if err := checkOrderAccess(ctx, order); err != nil {
+        return nil, err
     }
+    return order, nil
+}
 
-    // 2. Seller: Chỉ xem đơn của Shop mình
-    if actorOrg == "SellerOrgMSP" {
-        if order.SellerCompanyID != "" && order.SellerCompanyID != callerCompany {
-            return nil, fmt.Errorf("KHÔNG CÓ QUYỀN: Đơn này của Shop '%s'", order.SellerCompanyID)
-        }
-        return order, nil
+// -----------------------------------------------------------------------------------
+// [HÀM] QueryReturnCase: Xem một yêu cầu trả hàng (cùng quyền xem với đơn hàng gốc)
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) QueryReturnCase(ctx contractapi.TransactionContextInterface, orderID string, returnID string) (*ReturnCase, error) {
+    order, err := getOrderState(ctx, orderID)
+    if err != nil {
+        return nil, err
     }
-
-    // 3. Shipper: Chỉ xem đơn của Hãng mình
-    if actorOrg == "ShipperOrgMSP" {
-        if order.ShipperCompanyID != "" && order.ShipperCompanyID != callerCompany {
-            return nil, fmt.Errorf("KHÔNG CÓ QUYỀN: Đơn này của Hãng '%s'", order.ShipperCompanyID)
-        }
-    return order, nil
+    if err := checkOrderAccess(ctx, order); err != nil {
+        return nil, err
+    }
+    return getReturnCaseState(ctx, orderID, returnID)
 }
 
-    return nil, fmt.Errorf("tổ chức '%s' không có quyền truy cập", actorOrg)
+// -----------------------------------------------------------------------------------
+// [HÀM] QueryReturnCases: Liệt kê các yêu cầu trả hàng của một đơn
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) QueryReturnCases(ctx contractapi.TransactionContextInterface, orderID string) ([]*ReturnCase, error) {
+    order, err := getOrderState(ctx, orderID)
+    if err != nil {
+        return nil, err
+    }
+    if err := checkOrderAccess(ctx, order); err != nil {
+        return nil, err
+    }
+
+    resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(returnCaseObjectType, []string{orderID})
+    if err != nil {
+        return nil, errLedger(ctx, "GetState", err)
+    }
+    defer resultsIterator.Close()
+
+    results := []*ReturnCase{}
+    for resultsIterator.HasNext() {
+        queryResponse, err := resultsIterator.Next()
+        if err != nil {
+            return nil, err
+        }
+        var returnCase ReturnCase
+        if err := json.Unmarshal(queryResponse.Value, &returnCase); err != nil {
+            return nil, err
+        }
+        results = append(results, &returnCase)
+    }
+    return results, nil
 }
 
 // [HÀM HELPER] getQueryResult: Chuyển iterator kết quả thành slice of QueryResult
@@ -712,13 +1276,12 @@ func getQueryResult(resultsIterator shim.StateQueryIteratorInterface) ([]*QueryR
             return nil, err
         }
 
-        var order Order
-        err = json.Unmarshal(queryResponse.Value, &order)
+        order, _, err := decodeOrder(queryResponse.Value)
         if err != nil {
             return nil, err
         }
 
-        queryResult := QueryResult{Key: queryResponse.Key, Record: &order}
+        queryResult := QueryResult{Key: queryResponse.Key, Record: order}
         results = append(results, &queryResult)
     }
 
@@ -733,17 +1296,14 @@ func getQueryResult(resultsIterator shim.StateQueryIteratorInterface) ([]*QueryR
 func (s *SmartContract) QueryOrdersByString(ctx contractapi.TransactionContextInterface, queryString string) ([]*QueryResult, error) {
 
     // 1. Kiểm tra ACL (Chỉ cho Seller, Shipper, hoặc Sàn truy vấn danh sách)
-    actorOrg, err := getActorOrg(ctx)
-    if err != nil { return nil, err }
-
-    if actorOrg != "SellerOrgMSP" && actorOrg != "ECommercePlatformOrgMSP" && actorOrg != "ShipperOrgMSP" {
-        return nil, fmt.Errorf("KHÔNG CÓ QUYỀN: Tổ chức '%s' không được gọi hàm truy vấn danh sách", actorOrg)
+    if _, err := requireRole(ctx, RolePlatform, RoleSeller, RoleShipper); err != nil {
+        return nil, err
     }
 
     // 2. Thực hiện Rich Query trên sổ cái
     resultsIterator, err := ctx.GetStub().GetQueryResult(queryString)
     if err != nil {
-        return nil, fmt.Errorf("lỗi thực hiện Rich Query: %v", err)
+        return nil, errLedger(ctx, "GetQueryResult", err)
     }
 
     // 3. Chuyển đổi kết quả Iterator thành slice of QueryResult
@@ -759,24 +1319,34 @@ func (s *SmartContract) QueryOrderForOrg(ctx contractapi.TransactionContextInter
     // 1. Kiểm tra MSP người gọi (Client Identity)
     actorOrg, _ := getActorOrg(ctx)
     if actorOrg != requiredMSP {
-        return nil, fmt.Errorf("KHÔNG CÓ QUYỀN: Bạn phải là %s để truy vấn đơn này.", requiredMSP)
+        return nil, errAccessDenied(ctx, actorOrg, requiredMSP)
     }
 
     // 2. Kiểm tra quyền sở hữu bằng CompanyID
     var orderOwnerID string
 
-    if requiredMSP == "SellerOrgMSP" {
-        orderOwnerID = order.SellerCompanyID
-    } else if requiredMSP == "ShipperOrgMSP" {
-        orderOwnerID = order.ShipperCompanyID
-    } else {
+    registry, err := getRoleRegistry(ctx)
+    if err != nil {
+        return nil, err
+    }
+    var orderOwnerMSP string
+    switch registry.Orgs[requiredMSP] {
+    case RoleSeller:
+        orderOwnerMSP, orderOwnerID = order.SellerID, order.SellerCompanyID
+    case RoleShipper:
+        orderOwnerMSP, orderOwnerID = order.ShipperID, order.ShipperCompanyID
+    default:
         // Trường hợp không xác định (nên dùng QueryOrder cho Admin)
-        return nil, fmt.Errorf("MSP không hợp lệ cho truy vấn thành viên.")
+        return nil, errInvalidArgument(ctx, "requiredMSP", "MSP with role SELLER|SHIPPER")
+    }
+
+    if orderOwnerMSP != actorOrg {
+        return nil, errAccessDenied(ctx, actorOrg, orderOwnerMSP)
     }
 
     // So sánh CompanyID được Chaincode lưu với CompanyID được ứng dụng client truyền vào
     if orderOwnerID != requiredCompanyID {
-        return nil, fmt.Errorf("KHÔNG CÓ QUYỀN: Đơn hàng thuộc '%s', bạn là '%s'.", orderOwnerID, requiredCompanyID)
+        return nil, errCompanyMismatch(ctx, orderOwnerID, requiredCompanyID)
     }
 
     // Vượt qua kiểm tra
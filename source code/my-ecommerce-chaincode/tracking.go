@@ -0,0 +1,169 @@
+// my-ecommerce-chaincode/tracking.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const trackingEventObjectType = "TrackingEvent"
+
+// validCheckpointCodes là bộ mã checkpoint chuẩn được chấp nhận
+var validCheckpointCodes = map[string]bool{
+	"PICKED_UP":          true,
+	"AT_HUB":             true,
+	"DEPARTED_HUB":       true,
+	"IN_TRANSIT":         true,
+	"OUT_FOR_DELIVERY":   true,
+	"DELIVERY_ATTEMPTED": true,
+	"DELAYED":            true,
+}
+
+// getTrackingEvents: Đọc toàn bộ checkpoint của một đơn, theo thứ tự sequence
+func getTrackingEvents(ctx contractapi.TransactionContextInterface, orderID string) ([]*TrackingEvent, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(trackingEventObjectType, []string{orderID})
+	if err != nil {
+		return nil, errLedger(ctx, "GetState", err)
+	}
+	defer resultsIterator.Close()
+
+	events := []*TrackingEvent{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		var event TrackingEvent
+		if err := json.Unmarshal(queryResponse.Value, &event); err != nil {
+			return nil, errLedger(ctx, "unmarshal", err)
+		}
+		events = append(events, &event)
+	}
+	return events, nil
+}
+
+// lastActionTime trả về thời điểm gần nhất mà action được ghi trong lịch sử đơn
+func lastActionTime(order *Order, action string) time.Time {
+	for i := len(order.History) - 1; i >= 0; i-- {
+		if order.History[i].Action == action {
+			return order.History[i].Timestamp
+		}
+	}
+	return time.Time{}
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] AddTrackingEvent: Hãng vận chuyển được gán cho đơn ghi nhận một checkpoint
+// timestamp theo định dạng RFC3339; không được lùi về trước checkpoint trước đó,
+// trước lúc lấy hàng (ShipOrder) hoặc vượt quá thời điểm giao dịch.
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) AddTrackingEvent(ctx contractapi.TransactionContextInterface, orderID string, checkpointCode string, location string, timestamp string) error {
+	if prior, err := checkClientRequest(ctx); err != nil || prior != nil {
+		return err
+	}
+
+	// 1. Kiểm tra ACL: đúng tổ chức vận chuyển và đúng hãng được gán (companyCode trong chứng chỉ)
+	order, err := getOrderState(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	callerCompany, err := getCallerCompanyID(ctx)
+	if err != nil {
+		return err
+	}
+	actorOrg, err := checkShipperOwnership(ctx, order, callerCompany)
+	if err != nil {
+		return err
+	}
+
+	// 2. Kiểm tra đầu vào
+	if !validCheckpointCodes[checkpointCode] {
+		return errInvalidArgument(ctx, "checkpointCode", "unknown code "+checkpointCode)
+	}
+	eventTime, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return errInvalidArgument(ctx, "timestamp", "RFC3339")
+	}
+
+	// 3. Kiểm tra trạng thái đơn (checkpoint không đi qua saveOrderState nên kiểm tra đóng băng tại đây)
+	if order.Status != StatusShipped {
+		return errInvalidState(ctx, order.Status, StatusShipped)
+	}
+	if err := checkOrderNotFrozen(ctx, order); err != nil {
+		return err
+	}
+
+	// 4. Kiểm tra thứ tự thời gian: không trước lúc lấy hàng / checkpoint gần nhất,
+	// không sau thời điểm giao dịch
+	txTime, err := getTimeNow(ctx)
+	if err != nil {
+		return err
+	}
+	events, err := getTrackingEvents(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	notBefore := lastActionTime(order, "ShipOrder")
+	if len(events) > 0 && events[len(events)-1].Timestamp.After(notBefore) {
+		notBefore = events[len(events)-1].Timestamp
+	}
+	if eventTime.Before(notBefore) || eventTime.After(txTime) {
+		return newError(ctx, ErrTrackingOutOfOrder, errorDetails{"timestamp": eventTime, "notBefore": notBefore, "notAfter": txTime})
+	}
+
+	// 5. Lưu checkpoint mới
+	sequence := len(events) + 1
+	event := TrackingEvent{
+		DocType:          trackingEventObjectType,
+		OrderID:          orderID,
+		Sequence:         sequence,
+		CheckpointCode:   checkpointCode,
+		Location:         location,
+		Timestamp:        eventTime,
+		RecordedAt:       txTime,
+		TxID:             ctx.GetStub().GetTxID(),
+		ActorOrg:         actorOrg,
+		ShipperCompanyID: callerCompany,
+	}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return errLedger(ctx, "marshal", err)
+	}
+	// Sequence được đệm số 0 để thứ tự khóa trùng với thứ tự thời gian
+	key, err := ctx.GetStub().CreateCompositeKey(trackingEventObjectType, []string{orderID, fmt.Sprintf("%06d", sequence)})
+	if err != nil {
+		return errLedger(ctx, "CreateCompositeKey", err)
+	}
+	if err := ctx.GetStub().PutState(key, eventJSON); err != nil {
+		return errLedger(ctx, "PutState", err)
+	}
+
+	// 6. Sự kiện OrderChanged kèm checkpoint (đơn không đổi trạng thái)
+	return setOrderEvent(ctx, &OrderEvent{
+		Version:   orderEventVersion,
+		TxID:      event.TxID,
+		Action:    "AddTrackingEvent",
+		ActorOrg:  actorOrg,
+		Timestamp: txTime,
+		Orders:    []OrderEventEntry{newOrderEventEntry(order, order.Status)},
+		Tracking:  &event,
+	})
+}
+
+// -----------------------------------------------------------------------------------
+// [HÀM] GetTracking: Xem hành trình của đơn (cùng quyền xem với QueryOrder)
+// -----------------------------------------------------------------------------------
+func (s *SmartContract) GetTracking(ctx contractapi.TransactionContextInterface, orderID string) ([]*TrackingEvent, error) {
+	order, err := getOrderState(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrderAccess(ctx, order); err != nil {
+		return nil, err
+	}
+	return getTrackingEvents(ctx, orderID)
+}
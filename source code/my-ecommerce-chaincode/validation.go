@@ -0,0 +1,193 @@
+// my-ecommerce-chaincode/validation.go
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Toàn bộ quy tắc định dạng tham số được khai báo tại đây và kiểm tra một lần
+// trước mỗi giao dịch (xem beforeTransaction), thay vì rải rác trong từng hàm.
+// Các hàm nghiệp vụ chỉ còn kiểm tra những ràng buộc phụ thuộc dữ liệu trên ledger.
+
+var (
+	// ID bắt đầu bằng chữ/số nên không thể trùng khóa composite (bắt đầu bằng \x00)
+	idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:-]{0,63}$`)
+	// Mã công ty trong chứng chỉ/ERP, ví dụ "Shop_ABC", "GHN", "H&M"
+	companyIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.&-]{0,63}$`)
+)
+
+const (
+	maxBlobSize       = 64 * 1024   // Dữ liệu mã hóa của seller/shipper
+	maxLinesJSONSize  = 256 * 1024  // Danh sách dòng hàng của đơn
+	maxShortTextSize  = 256         // Địa điểm, mã checkpoint...
+	maxQueryJSONSize  = 16 * 1024   // Câu truy vấn CouchDB
+	maxConfigJSONSize = 16 * 1024   // Cấu hình chính sách
+	maxImportJSONSize = 1024 * 1024 // Lô đơn lịch sử của ImportOrders
+)
+
+// argCheck trả về mô tả ràng buộc bị vi phạm, rỗng nếu giá trị hợp lệ
+type argCheck func(value string) string
+
+// argRule gắn tên tham số (theo thứ tự khai báo của hàm) với quy tắc kiểm tra
+type argRule struct {
+	name  string
+	check argCheck
+}
+
+func matches(pattern *regexp.Regexp) argCheck {
+	return func(value string) string {
+		if !pattern.MatchString(value) {
+			return pattern.String()
+		}
+		return ""
+	}
+}
+
+func optional(check argCheck) argCheck {
+	return func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return check(value)
+	}
+}
+
+func maxSize(limit int) argCheck {
+	return func(value string) string {
+		if len(value) > limit {
+			return fmt.Sprintf("<= %d bytes", limit)
+		}
+		return ""
+	}
+}
+
+func required(limit int) argCheck {
+	return func(value string) string {
+		if value == "" {
+			return "required"
+		}
+		return maxSize(limit)(value)
+	}
+}
+
+func freezeScopeCheck(value string) string {
+	if !validFreezeScopes[FreezeScope(value)] {
+		return fmt.Sprintf("%s|%s|%s|%s", FreezeOrder, FreezeSellerCompany, FreezeShipperCompany, FreezeGlobal)
+	}
+	return ""
+}
+
+func paymentMethodCheck(value string) string {
+	if !validPaymentMethods[PaymentMethod(value)] {
+		return fmt.Sprintf("%s|%s", PaymentCOD, PaymentPrepaid)
+	}
+	return ""
+}
+
+// noCheck dùng cho tham số không phải chuỗi (bool, int) - contractapi tự kiểm tra kiểu
+func noCheck(string) string { return "" }
+
+var (
+	orderIDArg   = argRule{"orderID", matches(idPattern)}
+	returnIDArg  = argRule{"returnID", matches(idPattern)}
+	verifyingArg = argRule{"verificationCompanyID", matches(companyIDPattern)}
+	requestIDArg = argRule{"requestID", required(maxRequestIDLength)}
+	timestampArg = argRule{"timestamp", required(64)}
+)
+
+// transactionArgRules: quy tắc tham số cho từng giao dịch (hàm không có tên ở đây không nhận tham số chuỗi)
+var transactionArgRules = map[string][]argRule{
+	"CreateOrder": {
+		orderIDArg,
+		{"paymentMethod", paymentMethodCheck},
+		{"shipperCompanyID", matches(companyIDPattern)},
+		{"sellerDataBlob", maxSize(maxBlobSize)},
+		{"shipperDataBlob", maxSize(maxBlobSize)},
+		{"sellerCompanyID", optional(matches(companyIDPattern))},
+		{"linesJSON", maxSize(maxLinesJSONSize)},
+	},
+	"ConfirmPayment":        {orderIDArg},
+	"CancelOrder":           {orderIDArg},
+	"ShipOrder":             {orderIDArg, verifyingArg},
+	"ConfirmDelivery":       {orderIDArg, verifyingArg},
+	"ConfirmCODDelivery":    {orderIDArg, verifyingArg},
+	"RemitCOD":              {orderIDArg},
+	"PayoutToSeller":        {orderIDArg},
+	"RequestReturn":         {orderIDArg, returnIDArg, {"returnLinesJSON", maxSize(maxLinesJSONSize)}},
+	"ShipReturn":            {orderIDArg, returnIDArg, verifyingArg},
+	"ConfirmReturnReceived": {orderIDArg, returnIDArg, verifyingArg},
+	"InspectReturn":         {orderIDArg, returnIDArg, verifyingArg, {"accepted", noCheck}},
+	"RefundReturn":          {orderIDArg, returnIDArg},
+	"ApproveAction":         {orderIDArg, {"approvalID", required(maxShortTextSize)}},
+	"AddTrackingEvent": {
+		orderIDArg,
+		{"checkpointCode", required(maxShortTextSize)},
+		{"location", required(maxShortTextSize)},
+		timestampArg,
+	},
+	"ExpireUnpaidOrders":   {{"olderThan", maxSize(64)}, {"limit", noCheck}},
+	"SetPolicyConfig":      {{"policyJSON", required(maxConfigJSONSize)}},
+	"SetRoleRegistry":      {{"registryJSON", required(maxConfigJSONSize)}},
+	"Freeze":               {{"scope", freezeScopeCheck}, {"target", maxSize(64)}, {"reason", required(maxShortTextSize)}},
+	"Unfreeze":             {{"scope", freezeScopeCheck}, {"target", maxSize(64)}, {"reason", required(maxShortTextSize)}},
+	"MigrateOrders":        {{"fromKey", optional(matches(idPattern))}, {"limit", noCheck}},
+	"ImportOrders":         {{"ordersJSON", required(maxImportJSONSize)}},
+	"RebuildOrderCounters": {{"fromKey", optional(matches(idPattern))}, {"limit", noCheck}},
+	"InitLedger":           {{"bootstrapJSON", maxSize(maxConfigJSONSize)}, {"overwrite", noCheck}},
+
+	"QueryOrder":          {orderIDArg},
+	"QueryReturnCase":     {orderIDArg, returnIDArg},
+	"QueryReturnCases":    {orderIDArg},
+	"GetTracking":         {orderIDArg},
+	"GetOrderEndorsers":   {orderIDArg},
+	"GetApprovals":        {orderIDArg},
+	"GetFreezeLog":        {{"scope", optional(freezeScopeCheck)}, {"target", maxSize(64)}},
+	"GetClientRequest":    {requestIDArg},
+	"QueryOrdersByString": {{"queryString", required(maxQueryJSONSize)}},
+	"QueryOrderForOrg":    {orderIDArg, {"requiredMSP", required(maxShortTextSize)}, {"requiredCompanyID", matches(companyIDPattern)}},
+}
+
+// currentFunction trả về tên giao dịch (bỏ tiền tố "Contract:") và tham số của nó
+func currentFunction(ctx contractapi.TransactionContextInterface) (string, []string) {
+	function, params := ctx.GetStub().GetFunctionAndParameters()
+	if idx := strings.LastIndex(function, ":"); idx >= 0 {
+		function = function[idx+1:]
+	}
+	return function, params
+}
+
+// validateTransactionArgs kiểm tra tham số của giao dịch hiện tại theo transactionArgRules
+func validateTransactionArgs(ctx contractapi.TransactionContextInterface) error {
+	function, params := currentFunction(ctx)
+	for i, rule := range transactionArgRules[function] {
+		if i >= len(params) {
+			break // Sai số lượng tham số: contractapi sẽ báo lỗi
+		}
+		if constraint := rule.check(params[i]); constraint != "" {
+			return errInvalidArgument(ctx, rule.name, constraint)
+		}
+	}
+	return nil
+}
+
+// beforeTransaction: Kiểm tra chung cho mọi giao dịch - định dạng tham số, vai trò người gọi,
+// rồi đóng băng toàn cục
+func beforeTransaction(ctx contractapi.TransactionContextInterface) error {
+	if err := validateTransactionArgs(ctx); err != nil {
+		return err
+	}
+	if err := checkTransactionPermission(ctx); err != nil {
+		return err
+	}
+	return checkGlobalFreeze(ctx)
+}
+
+// GetBeforeTransaction: contractapi gọi hàm này trước mọi giao dịch của SmartContract
+func (s *SmartContract) GetBeforeTransaction() interface{} {
+	return beforeTransaction
+}
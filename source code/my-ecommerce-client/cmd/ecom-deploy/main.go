@@ -0,0 +1,52 @@
+// my-ecommerce-client/cmd/ecom-deploy/main.go
+
+// ecom-deploy triển khai hoặc nâng cấp chaincode ecommerce lên mạng my-ecommerce-network: đóng gói
+// với nhãn <chaincode>_<phiên bản>_<git commit>, cài lên mọi peer trong cấu hình, phê duyệt cho
+// từng tổ chức, commit với sequence = sequence đã commit + 1 rồi gọi InitLedger. Cần peer CLI
+// (Fabric 2.4+) trong PATH; lỗi ở bất kỳ bước nào dừng lệnh với mã thoát khác 0:
+//
+//	ecom-deploy -config deploy.yaml -dry-run
+//	ecom-deploy -config deploy.yaml
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"ecommerce.com/client/deploy"
+)
+
+func main() {
+	configPath := flag.String("config", "deploy.yaml", "file cấu hình triển khai")
+	version := flag.String("version", "", "phiên bản chaincode (rỗng = version trong cấu hình)")
+	dryRun := flag.Bool("dry-run", false, "chỉ truy vấn mạng và in các lệnh sẽ chạy")
+	allowDirty := flag.Bool("allow-dirty", false, "cho phép mã nguồn có thay đổi chưa commit (nhãn gói có hậu tố -dirty)")
+	force := flag.Bool("force", false, "tạo sequence mới kể cả khi gói này đã được commit")
+	overwrite := flag.Bool("init-overwrite", false, "InitLedger ghi đè cấu hình đã có trên sổ cái")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "ecom-deploy ", 0)
+
+	config, err := deploy.LoadConfig(*configPath)
+	if err != nil {
+		logger.Fatalf("Lỗi cấu hình: %v", err)
+	}
+	if *version != "" {
+		config.Version = *version
+	}
+	if *overwrite && config.Init != nil {
+		config.Init.Overwrite = true
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	options := deploy.Options{DryRun: *dryRun, AllowDirty: *allowDirty, Force: *force}
+	if _, err := deploy.New(config, options).Run(ctx); err != nil {
+		logger.Fatalf("Triển khai thất bại: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+// my-ecommerce-client/cmd/ecom-events/main.go
+
+// ecom-events nghe sự kiện OrderChanged của chaincode ecommerce và gửi webhook JSON có chữ ký
+// (HMAC-SHA256) cho hệ thống ngoài như Odoo. Vị trí đã xử lý được lưu trong file checkpoint
+// nên khởi động lại không bỏ sót sự kiện; webhook gửi không được sau khi hết lượt thử nằm
+// trong file dead-letter và được gửi lại bằng -redeliver:
+//
+//	export ECOM_WEBHOOK_SECRET=...
+//	ecom-events -profile platform -url https://odoo.example.com/fabric/webhook -types order.shipped,order.delivered
+//	ecom-events -url https://odoo.example.com/fabric/webhook -redeliver
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"ecommerce.com/client/events"
+	"ecommerce.com/client/fabric"
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+func main() {
+	profilesPath := flag.String("profiles", "profiles.yaml", "file hồ sơ kết nối")
+	profileName := flag.String("profile", "", "tên hồ sơ (rỗng = mặc định trong file)")
+	url := flag.String("url", os.Getenv("ECOM_WEBHOOK_URL"), "URL nhận webhook (ECOM_WEBHOOK_URL)")
+	secretFile := flag.String("secret-file", "", "file chứa khóa ký webhook (mặc định: biến ECOM_WEBHOOK_SECRET)")
+	checkpointPath := flag.String("checkpoint", "ecom-events.checkpoint.json", "file checkpoint")
+	deadLetterPath := flag.String("dead-letter", "ecom-events.deadletter.jsonl", "file dead-letter (JSONL)")
+	source := flag.String("source", string(events.ModeAuto), "nguồn sự kiện: auto | chaincode | block")
+	start := flag.String("start", "newest", "vị trí bắt đầu khi chưa có checkpoint: newest | oldest | <số block>")
+	types := flag.String("types", "", "loại webhook cần gửi, cách nhau dấu phẩy (rỗng = tất cả), VD: order.shipped,order.delivered")
+	attempts := flag.Int("attempts", 8, "số lần gửi tối đa mỗi webhook trước khi vào dead-letter")
+	backoff := flag.Duration("backoff", time.Second, "thời gian chờ trước lần gửi lại đầu tiên (nhân đôi sau mỗi lần)")
+	maxBackoff := flag.Duration("max-backoff", time.Minute, "thời gian chờ tối đa giữa hai lần gửi")
+	requestTimeout := flag.Duration("request-timeout", 10*time.Second, "thời gian chờ mỗi lần gửi")
+	redeliver := flag.Bool("redeliver", false, "gửi lại các webhook trong dead-letter rồi thoát (chạy khi daemon đã dừng)")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "ecom-events ", log.LstdFlags)
+
+	mode := events.Mode(*source)
+	if mode != events.ModeAuto && mode != events.ModeChaincode && mode != events.ModeBlock {
+		logger.Fatalf("-source không hợp lệ: %s", *source)
+	}
+	if *url == "" {
+		logger.Fatalf("Thiếu -url")
+	}
+	secret, err := readSecret(*secretFile)
+	if err != nil {
+		logger.Fatalf("Lỗi khóa ký: %v", err)
+	}
+	sender := &events.Sender{
+		URL:            *url,
+		Secret:         secret,
+		Client:         &http.Client{Timeout: *requestTimeout},
+		MaxAttempts:    *attempts,
+		InitialBackoff: *backoff,
+		MaxBackoff:     *maxBackoff,
+	}
+	deadLetters, err := events.OpenDeadLetterQueue(*deadLetterPath)
+	if err != nil {
+		logger.Fatalf("Lỗi mở dead-letter: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *redeliver {
+		delivered, remaining, err := deadLetters.Redeliver(ctx, sender)
+		logger.Printf("Đã gửi lại %d webhook, còn %d trong %s", delivered, remaining, *deadLetterPath)
+		if err != nil {
+			logger.Fatalf("Lỗi gửi lại: %v", err)
+		}
+		return
+	}
+
+	profile, err := fabric.LoadProfile(*profilesPath, *profileName)
+	if err != nil {
+		logger.Fatalf("Lỗi hồ sơ: %v", err)
+	}
+	conn, err := fabric.Connect(profile)
+	if err != nil {
+		logger.Fatalf("Lỗi kết nối: %v", err)
+	}
+	defer conn.Close()
+
+	checkpointer, err := client.NewFileCheckpointer(*checkpointPath)
+	if err != nil {
+		logger.Fatalf("Lỗi mở checkpoint %s: %v", *checkpointPath, err)
+	}
+	defer checkpointer.Close()
+	if checkpointer.BlockNumber() == 0 && checkpointer.TransactionID() == "" {
+		if err := initCheckpoint(ctx, conn, checkpointer, *start); err != nil {
+			logger.Fatalf("Lỗi khởi tạo checkpoint: %v", err)
+		}
+	}
+
+	dispatcher := &events.Dispatcher{Sender: sender, DeadLetters: deadLetters, Types: parseTypes(*types), Logger: logger}
+	listener := &events.Listener{
+		Network:      conn.Network(),
+		Chaincode:    profile.Chaincode,
+		Checkpointer: checkpointer,
+		Mode:         mode,
+		Logger:       logger,
+	}
+	logger.Printf("Chuyển sự kiện %s/%s (hồ sơ %s) tới %s", profile.Channel, profile.Chaincode, profile.Name, *url)
+	if err := listener.Run(ctx, dispatcher.Handle); err != nil {
+		logger.Fatalf("Dừng: %v", err)
+	}
+	logger.Printf("Đã dừng tại block %d", checkpointer.BlockNumber())
+}
+
+// initCheckpoint ghi vị trí bắt đầu ngay lần chạy đầu, để lần khởi động lại sau đó tiếp tục từ
+// đây kể cả khi chưa có sự kiện nào. Block 0 là block cấu hình nên "oldest" bắt đầu từ block 1.
+func initCheckpoint(ctx context.Context, conn *fabric.Connection, checkpointer *client.FileCheckpointer, start string) error {
+	var blockNumber uint64
+	switch start {
+	case "oldest":
+		blockNumber = 1
+	case "newest":
+		height, err := conn.ChainHeight(ctx)
+		if err != nil {
+			return err
+		}
+		blockNumber = height
+	default:
+		number, err := strconv.ParseUint(start, 10, 64)
+		if err != nil || number == 0 {
+			return fmt.Errorf("-start không hợp lệ: %s", start)
+		}
+		blockNumber = number
+	}
+	if err := checkpointer.CheckpointTransaction(blockNumber, ""); err != nil {
+		return err
+	}
+	return checkpointer.Sync()
+}
+
+func readSecret(path string) ([]byte, error) {
+	secret := os.Getenv("ECOM_WEBHOOK_SECRET")
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		secret = string(data)
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return nil, fmt.Errorf("cần -secret-file hoặc biến ECOM_WEBHOOK_SECRET")
+	}
+	return []byte(secret), nil
+}
+
+func parseTypes(list string) map[string]bool {
+	types := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			types[name] = true
+		}
+	}
+	return types
+}
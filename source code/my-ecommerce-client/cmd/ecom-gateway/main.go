@@ -0,0 +1,97 @@
+// my-ecommerce-client/cmd/ecom-gateway/main.go
+
+// ecom-gateway là dịch vụ REST cho hợp đồng ecommerce, dùng cho Odoo và các hệ thống
+// ngoài. Mỗi tổ chức chạy một instance với định danh (hồ sơ) của mình:
+//
+//	ecom-gateway -profiles profiles.yaml -profile seller -listen :8080
+//	ecom-gateway -metadata metadata.json -openapi > openapi.json   # sinh đặc tả không cần mạng
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ecommerce.com/client/contract"
+	"ecommerce.com/client/fabric"
+	"ecommerce.com/client/rest"
+)
+
+func main() {
+	profilesPath := flag.String("profiles", "profiles.yaml", "file hồ sơ kết nối")
+	profileName := flag.String("profile", "", "tên hồ sơ (rỗng = mặc định trong file)")
+	listen := flag.String("listen", ":8080", "địa chỉ HTTP")
+	timeout := flag.Duration("timeout", 0, "thời gian chờ tối đa mỗi request (0 = theo hồ sơ)")
+	printOpenAPI := flag.Bool("openapi", false, "in đặc tả OpenAPI rồi thoát")
+	metadataPath := flag.String("metadata", "", "file metadata của chaincode (dùng với -openapi, không cần kết nối)")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "ecom-gateway ", log.LstdFlags)
+
+	if *printOpenAPI && *metadataPath != "" {
+		data, err := os.ReadFile(*metadataPath)
+		if err != nil {
+			logger.Fatalf("Lỗi đọc metadata: %v", err)
+		}
+		metadata, err := contract.ParseMetadata(data)
+		if err != nil {
+			logger.Fatalf("Lỗi metadata: %v", err)
+		}
+		writeSpec(logger, metadata)
+		return
+	}
+
+	profile, err := fabric.LoadProfile(*profilesPath, *profileName)
+	if err != nil {
+		logger.Fatalf("Lỗi hồ sơ: %v", err)
+	}
+	conn, err := fabric.Connect(profile)
+	if err != nil {
+		logger.Fatalf("Lỗi kết nối: %v", err)
+	}
+	defer conn.Close()
+
+	if *printOpenAPI {
+		metadata, err := contract.FetchMetadata(context.Background(), conn.Contract())
+		if err != nil {
+			logger.Fatalf("Lỗi đọc metadata từ chaincode: %v", err)
+		}
+		writeSpec(logger, metadata)
+		return
+	}
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           rest.NewServer(conn.Contract(), logger, *timeout),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	logger.Printf("Phục vụ %s/%s với hồ sơ %s (%s) tại %s", profile.Channel, profile.Chaincode, profile.Name, profile.MSPID, *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatalf("Lỗi HTTP server: %v", err)
+	}
+}
+
+func writeSpec(logger *log.Logger, metadata *contract.Metadata) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(rest.BuildOpenAPI(metadata)); err != nil {
+		logger.Fatalf("Lỗi ghi đặc tả: %v", err)
+	}
+}
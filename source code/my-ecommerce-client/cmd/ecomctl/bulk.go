@@ -0,0 +1,233 @@
+// my-ecommerce-client/cmd/ecomctl/bulk.go
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"ecommerce.com/client/contract"
+)
+
+// errBatchFailed: ít nhất một giao dịch trong lô thất bại (chi tiết đã in trong bảng kết quả)
+var errBatchFailed = errors.New("có giao dịch thất bại")
+
+// requestIDColumn là cột CSV tùy chọn chứa request ID của từng dòng
+const requestIDColumn = "requestID"
+
+// job là một giao dịch trong lô: tham số theo tên và request ID
+type job struct {
+	line      int // Dòng trong file CSV (0 = từ dòng lệnh)
+	values    map[string]interface{}
+	requestID string
+}
+
+// jobResult là kết quả của một job
+type jobResult struct {
+	Line        int             `json:"line,omitempty"`
+	OrderID     string          `json:"orderID"`
+	Transaction string          `json:"transaction,omitempty"`
+	OK          bool            `json:"ok"`
+	RequestID   string          `json:"requestID,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       *contract.Error `json:"error,omitempty"`
+}
+
+// resolver chọn giao dịch cho một job
+type resolver func(ctx context.Context, values map[string]interface{}) (string, error)
+
+func fixedTransaction(name string) resolver {
+	return func(context.Context, map[string]interface{}) (string, error) {
+		return name, nil
+	}
+}
+
+// collectJobs tạo job từ file CSV hoặc danh sách mã đơn; defaults điền các tham số
+// không có trong dòng CSV
+func (a *app) collectJobs(csvPath string, orderIDs []string, requestID string, defaults map[string]interface{}) ([]job, error) {
+	if csvPath != "" {
+		if len(orderIDs) > 0 || requestID != "" {
+			return nil, usagef("--csv không dùng chung với mã đơn hoặc --request-id")
+		}
+		return readCSVJobs(csvPath, defaults)
+	}
+	if len(orderIDs) == 0 {
+		return nil, usagef("cần ít nhất một mã đơn hoặc --csv")
+	}
+	if requestID != "" && len(orderIDs) > 1 {
+		return nil, usagef("--request-id chỉ dùng khi gọi một đơn")
+	}
+	jobs := make([]job, len(orderIDs))
+	for i, orderID := range orderIDs {
+		values := copyValues(defaults)
+		values["orderID"] = orderID
+		jobs[i] = job{values: values, requestID: requestID}
+	}
+	return jobs, nil
+}
+
+// readCSVJobs đọc file CSV có dòng tiêu đề là tên tham số (orderID, verificationCompanyID...).
+// Cột lines / returnLines nhận JSON hoặc dạng rút gọn "sku:sl:giá|..." / "lineID:sl|...".
+func readCSVJobs(path string, defaults map[string]interface{}) ([]job, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: không đọc được dòng tiêu đề: %w", path, err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	var jobs []job
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		j := job{line: line, values: copyValues(defaults)}
+		for i, column := range header {
+			value := strings.TrimSpace(record[i])
+			switch {
+			case value == "":
+				continue
+			case column == requestIDColumn:
+				j.requestID = value
+			case column == "lines" && !isJSON(value):
+				lines, err := parseOrderLineSpecs(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s dòng %d: %w", path, line, err)
+				}
+				j.values[column] = lines
+			case column == "returnLines" && !isJSON(value):
+				lines, err := parseReturnLineSpecs(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s dòng %d: %w", path, line, err)
+				}
+				j.values[column] = lines
+			default:
+				j.values[column] = value
+			}
+		}
+		jobs = append(jobs, j)
+	}
+	if len(jobs) == 0 {
+		return nil, usagef("%s không có dòng dữ liệu", path)
+	}
+	return jobs, nil
+}
+
+// runBatch gửi các job song song (tối đa -parallel), in bảng kết quả theo thứ tự job.
+// Khi chạy từ CSV, dòng không có requestID dùng request ID suy ra từ tham số để chạy lại
+// file không thực hiện giao dịch hai lần.
+func (a *app) runBatch(ctx context.Context, jobs []job, resolve resolver, fromCSV bool) error {
+	client, err := a.connect()
+	if err != nil {
+		return err
+	}
+
+	results := make([]jobResult, len(jobs))
+	parallel := a.parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range jobs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = runJob(ctx, client, jobs[i], resolve, fromCSV)
+		}(i)
+	}
+	wg.Wait()
+
+	failed := false
+	var succeeded []string
+	for _, result := range results {
+		if result.OK {
+			succeeded = append(succeeded, result.OrderID)
+		} else {
+			failed = true
+		}
+	}
+	rememberOrderIDs(succeeded...)
+
+	if a.output == "json" {
+		if err := writeJSON(a.stdout, results); err != nil {
+			return err
+		}
+	} else {
+		printResults(a.stdout, results)
+	}
+	if failed {
+		return errBatchFailed
+	}
+	return nil
+}
+
+func runJob(ctx context.Context, client *contract.Client, j job, resolve resolver, fromCSV bool) jobResult {
+	result := jobResult{Line: j.line, OrderID: fmt.Sprint(j.values["orderID"]), RequestID: j.requestID}
+	fail := func(err error) jobResult {
+		contractErr, ok := contract.AsError(err)
+		if !ok {
+			contractErr = &contract.Error{Code: contract.ErrUnknown, Message: err.Error()}
+		}
+		result.Error = contractErr
+		return result
+	}
+
+	name, err := resolve(ctx, j.values)
+	if err != nil {
+		return fail(err)
+	}
+	result.Transaction = name
+	if fromCSV && result.RequestID == "" {
+		tx, _ := contract.Lookup(name)
+		args, err := tx.Args(j.values)
+		if err != nil {
+			return fail(err)
+		}
+		result.RequestID = "ecomctl-" + contract.RequestFingerprint(name, args)[:40]
+	}
+
+	payload, err := client.Do(ctx, name, j.values, result.RequestID)
+	if err != nil {
+		return fail(err)
+	}
+	result.OK = true
+	if len(payload) > 0 && json.Valid(payload) {
+		result.Result = payload
+	}
+	return result
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values)+1)
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
+
+func isJSON(value string) bool {
+	return strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{")
+}
@@ -0,0 +1,285 @@
+// my-ecommerce-client/cmd/ecomctl/completion.go
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"ecommerce.com/client/contract"
+	"ecommerce.com/client/fabric"
+)
+
+// completeCommand là lệnh ẩn mà script completion gọi: ecomctl __complete <các từ đã gõ>
+const completeCommand = "__complete"
+
+// maxCachedOrderIDs giới hạn số mã đơn nhớ lại cho tab-completion
+const maxCachedOrderIDs = 1000
+
+const bashCompletion = `# ecomctl bash completion
+_ecomctl() {
+	local IFS=$'\n'
+	COMPREPLY=($(ecomctl __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+	[[ ${#COMPREPLY[@]} == 1 && ${COMPREPLY[0]} == *= ]] && compopt -o nospace
+}
+complete -o default -F _ecomctl ecomctl
+`
+
+const zshCompletion = `# ecomctl zsh completion
+autoload -U +X bashcompinit && bashcompinit
+` + bashCompletion
+
+// globalValueFlags là các cờ chung nhận giá trị ở từ kế tiếp
+var globalValueFlags = map[string]bool{"-profiles": true, "-profile": true, "-o": true, "-timeout": true, "-parallel": true}
+
+// completionCommand: ecomctl completion bash|zsh
+func (a *app) completionCommand(args []string) error {
+	if len(args) != 1 {
+		return usagef("cách dùng: ecomctl completion bash|zsh")
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Fprint(a.stdout, bashCompletion)
+	case "zsh":
+		fmt.Fprint(a.stdout, zshCompletion)
+	default:
+		return usagef("shell không hỗ trợ: %s (có: bash, zsh)", args[0])
+	}
+	return nil
+}
+
+// completeCommand in các gợi ý (mỗi dòng một) cho từ cuối cùng trong words. Không kết nối
+// sổ cái: mã đơn lấy từ bộ nhớ đệm do các lệnh trước ghi lại.
+func (a *app) completeCommand(words []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current, previous := words[len(words)-1], words[:len(words)-1]
+	// Con trỏ ngay sau "tên=": bash coi "=" là từ hiện tại và sẽ thay nó bằng gợi ý
+	prefix := ""
+	if current == "=" {
+		prefix, current, previous = "=", "", words
+	}
+	for _, candidate := range completions(a.profilesPath, previous, current) {
+		if strings.HasPrefix(candidate, current) {
+			fmt.Fprintln(a.stdout, prefix+candidate)
+		}
+	}
+}
+
+func completions(profilesPath string, previous []string, current string) []string {
+	// Bỏ qua cờ chung đứng trước lệnh
+	i := 0
+	for i < len(previous) && strings.HasPrefix(previous[i], "-") {
+		name := "-" + strings.TrimLeft(previous[i], "-")
+		if strings.Contains(name, "=") || !globalValueFlags[name] {
+			i++
+			continue
+		}
+		if i == len(previous)-1 {
+			return globalFlagValues(profilesPath, name)
+		}
+		i += 2
+	}
+	previous = previous[i:]
+
+	if len(previous) == 0 {
+		if strings.HasPrefix(current, "-") {
+			return []string{"-profiles", "-profile", "-o", "-timeout", "-parallel"}
+		}
+		return []string{"order", "invoke", "completion", "help"}
+	}
+	switch previous[0] {
+	case "order":
+		return orderCompletions(previous[1:], current)
+	case "invoke":
+		return invokeCompletions(previous[1:])
+	case "completion":
+		if len(previous) == 1 {
+			return []string{"bash", "zsh"}
+		}
+	}
+	return nil
+}
+
+func globalFlagValues(profilesPath string, name string) []string {
+	switch name {
+	case "-o":
+		return []string{"table", "json"}
+	case "-profile":
+		return profileNames(profilesPath)
+	}
+	return nil
+}
+
+func orderCompletions(previous []string, current string) []string {
+	if len(previous) == 0 {
+		return orderVerbs
+	}
+	verb := previous[0]
+	if strings.HasPrefix(current, "-") {
+		return verbFlags[verb]
+	}
+	switch strings.TrimLeft(previous[len(previous)-1], "-") {
+	case "payment":
+		return []string{contract.PaymentCOD, contract.PaymentPrepaid}
+	case "status":
+		statuses := make([]string, len(contract.OrderStatuses))
+		for i, status := range contract.OrderStatuses {
+			statuses[i] = string(status)
+		}
+		return statuses
+	case "id", "company", "shipper-company", "seller-company", "seller-data", "shipper-data",
+		"line", "lines", "csv", "request-id", "limit", "batch", "max-bytes", "report":
+		return nil
+	}
+	switch verb {
+	case "create", "list", "import":
+		return nil
+	}
+	return cachedOrderIDs()
+}
+
+// invokeCompletions gợi ý tên giao dịch, sau đó "tham số=" chưa dùng; sau "orderID=" là mã đơn.
+// Bash tách "tên=giá trị" thành ba từ (COMP_WORDBREAKS có "="), nên ghép lại trước.
+func invokeCompletions(previous []string) []string {
+	if len(previous) == 0 {
+		names := make([]string, len(contract.Transactions))
+		for i, tx := range contract.Transactions {
+			names[i] = tx.Name
+		}
+		return names
+	}
+	tx, ok := contract.Lookup(previous[0])
+	if !ok {
+		return nil
+	}
+	assignments := joinAssignments(previous[1:])
+	if n := len(assignments); n > 0 && strings.HasSuffix(assignments[n-1], "=") {
+		param, ok := tx.Param(strings.TrimSuffix(assignments[n-1], "="))
+		switch {
+		case !ok:
+			return nil
+		case param.Name == "orderID":
+			return cachedOrderIDs()
+		case param.Type == contract.Boolean:
+			return []string{"true", "false"}
+		}
+		return nil
+	}
+	used := map[string]bool{}
+	for _, assignment := range assignments {
+		used[strings.SplitN(assignment, "=", 2)[0]] = true
+	}
+	var candidates []string
+	for _, param := range tx.Params {
+		if !used[param.Name] {
+			candidates = append(candidates, param.Name+"=")
+		}
+	}
+	return candidates
+}
+
+// joinAssignments ghép ["a", "=", "b", "c", "="] thành ["a=b", "c="]
+func joinAssignments(words []string) []string {
+	var joined []string
+	for i := 0; i < len(words); i++ {
+		if words[i] == "=" && len(joined) > 0 {
+			joined[len(joined)-1] += "="
+			if i+1 < len(words) {
+				i++
+				joined[len(joined)-1] += words[i]
+			}
+			continue
+		}
+		joined = append(joined, words[i])
+	}
+	return joined
+}
+
+// profileNames đọc tên các hồ sơ trong file hồ sơ (không kiểm tra nội dung)
+func profileNames(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var file fabric.ProfileFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(file.Profiles))
+	for name := range file.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func orderIDCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ecomctl", "order-ids"), nil
+}
+
+// cachedOrderIDs trả về các mã đơn đã gặp, mới nhất trước
+func cachedOrderIDs() []string {
+	path, err := orderIDCachePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Fields(string(data))
+	ids := make([]string, 0, len(lines))
+	for i := len(lines) - 1; i >= 0; i-- {
+		ids = append(ids, lines[i])
+	}
+	return ids
+}
+
+// rememberOrderIDs ghi các mã đơn vừa thao tác vào bộ nhớ đệm cho tab-completion. Lỗi ghi
+// file được bỏ qua: completion không được làm hỏng lệnh chính.
+func rememberOrderIDs(ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+	path, err := orderIDCachePath()
+	if err != nil {
+		return
+	}
+	fresh := map[string]bool{}
+	for _, id := range ids {
+		fresh[id] = true
+	}
+	// Giữ thứ tự cũ -> mới, đưa mã vừa dùng xuống cuối
+	var kept []string
+	if data, err := os.ReadFile(path); err == nil {
+		for _, id := range strings.Fields(string(data)) {
+			if !fresh[id] {
+				kept = append(kept, id)
+			}
+		}
+	}
+	for _, id := range ids {
+		if fresh[id] && id != "" {
+			kept = append(kept, id)
+			fresh[id] = false
+		}
+	}
+	if len(kept) > maxCachedOrderIDs {
+		kept = kept[len(kept)-maxCachedOrderIDs:]
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	os.WriteFile(path, []byte(strings.Join(kept, "\n")+"\n"), 0o600)
+}
@@ -0,0 +1,401 @@
+// my-ecommerce-client/cmd/ecomctl/import.go
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce.com/client/contract"
+)
+
+// Giới hạn của một lô ImportOrders (my-ecommerce-chaincode/import.go, validation.go)
+const (
+	maxImportBatch = 100
+	maxImportBytes = 1024 * 1024
+)
+
+// importStatusColumns: cột thời điểm trong CSV -> trạng thái, theo thứ tự của máy trạng thái
+// (dùng khi hai trạng thái có cùng thời điểm)
+var importStatusColumns = []struct {
+	column string
+	status contract.Status
+}{
+	{"createdAt", contract.StatusCreated},
+	{"paidAt", contract.StatusPaid},
+	{"shippedAt", contract.StatusShipped},
+	{"deliveredAt", contract.StatusDelivered},
+	{"settledAt", contract.StatusSettled},
+	{"cancelledAt", contract.StatusCancelled},
+	{"expiredAt", contract.StatusExpired},
+}
+
+// importItem là một đơn đọc từ file và vị trí của nó (dòng CSV / JSONL, phần tử JSON)
+type importItem struct {
+	source string
+	order  *contract.ImportOrder
+}
+
+// importReportRow là kết quả của một đơn trong báo cáo
+type importReportRow struct {
+	Source    string             `json:"source"`
+	OrderID   string             `json:"orderID"`
+	Batch     int                `json:"batch"`
+	Status    string             `json:"status"` // imported | exists | failed
+	Code      contract.ErrorCode `json:"code,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	RequestID string             `json:"requestID"`
+}
+
+// orderImport: ecomctl order import <file.csv|file.json|file.jsonl> [--batch 100] [--max-bytes N] [--report file]
+func (a *app) orderImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("order import", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	batchSize := fs.Int("batch", maxImportBatch, "số đơn tối đa mỗi giao dịch")
+	maxBytes := fs.Int("max-bytes", maxImportBytes*3/4, "kích thước JSON tối đa của một lô (byte)")
+	reportPath := fs.String("report", "", "ghi báo cáo từng đơn ra file (.csv hoặc .json)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	if len(positional) != 1 {
+		return usagef("cách dùng: ecomctl order import <file.csv|file.json|file.jsonl> [--batch N] [--max-bytes N] [--report file]")
+	}
+	if *batchSize < 1 || *batchSize > maxImportBatch {
+		return usagef("--batch phải trong khoảng 1..%d", maxImportBatch)
+	}
+	if *maxBytes < 1 || *maxBytes > maxImportBytes {
+		return usagef("--max-bytes phải trong khoảng 1..%d", maxImportBytes)
+	}
+
+	items, err := readImportFile(positional[0])
+	if err != nil {
+		return err
+	}
+	batches, err := splitImportBatches(items, *batchSize, *maxBytes)
+	if err != nil {
+		return err
+	}
+	client, err := a.connect()
+	if err != nil {
+		return err
+	}
+
+	// Các lô gửi lần lượt: mọi lô cùng cập nhật bộ đếm trạng thái nên gửi song song sẽ xung đột MVCC
+	var report []importReportRow
+	for i, batch := range batches {
+		rows, err := runImportBatch(ctx, client, i+1, batch)
+		if err != nil {
+			return err
+		}
+		report = append(report, rows...)
+	}
+
+	failed := 0
+	var imported []string
+	for _, row := range report {
+		switch row.Status {
+		case "imported":
+			imported = append(imported, row.OrderID)
+		case "failed":
+			failed++
+		}
+	}
+	rememberOrderIDs(imported...)
+
+	if *reportPath != "" {
+		if err := writeImportReport(*reportPath, report); err != nil {
+			return err
+		}
+	}
+	if a.output == "json" {
+		if err := writeJSON(a.stdout, report); err != nil {
+			return err
+		}
+	} else {
+		printImportReport(a.stdout, report, len(batches))
+	}
+	if failed > 0 {
+		return errBatchFailed
+	}
+	return nil
+}
+
+// runImportBatch gửi một lô với request ID suy ra từ nội dung lô: chạy lại file với cùng cách
+// chia lô nhận lại kết quả cũ thay vì nạp lần nữa. Lỗi của cả lô (ngoài lỗi hợp đồng) dừng lệnh.
+func runImportBatch(ctx context.Context, client *contract.Client, number int, batch []importItem) ([]importReportRow, error) {
+	orders := make([]*contract.ImportOrder, len(batch))
+	for i, item := range batch {
+		orders[i] = item.order
+	}
+	values := map[string]interface{}{"orders": orders}
+	tx, _ := contract.Lookup("ImportOrders")
+	args, err := tx.Args(values)
+	if err != nil {
+		return nil, err
+	}
+	requestID := "ecomctl-" + contract.RequestFingerprint(tx.Name, args)[:40]
+
+	rows := make([]importReportRow, len(batch))
+	for i, item := range batch {
+		rows[i] = importReportRow{Source: item.source, OrderID: item.order.OrderID, Batch: number, RequestID: requestID}
+	}
+
+	result, err := client.ImportOrders(ctx, orders, requestID)
+	if err != nil {
+		contractErr, ok := contract.AsError(err)
+		if !ok {
+			return nil, fmt.Errorf("lô %d: %w", number, err)
+		}
+		for i := range rows {
+			rows[i].Status, rows[i].Code, rows[i].Message = "failed", contractErr.Code, contractErr.Message
+		}
+		return rows, nil
+	}
+
+	for i := range rows {
+		rows[i].Status = "imported"
+	}
+	for _, failure := range result.Failed {
+		if failure.Index < 0 || failure.Index >= len(rows) {
+			continue
+		}
+		row := &rows[failure.Index]
+		row.Status, row.Code, row.Message = "failed", failure.Code, failure.Message
+		// Đơn đã có trên sổ cái (VD: chạy lại file sau khi đổi --batch) không tính là lỗi
+		if failure.Code == contract.ErrOrderAlreadyExists {
+			row.Status = "exists"
+		}
+	}
+	return rows, nil
+}
+
+// splitImportBatches chia các đơn thành lô theo số đơn và kích thước JSON
+func splitImportBatches(items []importItem, batchSize int, maxBytes int) ([][]importItem, error) {
+	var batches [][]importItem
+	var current []importItem
+	size := 2 // "[]"
+	for _, item := range items {
+		encoded, err := json.Marshal(item.order)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", item.source, err)
+		}
+		if len(encoded)+2 > maxBytes {
+			return nil, fmt.Errorf("%s: đơn %s có JSON %d byte, vượt --max-bytes", item.source, item.order.OrderID, len(encoded))
+		}
+		if len(current) == batchSize || (len(current) > 0 && size+len(encoded)+1 > maxBytes) {
+			batches = append(batches, current)
+			current, size = nil, 2
+		}
+		current = append(current, item)
+		size += len(encoded) + 1
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches, nil
+}
+
+// readImportFile đọc đơn lịch sử theo phần mở rộng: .csv, .json (mảng) hoặc .jsonl (mỗi dòng một đơn)
+func readImportFile(path string) ([]importItem, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var items []importItem
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		items, err = readImportCSV(file)
+	case ".json":
+		var orders []*contract.ImportOrder
+		if err = json.NewDecoder(file).Decode(&orders); err == nil {
+			for i, order := range orders {
+				items = append(items, importItem{source: "#" + strconv.Itoa(i+1), order: order})
+			}
+		}
+	case ".jsonl", ".ndjson":
+		items, err = readImportJSONLines(file)
+	default:
+		return nil, usagef("%s: chỉ hỗ trợ .csv, .json, .jsonl", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(items) == 0 {
+		return nil, usagef("%s không có đơn nào", path)
+	}
+	return items, nil
+}
+
+func readImportJSONLines(r io.Reader) ([]importItem, error) {
+	var items []importItem
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportBytes)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var order contract.ImportOrder
+		if err := json.Unmarshal([]byte(text), &order); err != nil {
+			return nil, fmt.Errorf("dòng %d: %w", line, err)
+		}
+		items = append(items, importItem{source: "dòng " + strconv.Itoa(line), order: &order})
+	}
+	return items, scanner.Err()
+}
+
+// readImportCSV đọc CSV có dòng tiêu đề: orderID, paymentMethod, sellerID, sellerCompanyID,
+// shipperCompanyID, lines (JSON hoặc sku:sl:giá|...), các cột thời điểm RFC 3339 (createdAt,
+// paidAt, shippedAt, deliveredAt, settledAt, cancelledAt, expiredAt, codRemittedAt),
+// payoutAmount, sellerDataBlob, shipperDataBlob. Chuỗi trạng thái là các cột thời điểm có giá trị.
+func readImportCSV(r io.Reader) ([]importItem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("không đọc được dòng tiêu đề: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	var items []importItem
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			values[column] = strings.TrimSpace(record[i])
+		}
+		order, err := parseImportRecord(values)
+		if err != nil {
+			return nil, fmt.Errorf("dòng %d: %w", line, err)
+		}
+		items = append(items, importItem{source: "dòng " + strconv.Itoa(line), order: order})
+	}
+	return items, nil
+}
+
+func parseImportRecord(values map[string]string) (*contract.ImportOrder, error) {
+	order := &contract.ImportOrder{
+		OrderID:          values["orderID"],
+		PaymentMethod:    strings.ToUpper(values["paymentMethod"]),
+		SellerID:         values["sellerID"],
+		SellerCompanyID:  values["sellerCompanyID"],
+		ShipperCompanyID: values["shipperCompanyID"],
+		SellerDataBlob:   values["sellerDataBlob"],
+		ShipperDataBlob:  values["shipperDataBlob"],
+	}
+	if lines := values["lines"]; isJSON(lines) {
+		if err := json.Unmarshal([]byte(lines), &order.Lines); err != nil {
+			return nil, fmt.Errorf("lines: %w", err)
+		}
+	} else if lines != "" {
+		parsed, err := parseOrderLineSpecs(lines)
+		if err != nil {
+			return nil, err
+		}
+		order.Lines = parsed
+	}
+
+	for _, column := range importStatusColumns {
+		at, err := parseImportTime(values, column.column)
+		if err != nil {
+			return nil, err
+		}
+		if at != nil {
+			order.Statuses = append(order.Statuses, contract.ImportStatus{Status: column.status, At: *at})
+		}
+	}
+	sort.SliceStable(order.Statuses, func(i, j int) bool {
+		return order.Statuses[i].At.Before(order.Statuses[j].At)
+	})
+
+	remittedAt, err := parseImportTime(values, "codRemittedAt")
+	if err != nil {
+		return nil, err
+	}
+	order.CodRemittedAt = remittedAt
+	if value := values["payoutAmount"]; value != "" {
+		payout, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("payoutAmount: %q không phải số nguyên", value)
+		}
+		order.PayoutAmount = &payout
+	}
+	return order, nil
+}
+
+func parseImportTime(values map[string]string, column string) (*time.Time, error) {
+	value := values[column]
+	if value == "" {
+		return nil, nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q không đúng định dạng RFC 3339 (VD: 2025-11-01T10:00:00+07:00)", column, value)
+	}
+	return &at, nil
+}
+
+// writeImportReport ghi báo cáo dạng JSON (.json) hoặc CSV
+func writeImportReport(path string, report []importReportRow) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = writeJSON(file, report)
+	} else {
+		writer := csv.NewWriter(file)
+		writer.Write([]string{"source", "orderID", "batch", "status", "code", "message", "requestID"})
+		for _, row := range report {
+			writer.Write([]string{row.Source, row.OrderID, strconv.Itoa(row.Batch), row.Status, string(row.Code), row.Message, row.RequestID})
+		}
+		writer.Flush()
+		err = writer.Error()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// printImportReport in các đơn không được nạp và dòng tổng kết
+func printImportReport(w io.Writer, report []importReportRow, batches int) {
+	counts := map[string]int{}
+	table := newTable(w)
+	header := false
+	for _, row := range report {
+		counts[row.Status]++
+		if row.Status == "imported" {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(table, "NGUỒN\tMÃ ĐƠN\tLÔ\tKẾT QUẢ")
+			header = true
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s: %s\n", row.Source, orDash(row.OrderID), row.Batch, row.Code, row.Message)
+	}
+	table.Flush()
+	fmt.Fprintf(w, "%d lô: %d đã nạp, %d đã có, %d thất bại\n", batches, counts["imported"], counts["exists"], counts["failed"])
+}
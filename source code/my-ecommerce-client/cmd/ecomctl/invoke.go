@@ -0,0 +1,77 @@
+// my-ecommerce-client/cmd/ecomctl/invoke.go
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"ecommerce.com/client/contract"
+)
+
+// invokeCommand: ecomctl invoke <Giao dịch> [tên=giá trị ...] [--request-id X]
+// Gọi giao dịch bất kỳ trong danh mục (cấu hình, phê duyệt, truy vấn...); kết quả in dạng JSON.
+func (a *app) invokeCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("invoke", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	requestID := fs.String("request-id", "", "request ID (idempotency)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	if len(positional) == 0 {
+		return usagef("cách dùng: ecomctl invoke <Giao dịch> [tên=giá trị ...]")
+	}
+	tx, ok := contract.Lookup(positional[0])
+	if !ok {
+		return usagef("giao dịch không có trong danh mục: %s", positional[0])
+	}
+
+	values := map[string]interface{}{}
+	for _, assignment := range positional[1:] {
+		name, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return usagef("tham số %q: cần dạng tên=giá trị", assignment)
+		}
+		if _, ok := tx.Param(name); !ok {
+			return usagef("%s không có tham số %s (có: %s)", tx.Name, name, paramNames(tx))
+		}
+		values[name] = value
+	}
+	for _, param := range tx.Params {
+		if _, ok := values[param.Name]; !ok && !param.Optional {
+			return usagef("%s thiếu tham số %s", tx.Name, param.Name)
+		}
+	}
+
+	client, err := a.connect()
+	if err != nil {
+		return err
+	}
+	result, err := client.Do(ctx, tx.Name, values, *requestID)
+	if err != nil {
+		return err
+	}
+	if orderID, ok := values["orderID"].(string); ok {
+		rememberOrderIDs(orderID)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	if !json.Valid(result) {
+		fmt.Fprintln(a.stdout, string(result))
+		return nil
+	}
+	return writeJSON(a.stdout, json.RawMessage(result))
+}
+
+func paramNames(tx *contract.Transaction) string {
+	names := make([]string, len(tx.Params))
+	for i, param := range tx.Params {
+		names[i] = param.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,204 @@
+// my-ecommerce-client/cmd/ecomctl/main.go
+
+// ecomctl là công cụ dòng lệnh cho người vận hành, thay cho việc docker exec vào container
+// cli và gõ lệnh peer chaincode invoke:
+//
+//	ecomctl -profile seller order create --id SO001 --payment COD --shipper-company GHN --line SKU-1:2:150000
+//	ecomctl -profile shipper order ship SO001 SO002 --company GHN
+//	ecomctl -profile platform order pay --csv paid.csv
+//	ecomctl -o json order show SO001
+//	source <(ecomctl completion bash)
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ecommerce.com/client/contract"
+	"ecommerce.com/client/fabric"
+)
+
+const usage = `Cách dùng: ecomctl [tùy chọn chung] <lệnh> ...
+
+Lệnh:
+  order create|pay|ship|deliver|remit|payout|cancel|return|show|list|history
+  invoke <Giao dịch> [tên=giá trị ...]   Gọi giao dịch bất kỳ theo danh mục
+  completion bash|zsh                    In script tab-completion
+
+Tùy chọn chung:
+`
+
+// app giữ cấu hình chung và kết nối (mở khi lệnh cần tới sổ cái)
+type app struct {
+	profilesPath string
+	profileName  string
+	output       string
+	timeout      time.Duration
+	parallel     int
+
+	stdout io.Writer
+	stderr io.Writer
+
+	conn      *fabric.Connection
+	client    *contract.Client
+	companyID string // companyID của hồ sơ, mặc định cho --company / --seller-company
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	a := &app{stdout: stdout, stderr: stderr}
+	fs := flag.NewFlagSet("ecomctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&a.profilesPath, "profiles", defaultProfilesPath(), "file hồ sơ kết nối (ECOMCTL_PROFILES)")
+	fs.StringVar(&a.profileName, "profile", os.Getenv("ECOMCTL_PROFILE"), "tên hồ sơ (ECOMCTL_PROFILE)")
+	fs.StringVar(&a.output, "o", "table", "định dạng kết quả: table | json")
+	fs.DurationVar(&a.timeout, "timeout", 2*time.Minute, "thời gian chờ tối đa của lệnh")
+	fs.IntVar(&a.parallel, "parallel", 4, "số giao dịch gửi song song khi chạy theo lô")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if a.output != "table" && a.output != "json" {
+		fmt.Fprintf(stderr, "-o phải là table hoặc json\n")
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	defer a.close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	var err error
+	command, rest := fs.Arg(0), fs.Args()[1:]
+	switch command {
+	case "order":
+		err = a.orderCommand(ctx, rest)
+	case "invoke":
+		err = a.invokeCommand(ctx, rest)
+	case "completion":
+		err = a.completionCommand(rest)
+	case completeCommand:
+		a.completeCommand(rest)
+	case "help":
+		fs.Usage()
+	default:
+		fmt.Fprintf(stderr, "lệnh không hợp lệ: %s\n", command)
+		fs.Usage()
+		return 2
+	}
+	return a.exitCode(err)
+}
+
+// exitCode in lỗi và trả về mã thoát: 1 = giao dịch thất bại, 2 = sai cách dùng
+func (a *app) exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintln(a.stderr, usageErr.Error())
+		return 2
+	}
+	if errors.Is(err, errBatchFailed) {
+		return 1
+	}
+	if contractErr, ok := contract.AsError(err); ok {
+		if a.output == "json" {
+			writeJSON(a.stderr, contractErr)
+		} else {
+			fmt.Fprintf(a.stderr, "Lỗi %s: %s\n", contractErr.Code, contractErr.Message)
+		}
+		return 1
+	}
+	fmt.Fprintf(a.stderr, "Lỗi: %v\n", err)
+	return 1
+}
+
+// usageError là lỗi cách dùng (thiếu tham số, cờ sai)
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+func usagef(format string, args ...interface{}) error {
+	return usageError(fmt.Sprintf(format, args...))
+}
+
+// connect mở kết nối Gateway theo hồ sơ (một lần cho mỗi lệnh)
+func (a *app) connect() (*contract.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+	profile, err := fabric.LoadProfile(a.profilesPath, a.profileName)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := fabric.Connect(profile)
+	if err != nil {
+		return nil, err
+	}
+	a.conn = conn
+	a.companyID = profile.CompanyID
+	a.client = contract.NewClient(conn.Contract())
+	return a.client, nil
+}
+
+func (a *app) close() {
+	if a.conn != nil {
+		a.conn.Close()
+	}
+}
+
+// defaultProfilesPath: ECOMCTL_PROFILES, ./profiles.yaml hoặc ~/.config/ecomctl/profiles.yaml
+func defaultProfilesPath() string {
+	if path := os.Getenv("ECOMCTL_PROFILES"); path != "" {
+		return path
+	}
+	if _, err := os.Stat("profiles.yaml"); err == nil {
+		return "profiles.yaml"
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "ecomctl", "profiles.yaml")
+	}
+	return "profiles.yaml"
+}
+
+// parseInterspersed cho phép cờ đứng sau tham số vị trí: "ship SO001 --company GHN"
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// stringList là cờ lặp lại được: --line A --line B
+type stringList []string
+
+func (s *stringList) String() string     { return strings.Join(*s, ",") }
+func (s *stringList) Set(v string) error { *s = append(*s, v); return nil }
@@ -0,0 +1,383 @@
+// my-ecommerce-client/cmd/ecomctl/order.go
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ecommerce.com/client/contract"
+)
+
+// orderVerbs theo thứ tự hiển thị / gợi ý
+var orderVerbs = []string{"create", "pay", "ship", "deliver", "remit", "payout", "cancel", "return", "show", "list", "history", "import"}
+
+// orderAction là thao tác một bước trên đơn (chỉ cần orderID và có thể thêm mã công ty)
+type orderAction struct {
+	transaction string // Rỗng: chọn theo phương thức thanh toán (deliver)
+	company     bool   // Nhận --company (verificationCompanyID)
+}
+
+var orderActions = map[string]orderAction{
+	"pay":     {transaction: "ConfirmPayment"},
+	"ship":    {transaction: "ShipOrder", company: true},
+	"deliver": {company: true},
+	"remit":   {transaction: "RemitCOD"},
+	"payout":  {transaction: "PayoutToSeller"},
+	"cancel":  {transaction: "CancelOrder"},
+}
+
+// verbFlags là các cờ của từng lệnh con, dùng cho tab-completion
+var verbFlags = map[string][]string{
+	"create":  {"--id", "--payment", "--shipper-company", "--seller-company", "--seller-data", "--shipper-data", "--line", "--lines", "--csv", "--request-id"},
+	"pay":     {"--csv", "--request-id"},
+	"ship":    {"--company", "--csv", "--request-id"},
+	"deliver": {"--company", "--csv", "--request-id"},
+	"remit":   {"--csv", "--request-id"},
+	"payout":  {"--csv", "--request-id"},
+	"cancel":  {"--csv", "--request-id"},
+	"return":  {"--line", "--csv", "--request-id"},
+	"list":    {"--status", "--seller-company", "--shipper-company", "--payment", "--limit"},
+	"history": {"--tracking"},
+	"import":  {"--batch", "--max-bytes", "--report"},
+}
+
+func (a *app) orderCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usagef("cách dùng: ecomctl order %s ...", strings.Join(orderVerbs, "|"))
+	}
+	verb, rest := args[0], args[1:]
+	if action, ok := orderActions[verb]; ok {
+		return a.orderAction(ctx, verb, action, rest)
+	}
+	switch verb {
+	case "create":
+		return a.orderCreate(ctx, rest)
+	case "return":
+		return a.orderReturn(ctx, rest)
+	case "show":
+		return a.orderShow(ctx, rest)
+	case "list":
+		return a.orderList(ctx, rest)
+	case "history":
+		return a.orderHistory(ctx, rest)
+	case "import":
+		return a.orderImport(ctx, rest)
+	}
+	return usagef("lệnh order không hợp lệ: %s (có: %s)", verb, strings.Join(orderVerbs, ", "))
+}
+
+// orderAction: ecomctl order pay|ship|deliver|remit|payout|cancel <orderID>... [--company X] [--csv file]
+func (a *app) orderAction(ctx context.Context, verb string, action orderAction, args []string) error {
+	fs := flag.NewFlagSet("order "+verb, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	csvPath := fs.String("csv", "", "file CSV, mỗi dòng một đơn (cột orderID[, verificationCompanyID, requestID])")
+	requestID := fs.String("request-id", "", "request ID (idempotency) khi gọi một đơn")
+	company := ""
+	if action.company {
+		fs.StringVar(&company, "company", "", "mã công ty vận chuyển (mặc định: companyID của hồ sơ)")
+	}
+	orderIDs, err := parseInterspersed(fs, args)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	client, err := a.connect()
+	if err != nil {
+		return err
+	}
+	defaults := map[string]interface{}{}
+	if action.company {
+		if company == "" {
+			company = a.companyID
+		}
+		if company != "" {
+			defaults["verificationCompanyID"] = company
+		}
+	}
+
+	jobs, err := a.collectJobs(*csvPath, orderIDs, *requestID, defaults)
+	if err != nil {
+		return err
+	}
+
+	resolve := func(ctx context.Context, values map[string]interface{}) (string, error) {
+		return action.transaction, nil
+	}
+	if action.transaction == "" {
+		// deliver: ConfirmCODDelivery cho đơn COD, ConfirmDelivery cho đơn PREPAID
+		resolve = func(ctx context.Context, values map[string]interface{}) (string, error) {
+			order, err := client.Order(ctx, fmt.Sprint(values["orderID"]))
+			if err != nil {
+				return "", err
+			}
+			if order.PaymentMethod == contract.PaymentCOD {
+				return "ConfirmCODDelivery", nil
+			}
+			return "ConfirmDelivery", nil
+		}
+	}
+	return a.runBatch(ctx, jobs, resolve, *csvPath != "")
+}
+
+// orderCreate: ecomctl order create --id SO001 --payment COD --shipper-company GHN --line SKU:2:150000
+func (a *app) orderCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("order create", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	orderID := fs.String("id", "", "mã đơn hàng")
+	payment := fs.String("payment", contract.PaymentCOD, "phương thức thanh toán: COD | PREPAID")
+	shipperCompany := fs.String("shipper-company", "", "mã công ty vận chuyển")
+	sellerCompany := fs.String("seller-company", "", "mã cửa hàng (mặc định: companyID của hồ sơ)")
+	sellerData := fs.String("seller-data", "", "dữ liệu riêng của Seller")
+	shipperData := fs.String("shipper-data", "", "dữ liệu riêng của Shipper")
+	linesJSON := fs.String("lines", "", "dòng hàng dạng JSON")
+	var lineSpecs stringList
+	fs.Var(&lineSpecs, "line", "dòng hàng [lineID:]sku:số lượng:đơn giá (lặp lại được)")
+	csvPath := fs.String("csv", "", "file CSV, mỗi dòng một đơn (cột theo tham số CreateOrder, lines = JSON hoặc sku:sl:giá|...)")
+	requestID := fs.String("request-id", "", "request ID (idempotency)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	if *orderID == "" && len(positional) == 1 {
+		*orderID = positional[0]
+	}
+
+	if _, err := a.connect(); err != nil {
+		return err
+	}
+	if *sellerCompany == "" {
+		*sellerCompany = a.companyID
+	}
+	defaults := map[string]interface{}{
+		"paymentMethod":    *payment,
+		"shipperCompanyID": *shipperCompany,
+		"sellerCompanyID":  *sellerCompany,
+		"sellerDataBlob":   *sellerData,
+		"shipperDataBlob":  *shipperData,
+	}
+	switch {
+	case *linesJSON != "":
+		defaults["lines"] = *linesJSON
+	case len(lineSpecs) > 0:
+		lines, err := parseOrderLineSpecs(strings.Join(lineSpecs, "|"))
+		if err != nil {
+			return usageError(err.Error())
+		}
+		defaults["lines"] = lines
+	}
+
+	var orderIDs []string
+	if *csvPath == "" {
+		if *orderID == "" {
+			return usagef("cần --id hoặc --csv")
+		}
+		orderIDs = []string{*orderID}
+	}
+	jobs, err := a.collectJobs(*csvPath, orderIDs, *requestID, defaults)
+	if err != nil {
+		return err
+	}
+	return a.runBatch(ctx, jobs, fixedTransaction("CreateOrder"), *csvPath != "")
+}
+
+// orderReturn: ecomctl order return <orderID> <returnID> [--line lineID:số lượng ...]
+func (a *app) orderReturn(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("order return", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	var lineSpecs stringList
+	fs.Var(&lineSpecs, "line", "dòng trả lineID:số lượng (lặp lại được; bỏ qua = trả toàn bộ)")
+	csvPath := fs.String("csv", "", "file CSV (cột orderID, returnID[, returnLines])")
+	requestID := fs.String("request-id", "", "request ID (idempotency)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	defaults := map[string]interface{}{}
+	if len(lineSpecs) > 0 {
+		lines, err := parseReturnLineSpecs(strings.Join(lineSpecs, "|"))
+		if err != nil {
+			return usageError(err.Error())
+		}
+		defaults["returnLines"] = lines
+	}
+	var orderIDs []string
+	if *csvPath == "" {
+		if len(positional) != 2 {
+			return usagef("cách dùng: ecomctl order return <orderID> <returnID> [--line lineID:số lượng ...]")
+		}
+		orderIDs = positional[:1]
+		defaults["returnID"] = positional[1]
+	}
+	if _, err := a.connect(); err != nil {
+		return err
+	}
+	jobs, err := a.collectJobs(*csvPath, orderIDs, *requestID, defaults)
+	if err != nil {
+		return err
+	}
+	return a.runBatch(ctx, jobs, fixedTransaction("RequestReturn"), *csvPath != "")
+}
+
+// orderShow: ecomctl order show <orderID>
+func (a *app) orderShow(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return usagef("cách dùng: ecomctl order show <orderID>")
+	}
+	client, err := a.connect()
+	if err != nil {
+		return err
+	}
+	order, err := client.Order(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	rememberOrderIDs(order.OrderID)
+	var returnCases []*contract.ReturnCase
+	if len(order.ReturnIDs) > 0 {
+		if returnCases, err = client.ReturnCases(ctx, order.OrderID); err != nil {
+			return err
+		}
+	}
+
+	if a.output == "json" {
+		return writeJSON(a.stdout, struct {
+			*contract.Order
+			Returns []*contract.ReturnCase `json:"returns,omitempty"`
+		}{order, returnCases})
+	}
+	printOrder(a.stdout, order, returnCases)
+	return nil
+}
+
+// orderList: ecomctl order list [--status SHIPPED] [--seller-company X] [--limit 50]
+func (a *app) orderList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("order list", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	status := fs.String("status", "", "lọc theo trạng thái")
+	sellerCompany := fs.String("seller-company", "", "lọc theo cửa hàng")
+	shipperCompany := fs.String("shipper-company", "", "lọc theo công ty vận chuyển")
+	payment := fs.String("payment", "", "lọc theo phương thức thanh toán")
+	limit := fs.Int("limit", 50, "số đơn tối đa (0 = không giới hạn)")
+	if _, err := parseInterspersed(fs, args); err != nil {
+		return usageError(err.Error())
+	}
+
+	selector := map[string]interface{}{}
+	for field, value := range map[string]string{
+		"status":           strings.ToUpper(*status),
+		"sellerCompanyID":  *sellerCompany,
+		"shipperCompanyID": *shipperCompany,
+		"paymentMethod":    strings.ToUpper(*payment),
+	} {
+		if value != "" {
+			selector[field] = value
+		}
+	}
+	client, err := a.connect()
+	if err != nil {
+		return err
+	}
+	orders, err := client.Orders(ctx, selector, *limit)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.OrderID
+	}
+	rememberOrderIDs(ids...)
+
+	if a.output == "json" {
+		return writeJSON(a.stdout, orders)
+	}
+	printOrderTable(a.stdout, orders)
+	return nil
+}
+
+// orderHistory: ecomctl order history <orderID> [--tracking]
+func (a *app) orderHistory(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("order history", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	withTracking := fs.Bool("tracking", false, "kèm các mốc hành trình vận chuyển")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	if len(positional) != 1 {
+		return usagef("cách dùng: ecomctl order history <orderID> [--tracking]")
+	}
+	client, err := a.connect()
+	if err != nil {
+		return err
+	}
+	order, err := client.Order(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	rememberOrderIDs(order.OrderID)
+	var events []*contract.TrackingEvent
+	if *withTracking {
+		if events, err = client.Tracking(ctx, order.OrderID); err != nil {
+			return err
+		}
+	}
+
+	if a.output == "json" {
+		return writeJSON(a.stdout, struct {
+			History  []contract.HistoryEntry   `json:"history"`
+			Tracking []*contract.TrackingEvent `json:"tracking,omitempty"`
+		}{order.History, events})
+	}
+	printHistory(a.stdout, order.History, events)
+	return nil
+}
+
+// parseOrderLineSpecs đọc "sku:sl:giá|lineID:sku:sl:giá" thành dòng hàng (lineID mặc định L1, L2...)
+func parseOrderLineSpecs(specs string) ([]contract.OrderLine, error) {
+	var lines []contract.OrderLine
+	for i, spec := range strings.Split(specs, "|") {
+		parts := strings.Split(strings.TrimSpace(spec), ":")
+		line := contract.OrderLine{LineID: "L" + strconv.Itoa(i+1)}
+		switch len(parts) {
+		case 3:
+		case 4:
+			line.LineID, parts = parts[0], parts[1:]
+		default:
+			return nil, fmt.Errorf("dòng hàng %q: cần [lineID:]sku:số lượng:đơn giá", spec)
+		}
+		line.SKU = parts[0]
+		quantity, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("dòng hàng %q: số lượng không hợp lệ", spec)
+		}
+		price, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("dòng hàng %q: đơn giá không hợp lệ", spec)
+		}
+		line.Quantity, line.UnitPrice = quantity, price
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// parseReturnLineSpecs đọc "lineID:sl|lineID:sl" thành dòng trả
+func parseReturnLineSpecs(specs string) ([]contract.ReturnLine, error) {
+	var lines []contract.ReturnLine
+	for _, spec := range strings.Split(specs, "|") {
+		parts := strings.Split(strings.TrimSpace(spec), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("dòng trả %q: cần lineID:số lượng", spec)
+		}
+		quantity, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("dòng trả %q: số lượng không hợp lệ", spec)
+		}
+		lines = append(lines, contract.ReturnLine{LineID: parts[0], Quantity: quantity})
+	}
+	return lines, nil
+}
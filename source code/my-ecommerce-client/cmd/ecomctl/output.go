@@ -0,0 +1,158 @@
+// my-ecommerce-client/cmd/ecomctl/output.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"ecommerce.com/client/contract"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(timeLayout)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// printOrder in chi tiết một đơn hàng, các dòng hàng và yêu cầu trả hàng
+func printOrder(w io.Writer, order *contract.Order, returnCases []*contract.ReturnCase) {
+	table := newTable(w)
+	fmt.Fprintf(table, "Mã đơn:\t%s\n", order.OrderID)
+	fmt.Fprintf(table, "Trạng thái:\t%s\n", order.Status)
+	fmt.Fprintf(table, "Thanh toán:\t%s\n", order.PaymentMethod)
+	if order.PaymentMethod == contract.PaymentCOD {
+		fmt.Fprintf(table, "Trạng thái COD:\t%s\n", orDash(order.CodStatus))
+	}
+	fmt.Fprintf(table, "Cửa hàng:\t%s (%s)\n", orDash(order.SellerCompanyID), orDash(order.SellerID))
+	fmt.Fprintf(table, "Vận chuyển:\t%s (%s)\n", orDash(order.ShipperCompanyID), orDash(order.ShipperID))
+	fmt.Fprintf(table, "Tạo lúc:\t%s\n", formatTime(order.CreatedAt))
+	fmt.Fprintf(table, "Cập nhật:\t%s\n", formatTime(order.UpdatedAt))
+	if !order.DeliveryTimestamp.IsZero() {
+		fmt.Fprintf(table, "Giao lúc:\t%s\n", formatTime(order.DeliveryTimestamp))
+	}
+	fmt.Fprintf(table, "Tổng tiền:\t%d\n", order.TotalAmount)
+	fmt.Fprintf(table, "Phí sàn:\t%d\n", order.PlatformFee)
+	fmt.Fprintf(table, "Chi trả Seller:\t%d\n", order.PayoutAmount)
+	fmt.Fprintf(table, "Đã hoàn:\t%d / %d\n", order.RefundedAmount, order.RefundableAmount)
+	table.Flush()
+
+	if len(order.Lines) > 0 {
+		fmt.Fprintln(w)
+		table = newTable(w)
+		fmt.Fprintln(table, "DÒNG\tSKU\tSL\tĐƠN GIÁ\tĐÃ TRẢ")
+		for _, line := range order.Lines {
+			fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%d\n", line.LineID, line.SKU, line.Quantity, line.UnitPrice, line.ReturnedQuantity)
+		}
+		table.Flush()
+	}
+
+	if len(returnCases) > 0 {
+		fmt.Fprintln(w)
+		table = newTable(w)
+		fmt.Fprintln(table, "MÃ TRẢ\tTRẠNG THÁI\tDÒNG\tHOÀN\tCẬP NHẬT")
+		for _, returnCase := range returnCases {
+			lines := "toàn bộ"
+			if !returnCase.FullReturn {
+				specs := make([]string, len(returnCase.Lines))
+				for i, line := range returnCase.Lines {
+					specs[i] = fmt.Sprintf("%s:%d", line.LineID, line.Quantity)
+				}
+				lines = strings.Join(specs, " ")
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\n", returnCase.ReturnID, returnCase.Status, lines, returnCase.RefundAmount, formatTime(returnCase.UpdatedAt))
+		}
+		table.Flush()
+	}
+}
+
+// printOrderTable in danh sách đơn hàng, mỗi đơn một dòng
+func printOrderTable(w io.Writer, orders []*contract.Order) {
+	table := newTable(w)
+	fmt.Fprintln(table, "MÃ ĐƠN\tTRẠNG THÁI\tTHANH TOÁN\tCỬA HÀNG\tVẬN CHUYỂN\tTỔNG TIỀN\tCẬP NHẬT")
+	for _, order := range orders {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", order.OrderID, order.Status, order.PaymentMethod,
+			orDash(order.SellerCompanyID), orDash(order.ShipperCompanyID), order.TotalAmount, formatTime(order.UpdatedAt))
+	}
+	table.Flush()
+	fmt.Fprintf(w, "%d đơn\n", len(orders))
+}
+
+// printHistory in lịch sử thao tác và (nếu có) các mốc hành trình
+func printHistory(w io.Writer, history []contract.HistoryEntry, events []*contract.TrackingEvent) {
+	table := newTable(w)
+	fmt.Fprintln(table, "THỜI GIAN\tTHAO TÁC\tTỔ CHỨC\tTX ID")
+	for _, entry := range history {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", formatTime(entry.Timestamp), entry.Action, entry.ActorOrg, entry.TxID)
+	}
+	table.Flush()
+
+	if len(events) > 0 {
+		fmt.Fprintln(w)
+		table = newTable(w)
+		fmt.Fprintln(table, "#\tTHỜI GIAN\tMỐC\tĐỊA ĐIỂM\tCÔNG TY")
+		for _, event := range events {
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", event.Sequence, formatTime(event.Timestamp), event.CheckpointCode,
+				orDash(event.Location), orDash(event.ShipperCompanyID))
+		}
+		table.Flush()
+	}
+}
+
+// printResults in kết quả của một lô giao dịch
+func printResults(w io.Writer, results []jobResult) {
+	table := newTable(w)
+	fmt.Fprintln(table, "DÒNG\tMÃ ĐƠN\tGIAO DỊCH\tKẾT QUẢ\tREQUEST ID")
+	failed := 0
+	for _, result := range results {
+		line := "-"
+		if result.Line > 0 {
+			line = fmt.Sprint(result.Line)
+		}
+		outcome := "OK"
+		if !result.OK {
+			failed++
+			outcome = fmt.Sprintf("%s: %s", result.Error.Code, result.Error.Message)
+		} else if approvalID := pendingApproval(result.Result); approvalID != "" {
+			outcome = "CHỜ DUYỆT: " + approvalID
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", line, result.OrderID, orDash(result.Transaction), outcome, orDash(result.RequestID))
+	}
+	table.Flush()
+	if len(results) > 1 {
+		fmt.Fprintf(w, "%d thành công, %d thất bại\n", len(results)-failed, failed)
+	}
+}
+
+// pendingApproval trả về approvalID nếu kết quả là thanh toán / hoàn tiền đang chờ duyệt
+func pendingApproval(payload json.RawMessage) string {
+	var settlement contract.SettlementResult
+	if len(payload) == 0 || json.Unmarshal(payload, &settlement) != nil || settlement.Status != contract.ApprovalPending {
+		return ""
+	}
+	return settlement.ApprovalID
+}
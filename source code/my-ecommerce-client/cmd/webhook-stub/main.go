@@ -0,0 +1,126 @@
+// my-ecommerce-client/cmd/webhook-stub/main.go
+
+// webhook-stub là bên nhận webhook giả lập Odoo để thử ecom-events trên máy: kiểm tra chữ ký,
+// in từng webhook, báo webhook trùng (gửi lại) và có thể cố ý trả lỗi để thử cơ chế gửi lại /
+// dead-letter:
+//
+//	ECOM_WEBHOOK_SECRET=dev webhook-stub -listen :9000 -fail-every 3
+//	ECOM_WEBHOOK_SECRET=dev ecom-events -profile platform -url http://localhost:9000/webhook
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"ecommerce.com/client/events"
+)
+
+type stub struct {
+	secret    []byte
+	tolerance time.Duration
+	failEvery int
+	reject    bool
+	logger    *log.Logger
+
+	mu       sync.Mutex
+	requests int
+	seen     map[string]bool
+}
+
+func main() {
+	listen := flag.String("listen", ":9000", "địa chỉ HTTP")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "độ lệch timestamp tối đa (0 = không kiểm tra)")
+	failEvery := flag.Int("fail-every", 0, "trả 503 cho mỗi request thứ N (0 = không)")
+	reject := flag.Bool("reject", false, "trả 422 cho mọi webhook (thử dead-letter ngay)")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "webhook-stub ", log.LstdFlags)
+	secret := os.Getenv("ECOM_WEBHOOK_SECRET")
+	if secret == "" {
+		logger.Fatalf("Thiếu biến ECOM_WEBHOOK_SECRET")
+	}
+	s := &stub{secret: []byte(secret), tolerance: *tolerance, failEvery: *failEvery, reject: *reject, logger: logger, seen: map[string]bool{}}
+
+	server := &http.Server{Addr: *listen, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+	logger.Printf("Nhận webhook tại %s", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatalf("Lỗi HTTP server: %v", err)
+	}
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "chỉ nhận POST", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := events.Verify(s.secret, r.Header.Get(events.HeaderTimestamp), r.Header.Get(events.HeaderSignature), body, s.tolerance, time.Now()); err != nil {
+		s.logger.Printf("Từ chối %s: %v", r.Header.Get(events.HeaderEventID), err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests++
+	fail := s.failEvery > 0 && s.requests%s.failEvery == 0
+	s.mu.Unlock()
+	if fail {
+		s.logger.Printf("Cố ý trả 503 cho %s (lần %s)", r.Header.Get(events.HeaderEventID), r.Header.Get(events.HeaderAttempt))
+		http.Error(w, "thử lại sau", http.StatusServiceUnavailable)
+		return
+	}
+	if s.reject {
+		http.Error(w, "từ chối", http.StatusUnprocessableEntity)
+		return
+	}
+
+	var webhook events.Webhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	duplicate := s.seen[webhook.ID]
+	s.seen[webhook.ID] = true
+	s.mu.Unlock()
+
+	note := ""
+	if duplicate {
+		note = " (trùng, bỏ qua)"
+	}
+	s.logger.Printf("%s %s đơn %s: %s -> %s, %s bởi %s, block %d%s", webhook.ID, webhook.Type, webhook.Order.OrderID,
+		orDash(string(webhook.Order.PreviousStatus)), webhook.Order.Status, webhook.Action, webhook.ActorOrg, webhook.BlockNumber, note)
+	if webhook.Tracking != nil {
+		s.logger.Printf("    checkpoint #%d %s tại %s", webhook.Tracking.Sequence, webhook.Tracking.CheckpointCode, webhook.Tracking.Location)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
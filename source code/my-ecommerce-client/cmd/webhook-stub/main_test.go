@@ -0,0 +1,67 @@
+// my-ecommerce-client/cmd/webhook-stub/main_test.go
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ecommerce.com/client/contract"
+	"ecommerce.com/client/events"
+)
+
+// newStubServer chạy stub trên httptest và trả về Sender gửi tới nó bằng đúng secret
+func newStubServer(t *testing.T, failEvery int) (*stub, *events.Sender) {
+	s := &stub{secret: []byte("dev"), tolerance: time.Minute, failEvery: failEvery, logger: log.New(io.Discard, "", 0), seen: map[string]bool{}}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	sender := &events.Sender{URL: server.URL, Secret: []byte("dev"), MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	return s, sender
+}
+
+// Request thứ 2 và 4 bị trả 503: webhook 2 và 3 được gửi lại một lần
+func TestStubFailEveryIsRetriedBySender(t *testing.T) {
+	s, sender := newStubServer(t, 2)
+
+	for i, want := range []int{1, 2, 2} {
+		webhook := &events.Webhook{ID: fmt.Sprintf("tx%d-0", i+1), Type: "order.delivered", Order: contract.OrderEventEntry{OrderID: "S001"}}
+		attempts, err := sender.Send(context.Background(), webhook)
+		if err != nil {
+			t.Fatalf("webhook %d: %v", i, err)
+		}
+		if attempts != want {
+			t.Errorf("webhook %d gửi %d lần, muốn %d", i, attempts, want)
+		}
+	}
+	if len(s.seen) != 3 {
+		t.Errorf("stub nhận %d webhook khác nhau, muốn 3", len(s.seen))
+	}
+}
+
+func TestStubRejectsBadSignatureAndMarksDuplicates(t *testing.T) {
+	s, sender := newStubServer(t, 0)
+	webhook := &events.Webhook{ID: "tx1-0", Type: "order.shipped", Order: contract.OrderEventEntry{OrderID: "S002"}}
+
+	sender.Secret = []byte("sai")
+	if attempts, err := sender.Send(context.Background(), webhook); err == nil || attempts != 1 {
+		t.Fatalf("chữ ký sai: %d lần, lỗi %v; muốn bị từ chối ngay", attempts, err)
+	}
+	if len(s.seen) != 0 {
+		t.Fatal("stub ghi nhận webhook có chữ ký sai")
+	}
+
+	sender.Secret = []byte("dev")
+	for i := 0; i < 2; i++ {
+		if _, err := sender.Send(context.Background(), webhook); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.requests != 2 || len(s.seen) != 1 {
+		t.Errorf("stub nhận %d request, %d webhook; muốn 2 request cùng một webhook", s.requests, len(s.seen))
+	}
+}
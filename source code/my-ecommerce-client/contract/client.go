@@ -0,0 +1,136 @@
+// my-ecommerce-client/contract/client.go
+
+package contract
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Client gọi giao dịch theo tên tham số và giải mã kết quả thành kiểu của hợp đồng
+type Client struct {
+	Contract Contract
+}
+
+// NewClient tạo Client trên một Contract
+func NewClient(c Contract) *Client {
+	return &Client{Contract: c}
+}
+
+// Do gọi giao dịch name với tham số theo tên; requestID rỗng = không dùng idempotency
+func (c *Client) Do(ctx context.Context, name string, values map[string]interface{}, requestID string) ([]byte, error) {
+	tx, ok := Lookup(name)
+	if !ok {
+		return nil, &Error{Code: ErrUnknown, Message: "giao dịch không có trong danh mục: " + name}
+	}
+	args, err := tx.Args(values)
+	if err != nil {
+		return nil, err
+	}
+	return Invoke(ctx, c.Contract, tx, Call{Args: args}.WithRequestID(requestID))
+}
+
+// Order đọc một đơn hàng (QueryOrder)
+func (c *Client) Order(ctx context.Context, orderID string) (*Order, error) {
+	var order Order
+	if err := c.decode(ctx, "QueryOrder", map[string]interface{}{"orderID": orderID}, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Orders tìm đơn hàng theo selector CouchDB (QueryOrdersByString), VD: {"status": "SHIPPED"}.
+// Điều kiện docType = Order luôn được thêm vào; limit = 0: không giới hạn.
+func (c *Client) Orders(ctx context.Context, selector map[string]interface{}, limit int) ([]*Order, error) {
+	query := map[string]interface{}{"selector": withDocType(selector)}
+	if limit > 0 {
+		query["limit"] = limit
+	}
+	var results []*QueryResult
+	if err := c.decode(ctx, "QueryOrdersByString", map[string]interface{}{"queryString": query}, &results); err != nil {
+		return nil, err
+	}
+	orders := make([]*Order, 0, len(results))
+	for _, result := range results {
+		if result.Record != nil {
+			orders = append(orders, result.Record)
+		}
+	}
+	return orders, nil
+}
+
+// ReturnCases đọc các yêu cầu trả hàng của đơn
+func (c *Client) ReturnCases(ctx context.Context, orderID string) ([]*ReturnCase, error) {
+	var returnCases []*ReturnCase
+	if err := c.decode(ctx, "QueryReturnCases", map[string]interface{}{"orderID": orderID}, &returnCases); err != nil {
+		return nil, err
+	}
+	return returnCases, nil
+}
+
+// Tracking đọc hành trình vận chuyển của đơn
+func (c *Client) Tracking(ctx context.Context, orderID string) ([]*TrackingEvent, error) {
+	var events []*TrackingEvent
+	if err := c.decode(ctx, "GetTracking", map[string]interface{}{"orderID": orderID}, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// RoleRegistry đọc sổ đăng ký vai trò tổ chức và công ty (GetRoleRegistry)
+func (c *Client) RoleRegistry(ctx context.Context) (*RoleRegistry, error) {
+	var registry RoleRegistry
+	if err := c.decode(ctx, "GetRoleRegistry", nil, &registry); err != nil {
+		return nil, err
+	}
+	return &registry, nil
+}
+
+// ImportOrders nạp một lô đơn lịch sử; requestID cố định cho lô giúp gửi lại không nạp hai lần.
+// Đơn bị từ chối nằm trong ImportResult.Failed, lỗi trả về là lỗi của cả lô.
+func (c *Client) ImportOrders(ctx context.Context, orders []*ImportOrder, requestID string) (*ImportResult, error) {
+	payload, err := c.Do(ctx, "ImportOrders", map[string]interface{}{"orders": orders}, requestID)
+	if err != nil {
+		return nil, err
+	}
+	var result ImportResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return nil, fmt.Errorf("kết quả ImportOrders không hợp lệ: %w", err)
+	}
+	return &result, nil
+}
+
+func (c *Client) decode(ctx context.Context, name string, values map[string]interface{}, target interface{}) error {
+	result, err := c.Do(ctx, name, values, "")
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result, target); err != nil {
+		return fmt.Errorf("kết quả %s không hợp lệ: %w", name, err)
+	}
+	return nil
+}
+
+func withDocType(selector map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{"docType": "Order"}
+	for key, value := range selector {
+		merged[key] = value
+	}
+	return merged
+}
+
+// RequestFingerprint băm tên giao dịch và tham số giống chaincode (idempotency.go). Dùng làm
+// request ID cố định để chạy lại một lô (CSV) mà không thực hiện giao dịch hai lần.
+func RequestFingerprint(name string, args []string) string {
+	hash := sha256.New()
+	for _, part := range append([]string{name}, args...) {
+		fmt.Fprintf(hash, "%d:%s;", len(part), part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
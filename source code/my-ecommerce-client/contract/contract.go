@@ -0,0 +1,65 @@
+// my-ecommerce-client/contract/contract.go
+
+// Package contract mô tả cách client gọi chaincode ecommerce: danh mục giao dịch,
+// lỗi có cấu trúc của hợp đồng và giao diện Contract dùng chung cho Fabric Gateway
+// thật (package fabric) lẫn gateway giả trong bộ nhớ (Fake).
+package contract
+
+import (
+	"context"
+)
+
+// Khóa transient map mà chaincode đọc (idempotency.go, errors.go)
+const (
+	TransientRequestID = "requestID"
+	TransientLocale    = "locale"
+)
+
+// MetadataTransaction là giao dịch hệ thống trả về metadata của hợp đồng (contractapi)
+const MetadataTransaction = "org.hyperledger.fabric:GetMetadata"
+
+// Call là một lần gọi giao dịch với tham số theo vị trí
+type Call struct {
+	Name      string
+	Args      []string
+	Transient map[string][]byte
+}
+
+// WithRequestID gắn request ID để gửi lại an toàn (chaincode trả kết quả lần đầu)
+func (c Call) WithRequestID(requestID string) Call {
+	return c.withTransient(TransientRequestID, requestID)
+}
+
+// WithLocale chọn ngôn ngữ thông báo lỗi ("vi" mặc định, "en")
+func (c Call) WithLocale(locale string) Call {
+	return c.withTransient(TransientLocale, locale)
+}
+
+func (c Call) withTransient(key string, value string) Call {
+	if value == "" {
+		return c
+	}
+	transient := make(map[string][]byte, len(c.Transient)+1)
+	for k, v := range c.Transient {
+		transient[k] = v
+	}
+	transient[key] = []byte(value)
+	c.Transient = transient
+	return c
+}
+
+// Contract gửi giao dịch tới chaincode. Submit ghi sổ cái và chờ commit; Evaluate chỉ
+// truy vấn một peer. Lỗi của hợp đồng được trả về dưới dạng *Error.
+type Contract interface {
+	Submit(ctx context.Context, call Call) ([]byte, error)
+	Evaluate(ctx context.Context, call Call) ([]byte, error)
+}
+
+// Invoke gọi giao dịch theo loại khai báo trong danh mục (Submit hoặc Evaluate)
+func Invoke(ctx context.Context, c Contract, tx *Transaction, call Call) ([]byte, error) {
+	call.Name = tx.Name
+	if tx.Kind == Evaluate {
+		return c.Evaluate(ctx, call)
+	}
+	return c.Submit(ctx, call)
+}
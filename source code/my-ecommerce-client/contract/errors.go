@@ -0,0 +1,95 @@
+// my-ecommerce-client/contract/errors.go
+
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrorCode là mã lỗi ổn định của hợp đồng (my-ecommerce-chaincode/errors.go)
+type ErrorCode string
+
+const (
+	ErrLedger              ErrorCode = "LEDGER_ERROR"
+	ErrIdentity            ErrorCode = "IDENTITY_ERROR"
+	ErrInvalidArgument     ErrorCode = "INVALID_ARGUMENT"
+	ErrOrderNotFound       ErrorCode = "ORDER_NOT_FOUND"
+	ErrOrderAlreadyExists  ErrorCode = "ORDER_ALREADY_EXISTS"
+	ErrReturnNotFound      ErrorCode = "RETURN_NOT_FOUND"
+	ErrReturnAlreadyExists ErrorCode = "RETURN_ALREADY_EXISTS"
+	ErrNothingToReturn     ErrorCode = "NOTHING_TO_RETURN"
+	ErrAccessDenied        ErrorCode = "ACCESS_DENIED"
+	ErrRoleRequired        ErrorCode = "ROLE_REQUIRED"
+	ErrCompanyMismatch     ErrorCode = "COMPANY_MISMATCH"
+	ErrInvalidState        ErrorCode = "INVALID_STATE"
+	ErrPaymentMethod       ErrorCode = "PAYMENT_METHOD_MISMATCH"
+	ErrWindowExpired       ErrorCode = "WINDOW_EXPIRED"
+	ErrWindowNotElapsed    ErrorCode = "WINDOW_NOT_ELAPSED"
+	ErrTrackingOutOfOrder  ErrorCode = "TRACKING_OUT_OF_ORDER"
+	ErrRequestNotFound     ErrorCode = "REQUEST_NOT_FOUND"
+	ErrRequestIDConflict   ErrorCode = "REQUEST_ID_CONFLICT"
+	ErrApprovalNotFound    ErrorCode = "APPROVAL_NOT_FOUND"
+	ErrApprovalPending     ErrorCode = "APPROVAL_PENDING"
+	ErrApprovalExpired     ErrorCode = "APPROVAL_EXPIRED"
+	ErrSelfApproval        ErrorCode = "SELF_APPROVAL"
+	ErrFrozen              ErrorCode = "FROZEN"
+	ErrConfigExists        ErrorCode = "CONFIG_EXISTS"
+)
+
+// Mã lỗi phía client: lỗi xảy ra ngoài chaincode (kết nối, commit, hết thời gian chờ)
+const (
+	ErrUnavailable  ErrorCode = "GATEWAY_UNAVAILABLE" // Không kết nối được peer/gateway
+	ErrTimeout      ErrorCode = "TIMEOUT"             // Hết thời gian chờ endorse/commit
+	ErrCommitFailed ErrorCode = "COMMIT_FAILED"       // Giao dịch bị từ chối khi commit (VD: MVCC_READ_CONFLICT)
+	ErrUnknown      ErrorCode = "UNKNOWN"             // Lỗi không mang mã của hợp đồng
+)
+
+// Error là lỗi có cấu trúc {"code", "message", "details"} do chaincode trả về,
+// kèm mã giao dịch nếu gateway cung cấp
+type Error struct {
+	Code          ErrorCode              `json:"code"`
+	Message       string                 `json:"message"`
+	Details       map[string]interface{} `json:"details,omitempty"`
+	TransactionID string                 `json:"transactionID,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// AsError trả về *Error trong chuỗi lỗi err (nếu có)
+func AsError(err error) (*Error, bool) {
+	var contractErr *Error
+	if errors.As(err, &contractErr) {
+		return contractErr, true
+	}
+	return nil, false
+}
+
+// CodeOf trả về mã lỗi của err, ErrUnknown nếu err không phải lỗi của hợp đồng
+func CodeOf(err error) ErrorCode {
+	if contractErr, ok := AsError(err); ok {
+		return contractErr.Code
+	}
+	return ErrUnknown
+}
+
+// ParseMessage tìm lỗi JSON của chaincode trong thông báo của peer, VD:
+// "chaincode response 500, {"code":"INVALID_STATE","message":"..."}"
+func ParseMessage(message string) (*Error, bool) {
+	for start := strings.Index(message, "{"); start >= 0; {
+		var contractErr Error
+		decoder := json.NewDecoder(strings.NewReader(message[start:]))
+		if err := decoder.Decode(&contractErr); err == nil && contractErr.Code != "" {
+			return &contractErr, true
+		}
+		next := strings.Index(message[start+1:], "{")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return nil, false
+}
@@ -0,0 +1,86 @@
+// my-ecommerce-client/contract/fake.go
+
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Handler xử lý một giao dịch trong Fake
+type Handler func(call Call) ([]byte, error)
+
+// Fake là gateway giả chạy trong bộ nhớ để kiểm thử REST / CLI mà không cần mạng Fabric.
+// Mỗi giao dịch được xử lý bởi Handler đăng ký qua On; giao dịch chưa đăng ký trả về lỗi.
+type Fake struct {
+	mu       sync.Mutex
+	handlers map[string]Handler
+	calls    []FakeCall
+}
+
+// FakeCall ghi lại một lần gọi Fake
+type FakeCall struct {
+	Kind Kind
+	Call Call
+}
+
+// NewFake tạo Fake rỗng
+func NewFake() *Fake {
+	return &Fake{handlers: make(map[string]Handler)}
+}
+
+// On đăng ký handler cho giao dịch name
+func (f *Fake) On(name string, handler Handler) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[name] = handler
+	return f
+}
+
+// Return đăng ký kết quả cố định (được mã hóa JSON) cho giao dịch name
+func (f *Fake) Return(name string, result interface{}) *Fake {
+	payload, err := json.Marshal(result)
+	return f.On(name, func(Call) ([]byte, error) {
+		return payload, err
+	})
+}
+
+// Fail đăng ký lỗi của hợp đồng cho giao dịch name
+func (f *Fake) Fail(name string, code ErrorCode, message string) *Fake {
+	return f.On(name, func(Call) ([]byte, error) {
+		return nil, &Error{Code: code, Message: message}
+	})
+}
+
+// Calls trả về các lần gọi đã nhận, theo thứ tự
+func (f *Fake) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// Submit thực hiện Contract
+func (f *Fake) Submit(ctx context.Context, call Call) ([]byte, error) {
+	return f.invoke(ctx, Submit, call)
+}
+
+// Evaluate thực hiện Contract
+func (f *Fake) Evaluate(ctx context.Context, call Call) ([]byte, error) {
+	return f.invoke(ctx, Evaluate, call)
+}
+
+func (f *Fake) invoke(ctx context.Context, kind Kind, call Call) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &Error{Code: ErrTimeout, Message: err.Error()}
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, FakeCall{Kind: kind, Call: call})
+	handler, ok := f.handlers[call.Name]
+	f.mu.Unlock()
+	if !ok {
+		return nil, &Error{Code: ErrUnknown, Message: fmt.Sprintf("fake: giao dịch %s chưa được đăng ký", call.Name)}
+	}
+	return handler(call)
+}
@@ -0,0 +1,93 @@
+// my-ecommerce-client/contract/metadata.go
+
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// SmartContractName là tên hợp đồng trong metadata của chaincode
+const SmartContractName = "SmartContract"
+
+// Metadata là metadata do contractapi sinh ra (org.hyperledger.fabric:GetMetadata)
+type Metadata struct {
+	Info       MetadataInfo                `json:"info"`
+	Contracts  map[string]ContractMetadata `json:"contracts"`
+	Components struct {
+		Schemas map[string]json.RawMessage `json:"schemas"`
+	} `json:"components"`
+}
+
+// MetadataInfo là thông tin chung của chaincode / hợp đồng
+type MetadataInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// ContractMetadata mô tả một hợp đồng trong chaincode
+type ContractMetadata struct {
+	Info         MetadataInfo          `json:"info"`
+	Name         string                `json:"name"`
+	Transactions []TransactionMetadata `json:"transactions"`
+}
+
+// TransactionMetadata mô tả một giao dịch. Tên tham số do contractapi đặt (param0, param1...),
+// vì vậy tên trường lấy từ danh mục Transactions, còn kiểu lấy từ schema ở đây.
+type TransactionMetadata struct {
+	Name       string              `json:"name"`
+	Tag        []string            `json:"tag"`
+	Parameters []ParameterMetadata `json:"parameters"`
+	Returns    json.RawMessage     `json:"returns,omitempty"`
+}
+
+// ParameterMetadata mô tả một tham số giao dịch
+type ParameterMetadata struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// FetchMetadata đọc metadata của chaincode đang chạy
+func FetchMetadata(ctx context.Context, c Contract) (*Metadata, error) {
+	result, err := c.Evaluate(ctx, Call{Name: MetadataTransaction})
+	if err != nil {
+		return nil, err
+	}
+	return ParseMetadata(result)
+}
+
+// ParseMetadata giải mã metadata JSON
+func ParseMetadata(data []byte) (*Metadata, error) {
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("metadata không hợp lệ: %w", err)
+	}
+	if _, ok := metadata.Contracts[SmartContractName]; !ok {
+		return nil, fmt.Errorf("metadata không có hợp đồng %s", SmartContractName)
+	}
+	return &metadata, nil
+}
+
+// Transaction tìm metadata của một giao dịch trong SmartContract
+func (m *Metadata) Transaction(name string) (*TransactionMetadata, bool) {
+	contract := m.Contracts[SmartContractName]
+	for i := range contract.Transactions {
+		if contract.Transactions[i].Name == name {
+			return &contract.Transactions[i], true
+		}
+	}
+	return nil, false
+}
+
+// Uncatalogued trả về các giao dịch có trong metadata nhưng chưa có trong danh mục
+// Transactions (chaincode mới hơn client)
+func (m *Metadata) Uncatalogued() []string {
+	var missing []string
+	for _, tx := range m.Contracts[SmartContractName].Transactions {
+		if _, ok := Lookup(tx.Name); !ok {
+			missing = append(missing, tx.Name)
+		}
+	}
+	return missing
+}
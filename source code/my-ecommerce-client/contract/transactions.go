@@ -0,0 +1,284 @@
+// my-ecommerce-client/contract/transactions.go
+
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Kind cho biết giao dịch ghi sổ cái (Submit) hay chỉ truy vấn (Evaluate).
+// Metadata của contractapi gắn tag "submit" cho mọi hàm nên phải khai báo ở đây.
+type Kind string
+
+const (
+	Submit   Kind = "submit"
+	Evaluate Kind = "evaluate"
+)
+
+// ParamType là kiểu của tham số khi nhận từ JSON / dòng lệnh
+type ParamType string
+
+const (
+	String  ParamType = "string"
+	Integer ParamType = "integer"
+	Boolean ParamType = "boolean"
+	JSON    ParamType = "json" // Đối tượng / mảng JSON, gửi cho chaincode dưới dạng chuỗi
+)
+
+// Param là một tham số theo vị trí của giao dịch
+type Param struct {
+	Name     string // Tên trường trong REST / CLI (tham số JSON bỏ hậu tố "JSON": linesJSON -> lines)
+	Type     ParamType
+	Optional bool // Được phép rỗng / bỏ qua
+}
+
+// Transaction mô tả một hàm của SmartContract
+type Transaction struct {
+	Name    string
+	Kind    Kind
+	Summary string
+	Params  []Param
+}
+
+var (
+	orderIDParam   = Param{Name: "orderID", Type: String}
+	returnIDParam  = Param{Name: "returnID", Type: String}
+	verifyingParam = Param{Name: "verificationCompanyID", Type: String}
+	scopeParam     = Param{Name: "scope", Type: String}
+	targetParam    = Param{Name: "target", Type: String, Optional: true}
+	reasonParam    = Param{Name: "reason", Type: String}
+	limitParam     = Param{Name: "limit", Type: Integer, Optional: true}
+)
+
+// Transactions là danh mục mọi giao dịch của SmartContract, theo đúng thứ tự tham số
+var Transactions = []*Transaction{
+	// --- Vòng đời đơn hàng ---
+	{Name: "CreateOrder", Kind: Submit, Summary: "Seller tạo đơn hàng", Params: []Param{
+		orderIDParam,
+		{Name: "paymentMethod", Type: String},
+		{Name: "shipperCompanyID", Type: String},
+		{Name: "sellerDataBlob", Type: String, Optional: true},
+		{Name: "shipperDataBlob", Type: String, Optional: true},
+		{Name: "sellerCompanyID", Type: String, Optional: true},
+		{Name: "lines", Type: JSON},
+	}},
+	{Name: "ConfirmPayment", Kind: Submit, Summary: "Sàn xác nhận đơn PREPAID đã thanh toán", Params: []Param{orderIDParam}},
+	{Name: "CancelOrder", Kind: Submit, Summary: "Hủy đơn chưa giao cho vận chuyển", Params: []Param{orderIDParam}},
+	{Name: "ShipOrder", Kind: Submit, Summary: "Shipper nhận hàng đi giao", Params: []Param{orderIDParam, verifyingParam}},
+	{Name: "ConfirmDelivery", Kind: Submit, Summary: "Shipper xác nhận giao đơn PREPAID", Params: []Param{orderIDParam, verifyingParam}},
+	{Name: "ConfirmCODDelivery", Kind: Submit, Summary: "Shipper xác nhận giao và thu tiền đơn COD", Params: []Param{orderIDParam, verifyingParam}},
+	{Name: "RemitCOD", Kind: Submit, Summary: "Shipper nộp tiền COD cho Sàn", Params: []Param{orderIDParam}},
+	{Name: "PayoutToSeller", Kind: Submit, Summary: "Sàn thanh toán cho Seller", Params: []Param{orderIDParam}},
+	{Name: "AddTrackingEvent", Kind: Submit, Summary: "Shipper ghi mốc hành trình", Params: []Param{
+		orderIDParam,
+		{Name: "checkpointCode", Type: String},
+		{Name: "location", Type: String},
+		{Name: "timestamp", Type: String},
+	}},
+	{Name: "ExpireUnpaidOrders", Kind: Submit, Summary: "Hủy các đơn PREPAID quá hạn thanh toán", Params: []Param{
+		{Name: "olderThan", Type: String, Optional: true},
+		limitParam,
+	}},
+
+	// --- Trả hàng ---
+	{Name: "RequestReturn", Kind: Submit, Summary: "Yêu cầu trả hàng (toàn phần hoặc một phần)", Params: []Param{
+		orderIDParam,
+		returnIDParam,
+		{Name: "returnLines", Type: JSON, Optional: true},
+	}},
+	{Name: "ShipReturn", Kind: Submit, Summary: "Shipper nhận hàng hoàn", Params: []Param{orderIDParam, returnIDParam, verifyingParam}},
+	{Name: "ConfirmReturnReceived", Kind: Submit, Summary: "Seller nhận lại hàng hoàn", Params: []Param{orderIDParam, returnIDParam, verifyingParam}},
+	{Name: "InspectReturn", Kind: Submit, Summary: "Seller kiểm hàng hoàn", Params: []Param{
+		orderIDParam, returnIDParam, verifyingParam,
+		{Name: "accepted", Type: Boolean},
+	}},
+	{Name: "RefundReturn", Kind: Submit, Summary: "Sàn hoàn tiền cho người mua", Params: []Param{orderIDParam, returnIDParam}},
+
+	// --- Duyệt hai người ---
+	{Name: "ApproveAction", Kind: Submit, Summary: "Người thứ hai duyệt thanh toán / hoàn tiền lớn", Params: []Param{
+		orderIDParam,
+		{Name: "approvalID", Type: String},
+	}},
+	{Name: "GetApprovals", Kind: Evaluate, Summary: "Danh sách đề xuất duyệt của đơn", Params: []Param{orderIDParam}},
+
+	// --- Truy vấn ---
+	{Name: "QueryOrder", Kind: Evaluate, Summary: "Đọc đơn hàng", Params: []Param{orderIDParam}},
+	{Name: "QueryOrderForOrg", Kind: Evaluate, Summary: "Đọc đơn hàng và kiểm tra tổ chức / công ty", Params: []Param{
+		orderIDParam,
+		{Name: "requiredMSP", Type: String},
+		{Name: "requiredCompanyID", Type: String},
+	}},
+	{Name: "QueryOrdersByString", Kind: Evaluate, Summary: "Truy vấn CouchDB (rich query)", Params: []Param{
+		{Name: "queryString", Type: JSON},
+	}},
+	{Name: "QueryReturnCase", Kind: Evaluate, Summary: "Đọc yêu cầu trả hàng", Params: []Param{orderIDParam, returnIDParam}},
+	{Name: "QueryReturnCases", Kind: Evaluate, Summary: "Các yêu cầu trả hàng của đơn", Params: []Param{orderIDParam}},
+	{Name: "GetTracking", Kind: Evaluate, Summary: "Hành trình vận chuyển của đơn", Params: []Param{orderIDParam}},
+	{Name: "GetOrderEndorsers", Kind: Evaluate, Summary: "Tổ chức phải endorse giao dịch ghi lên đơn", Params: []Param{orderIDParam}},
+	{Name: "GetClientRequest", Kind: Evaluate, Summary: "Kết quả của một request ID", Params: []Param{
+		{Name: "requestID", Type: String},
+	}},
+	{Name: "GetContractInfo", Kind: Evaluate, Summary: "Phiên bản, cấu hình, đóng băng và số đơn theo trạng thái"},
+
+	// --- Quản trị ---
+	{Name: "InitLedger", Kind: Submit, Summary: "Khởi tạo cấu hình hợp đồng", Params: []Param{
+		{Name: "bootstrap", Type: JSON, Optional: true},
+		{Name: "overwrite", Type: Boolean, Optional: true},
+	}},
+	{Name: "GetPolicyConfig", Kind: Evaluate, Summary: "Cấu hình chính sách hiện hành"},
+	{Name: "SetPolicyConfig", Kind: Submit, Summary: "Cập nhật cấu hình chính sách", Params: []Param{
+		{Name: "policy", Type: JSON},
+	}},
+	{Name: "GetRoleRegistry", Kind: Evaluate, Summary: "Sổ đăng ký vai trò tổ chức và công ty"},
+	{Name: "SetRoleRegistry", Kind: Submit, Summary: "Cập nhật sổ đăng ký vai trò", Params: []Param{
+		{Name: "registry", Type: JSON},
+	}},
+	{Name: "Freeze", Kind: Submit, Summary: "Đóng băng đơn / công ty / toàn bộ hợp đồng", Params: []Param{scopeParam, targetParam, reasonParam}},
+	{Name: "Unfreeze", Kind: Submit, Summary: "Gỡ đóng băng", Params: []Param{scopeParam, targetParam, reasonParam}},
+	{Name: "GetFreezes", Kind: Evaluate, Summary: "Các đóng băng đang hiệu lực"},
+	{Name: "GetFreezeLog", Kind: Evaluate, Summary: "Nhật ký đóng băng", Params: []Param{
+		{Name: "scope", Type: String, Optional: true},
+		targetParam,
+	}},
+	{Name: "MigrateOrders", Kind: Submit, Summary: "Nâng lược đồ đơn hàng theo lô", Params: []Param{
+		{Name: "fromKey", Type: String, Optional: true},
+		limitParam,
+	}},
+	{Name: "ImportOrders", Kind: Submit, Summary: "Nạp một lô đơn lịch sử", Params: []Param{
+		{Name: "orders", Type: JSON},
+	}},
+	{Name: "RebuildOrderCounters", Kind: Submit, Summary: "Đếm lại số đơn theo trạng thái theo lô", Params: []Param{
+		{Name: "fromKey", Type: String, Optional: true},
+		limitParam,
+	}},
+}
+
+var transactionsByName = func() map[string]*Transaction {
+	byName := make(map[string]*Transaction, len(Transactions))
+	for _, tx := range Transactions {
+		byName[tx.Name] = tx
+	}
+	return byName
+}()
+
+// Lookup tìm giao dịch theo tên
+func Lookup(name string) (*Transaction, bool) {
+	tx, ok := transactionsByName[name]
+	return tx, ok
+}
+
+// Param trả về tham số theo tên
+func (tx *Transaction) Param(name string) (Param, bool) {
+	for _, param := range tx.Params {
+		if param.Name == name {
+			return param, true
+		}
+	}
+	return Param{}, false
+}
+
+// Args chuyển giá trị theo tên (giải mã từ JSON hoặc chuỗi từ URL / dòng lệnh) thành
+// tham số theo vị trí. Trường thiếu nhận giá trị rỗng (0 / false với số và boolean)
+// để chaincode tự kiểm tra.
+func (tx *Transaction) Args(values map[string]interface{}) ([]string, error) {
+	var unknown []string
+	for name := range values {
+		if _, ok := tx.Param(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, invalidArgument(unknown[0], "không thuộc giao dịch "+tx.Name)
+	}
+
+	args := make([]string, len(tx.Params))
+	for i, param := range tx.Params {
+		arg := ""
+		if value, ok := values[param.Name]; ok && value != nil {
+			formatted, err := param.format(value)
+			if err != nil {
+				return nil, err
+			}
+			arg = formatted
+		}
+		args[i] = param.orZero(arg)
+	}
+	return args, nil
+}
+
+// orZero thay chuỗi rỗng bằng giá trị 0 của kiểu số / boolean (contractapi không nhận "")
+func (p Param) orZero(arg string) string {
+	if arg != "" {
+		return arg
+	}
+	switch p.Type {
+	case Integer:
+		return "0"
+	case Boolean:
+		return "false"
+	}
+	return arg
+}
+
+// format chuyển một giá trị thành chuỗi tham số chaincode theo kiểu của tham số
+func (p Param) format(value interface{}) (string, error) {
+	switch p.Type {
+	case Integer:
+		switch v := value.(type) {
+		case float64:
+			if v != float64(int64(v)) {
+				return "", invalidArgument(p.Name, "phải là số nguyên")
+			}
+			return strconv.FormatInt(int64(v), 10), nil
+		case json.Number:
+			if _, err := v.Int64(); err != nil {
+				return "", invalidArgument(p.Name, "phải là số nguyên")
+			}
+			return v.String(), nil
+		case string:
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil && v != "" {
+				return "", invalidArgument(p.Name, "phải là số nguyên")
+			}
+			return v, nil
+		}
+		return "", invalidArgument(p.Name, "phải là số nguyên")
+	case Boolean:
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			if _, err := strconv.ParseBool(v); err != nil && v != "" {
+				return "", invalidArgument(p.Name, "phải là true/false")
+			}
+			return v, nil
+		}
+		return "", invalidArgument(p.Name, "phải là true/false")
+	case JSON:
+		// Chuỗi được coi là JSON đã mã hóa sẵn
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", invalidArgument(p.Name, err.Error())
+		}
+		return string(encoded), nil
+	default:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		return "", invalidArgument(p.Name, "phải là chuỗi")
+	}
+}
+
+func invalidArgument(field string, constraint string) *Error {
+	return &Error{
+		Code:    ErrInvalidArgument,
+		Message: fmt.Sprintf("tham số '%s' không hợp lệ: %s", field, constraint),
+		Details: map[string]interface{}{"field": field, "constraint": constraint},
+	}
+}
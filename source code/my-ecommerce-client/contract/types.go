@@ -0,0 +1,212 @@
+// my-ecommerce-client/contract/types.go
+
+package contract
+
+import "time"
+
+// Kiểu dữ liệu trả về từ chaincode (my-ecommerce-chaincode/model.go, enums.go)
+
+// Status là trạng thái của đơn hàng / yêu cầu trả hàng
+type Status string
+
+const (
+	StatusCreated         Status = "CREATED"
+	StatusPaid            Status = "PAID"
+	StatusShipped         Status = "SHIPPED"
+	StatusDelivered       Status = "DELIVERED"
+	StatusSettled         Status = "SETTLED"
+	StatusCancelled       Status = "CANCELLED"
+	StatusExpired         Status = "EXPIRED"
+	StatusReturnRequested Status = "RETURN_REQUESTED"
+	StatusReturnInTransit Status = "RETURN_IN_TRANSIT"
+	StatusReturned        Status = "RETURNED"
+	StatusReturnReceived  Status = "RETURN_RECEIVED"
+	StatusReturnInspected Status = "RETURN_INSPECTED"
+	StatusRefunded        Status = "REFUNDED"
+)
+
+// OrderStatuses là các trạng thái của đơn hàng
+var OrderStatuses = []Status{
+	StatusCreated, StatusPaid, StatusShipped, StatusDelivered, StatusSettled,
+	StatusCancelled, StatusExpired, StatusReturnRequested, StatusReturnInTransit, StatusReturned,
+}
+
+// Phương thức thanh toán
+const (
+	PaymentCOD     = "COD"
+	PaymentPrepaid = "PREPAID"
+)
+
+// Order là đơn hàng trên sổ cái
+type Order struct {
+	DocType              string         `json:"docType"`
+	SchemaVersion        int            `json:"schemaVersion"`
+	OrderID              string         `json:"orderID"`
+	Status               Status         `json:"status"`
+	PaymentMethod        string         `json:"paymentMethod"`
+	CodStatus            string         `json:"codStatus"`
+	SellerID             string         `json:"sellerID"`
+	ShipperID            string         `json:"shipperID"`
+	SellerCompanyID      string         `json:"sellerCompanyID"`
+	ShipperCompanyID     string         `json:"shipperCompanyID"`
+	CreatedAt            time.Time      `json:"createdAt"`
+	UpdatedAt            time.Time      `json:"updatedAt"`
+	DeliveryTimestamp    time.Time      `json:"deliveryTimestamp"`
+	SellerSensitiveData  string         `json:"seller_sensitive_data,omitempty"`
+	ShipperSensitiveData string         `json:"shipper_sensitive_data,omitempty"`
+	Lines                []OrderLine    `json:"lines,omitempty"`
+	TotalAmount          int64          `json:"totalAmount"`
+	RefundableAmount     int64          `json:"refundableAmount"`
+	PayoutAmount         int64          `json:"payoutAmount"`
+	PlatformFee          int64          `json:"platformFee"`
+	RefundedAmount       int64          `json:"refundedAmount"`
+	ReturnIDs            []string       `json:"returnIDs,omitempty"`
+	Imported             bool           `json:"imported,omitempty"`
+	History              []HistoryEntry `json:"history"`
+}
+
+// OrderLine là một dòng sản phẩm của đơn
+type OrderLine struct {
+	LineID           string `json:"lineID"`
+	SKU              string `json:"sku"`
+	Quantity         int    `json:"quantity"`
+	UnitPrice        int64  `json:"unitPrice"`
+	ReturnedQuantity int    `json:"returnedQuantity"`
+}
+
+// ReturnCase là một yêu cầu trả hàng
+type ReturnCase struct {
+	DocType      string         `json:"docType"`
+	ReturnID     string         `json:"returnID"`
+	OrderID      string         `json:"orderID"`
+	Status       Status         `json:"status"`
+	FullReturn   bool           `json:"fullReturn"`
+	Lines        []ReturnLine   `json:"lines,omitempty"`
+	RefundAmount int64          `json:"refundAmount"`
+	Accepted     bool           `json:"accepted"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	History      []HistoryEntry `json:"history"`
+}
+
+// ReturnLine là một dòng hàng được trả
+type ReturnLine struct {
+	LineID   string `json:"lineID"`
+	Quantity int    `json:"quantity"`
+	Amount   int64  `json:"amount,omitempty"`
+}
+
+// HistoryEntry là một dòng lịch sử của đơn / yêu cầu trả hàng
+type HistoryEntry struct {
+	TxID      string    `json:"txID"`
+	Timestamp time.Time `json:"timestamp"`
+	Action    string    `json:"action"`
+	ActorOrg  string    `json:"actorOrg"`
+}
+
+// TrackingEvent là một mốc hành trình vận chuyển
+type TrackingEvent struct {
+	OrderID          string    `json:"orderID"`
+	Sequence         int       `json:"sequence"`
+	CheckpointCode   string    `json:"checkpointCode"`
+	Location         string    `json:"location"`
+	Timestamp        time.Time `json:"timestamp"`
+	RecordedAt       time.Time `json:"recordedAt"`
+	TxID             string    `json:"txID"`
+	ActorOrg         string    `json:"actorOrg"`
+	ShipperCompanyID string    `json:"shipperCompanyID"`
+}
+
+// RoleRegistry là sổ đăng ký vai trò: MSP ID -> PLATFORM | SELLER | SHIPPER, mã công ty -> MSP ID
+type RoleRegistry struct {
+	Orgs      map[string]string `json:"orgs"`
+	Companies map[string]string `json:"companies,omitempty"`
+}
+
+// ImportOrder là một đơn lịch sử gửi cho ImportOrders (my-ecommerce-chaincode/import.go)
+type ImportOrder struct {
+	OrderID          string         `json:"orderID"`
+	PaymentMethod    string         `json:"paymentMethod"`
+	SellerID         string         `json:"sellerID"`
+	SellerCompanyID  string         `json:"sellerCompanyID,omitempty"`
+	ShipperCompanyID string         `json:"shipperCompanyID"`
+	SellerDataBlob   string         `json:"sellerDataBlob,omitempty"`
+	ShipperDataBlob  string         `json:"shipperDataBlob,omitempty"`
+	Lines            []OrderLine    `json:"lines"`
+	Statuses         []ImportStatus `json:"statuses"`
+	CodRemittedAt    *time.Time     `json:"codRemittedAt,omitempty"`
+	PayoutAmount     *int64         `json:"payoutAmount,omitempty"`
+}
+
+// ImportStatus là một trạng thái mà đơn lịch sử đã qua
+type ImportStatus struct {
+	Status Status    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// ImportResult là kết quả của một lô ImportOrders
+type ImportResult struct {
+	Imported []string        `json:"imported"`
+	Failed   []ImportFailure `json:"failed,omitempty"`
+}
+
+// ImportFailure là một đơn của lô không được nạp
+type ImportFailure struct {
+	Index   int       `json:"index"`
+	OrderID string    `json:"orderID"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// Trạng thái của một đề xuất duyệt hai người (my-ecommerce-chaincode/approvals.go)
+const (
+	ApprovalPending  = "PENDING"
+	ApprovalExecuted = "EXECUTED"
+	ApprovalExpired  = "EXPIRED"
+)
+
+// SettlementResult là kết quả của PayoutToSeller / RefundReturn: Status EXECUTED khi đã thực hiện,
+// PENDING khi số tiền vượt ngưỡng và chỉ đề xuất ApprovalID được tạo (chờ ApproveAction)
+type SettlementResult struct {
+	Action     string `json:"action"`
+	OrderID    string `json:"orderID"`
+	ReturnID   string `json:"returnID,omitempty"`
+	Amount     int64  `json:"amount"`
+	Status     string `json:"status"`
+	ApprovalID string `json:"approvalID,omitempty"`
+}
+
+// QueryResult là một kết quả của QueryOrdersByString
+type QueryResult struct {
+	Key    string `json:"Key"`
+	Record *Order `json:"Record"`
+}
+
+// OrderEventName là tên sự kiện chaincode phát ra khi đơn thay đổi (my-ecommerce-chaincode/events.go)
+const OrderEventName = "OrderChanged"
+
+// OrderEvent là payload của sự kiện OrderChanged; giao dịch theo lô mang nhiều đơn
+type OrderEvent struct {
+	Version   int               `json:"version"`
+	TxID      string            `json:"txID"`
+	Action    string            `json:"action"`
+	ActorOrg  string            `json:"actorOrg"`
+	Timestamp time.Time         `json:"timestamp"`
+	Orders    []OrderEventEntry `json:"orders"`
+	Tracking  *TrackingEvent    `json:"tracking,omitempty"`
+}
+
+// OrderEventEntry là trạng thái của một đơn sau giao dịch (không có dữ liệu riêng, dòng hàng)
+type OrderEventEntry struct {
+	OrderID          string   `json:"orderID"`
+	PreviousStatus   Status   `json:"previousStatus,omitempty"`
+	Status           Status   `json:"status"`
+	PaymentMethod    string   `json:"paymentMethod"`
+	CodStatus        string   `json:"codStatus"`
+	SellerCompanyID  string   `json:"sellerCompanyID"`
+	ShipperCompanyID string   `json:"shipperCompanyID"`
+	TotalAmount      int64    `json:"totalAmount"`
+	RefundedAmount   int64    `json:"refundedAmount"`
+	PayoutAmount     int64    `json:"payoutAmount"`
+	ReturnIDs        []string `json:"returnIDs,omitempty"`
+}
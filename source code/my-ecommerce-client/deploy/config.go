@@ -0,0 +1,149 @@
+// my-ecommerce-client/deploy/config.go
+
+// Package deploy triển khai (hoặc nâng cấp) chaincode ecommerce qua vòng đời chaincode của
+// Fabric bằng peer CLI: đóng gói, cài đặt lên mọi peer, phê duyệt cho từng tổ chức, commit
+// và gọi InitLedger. Sequence được tính từ định nghĩa đã commit trên kênh và nhãn gói gồm
+// git commit của mã nguồn, nên chạy lại cùng một mã nguồn không tạo định nghĩa mới.
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config mô tả kênh, chaincode và các peer / orderer dùng để triển khai. Đường dẫn tương đối
+// được tính từ thư mục chứa file cấu hình.
+type Config struct {
+	Channel         string      `yaml:"channel"`
+	Chaincode       string      `yaml:"chaincode"`
+	Version         string      `yaml:"version"`         // Phiên bản định nghĩa chaincode, cũng là contractVersion của GetContractInfo
+	Path            string      `yaml:"path"`            // Thư mục mã nguồn chaincode (có go.mod)
+	InitRequired    bool        `yaml:"initRequired"`    // --init-required; bắt buộc gọi Init sau commit
+	SignaturePolicy string      `yaml:"signaturePolicy"` // Chính sách endorse, rỗng = mặc định của kênh
+	PeerBinary      string      `yaml:"peerBinary"`      // Mặc định "peer" trong PATH
+	FabricCfgPath   string      `yaml:"fabricCfgPath"`   // Thư mục chứa core.yaml (FABRIC_CFG_PATH), rỗng = giữ biến môi trường
+	ClientAuth      bool        `yaml:"clientAuth"`      // Mạng bật TLS hai chiều: gửi chứng chỉ TLS client của peer
+	Init            *InitConfig `yaml:"init"`            // nil = không gọi Init
+	Orderer         Orderer     `yaml:"orderer"`
+	Peers           []*Peer     `yaml:"peers"`
+}
+
+// InitConfig là giao dịch --isInit gọi sau khi commit
+type InitConfig struct {
+	Function      string `yaml:"function"`      // Mặc định InitLedger
+	BootstrapFile string `yaml:"bootstrapFile"` // Tham số bootstrapJSON (rỗng = cấu hình mặc định)
+	Overwrite     bool   `yaml:"overwrite"`     // Ghi đè cấu hình đã có trên sổ cái (CONFIG_EXISTS)
+}
+
+// Orderer là orderer nhận giao dịch approve / commit / init
+type Orderer struct {
+	Address      string `yaml:"address"`      // VD: localhost:7050
+	HostOverride string `yaml:"hostOverride"` // Tên trong chứng chỉ TLS, VD: orderer0.example.com
+	CAFile       string `yaml:"caFile"`       // CA TLS của orderer
+}
+
+// Peer là một peer cần cài gói, kèm định danh admin của tổ chức sở hữu peer. Peer đầu tiên
+// của mỗi tổ chức được dùng để phê duyệt và làm peer endorse khi commit / init.
+type Peer struct {
+	Name          string `yaml:"name"`
+	MSPID         string `yaml:"mspID"`
+	Address       string `yaml:"address"`       // VD: localhost:9051
+	HostOverride  string `yaml:"hostOverride"`  // Tên trong chứng chỉ TLS, VD: peer0.seller.com
+	TLSRootCert   string `yaml:"tlsRootCert"`   // CA TLS của peer
+	MSPConfigPath string `yaml:"mspConfigPath"` // Thư mục msp của admin tổ chức
+	ClientCert    string `yaml:"clientCert"`    // Chứng chỉ TLS client của admin (clientAuth)
+	ClientKey     string `yaml:"clientKey"`
+}
+
+// LoadConfig đọc file cấu hình, điền giá trị mặc định và kiểm tra các trường bắt buộc
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if config.PeerBinary == "" {
+		config.PeerBinary = "peer"
+	}
+	if config.Init != nil && config.Init.Function == "" {
+		config.Init.Function = "InitLedger"
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(p *string) {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	resolve(&config.Path)
+	resolve(&config.FabricCfgPath)
+	resolve(&config.Orderer.CAFile)
+	if config.Init != nil {
+		resolve(&config.Init.BootstrapFile)
+	}
+	for _, peer := range config.Peers {
+		resolve(&peer.TLSRootCert)
+		resolve(&peer.MSPConfigPath)
+		resolve(&peer.ClientCert)
+		resolve(&peer.ClientKey)
+	}
+	return &config, nil
+}
+
+func (c *Config) validate() error {
+	required := []struct{ field, value string }{
+		{"channel", c.Channel},
+		{"chaincode", c.Chaincode},
+		{"version", c.Version},
+		{"path", c.Path},
+		{"orderer.address", c.Orderer.Address},
+		{"orderer.caFile", c.Orderer.CAFile},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return fmt.Errorf("thiếu %s", r.field)
+		}
+	}
+	if c.Init != nil && !c.InitRequired {
+		return fmt.Errorf("init chỉ dùng được khi initRequired: true")
+	}
+	if len(c.Peers) == 0 {
+		return fmt.Errorf("thiếu peers")
+	}
+	names := make(map[string]bool)
+	for i, peer := range c.Peers {
+		if peer.Name == "" || peer.MSPID == "" || peer.Address == "" || peer.TLSRootCert == "" || peer.MSPConfigPath == "" {
+			return fmt.Errorf("peers[%d] cần name, mspID, address, tlsRootCert và mspConfigPath", i)
+		}
+		if names[peer.Name] {
+			return fmt.Errorf("trùng tên peer %s", peer.Name)
+		}
+		names[peer.Name] = true
+		if c.ClientAuth && (peer.ClientCert == "" || peer.ClientKey == "") {
+			return fmt.Errorf("peer %s cần clientCert và clientKey khi clientAuth: true", peer.Name)
+		}
+	}
+	return nil
+}
+
+// Orgs trả về peer đầu tiên của mỗi tổ chức, theo thứ tự trong cấu hình
+func (c *Config) Orgs() []*Peer {
+	var orgs []*Peer
+	seen := make(map[string]bool)
+	for _, peer := range c.Peers {
+		if !seen[peer.MSPID] {
+			seen[peer.MSPID] = true
+			orgs = append(orgs, peer)
+		}
+	}
+	return orgs
+}
@@ -0,0 +1,482 @@
+// my-ecommerce-client/deploy/deploy.go
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// buildInfoFile được sinh trong thư mục chaincode lúc đóng gói (my-ecommerce-chaincode/info.go)
+const buildInfoFile = "buildinfo_generated.go"
+
+// Options điều chỉnh một lần triển khai
+type Options struct {
+	DryRun     bool      // Chỉ chạy các bước chỉ đọc (git, đóng gói tạm, truy vấn) và in các lệnh sẽ chạy
+	AllowDirty bool      // Cho phép mã nguồn có thay đổi chưa commit; nhãn gói có hậu tố -dirty
+	Force      bool      // Tạo sequence mới kể cả khi định nghĩa đã commit dùng cùng nhãn gói
+	Out        io.Writer // Tiến trình; nil = os.Stdout
+}
+
+// Definition là định nghĩa chaincode đã commit trên kênh
+type Definition struct {
+	Sequence  int64  `json:"sequence"`
+	Version   string `json:"version"`
+	PackageID string `json:"-"` // Theo phê duyệt của tổ chức đầu tiên; rỗng nếu không đọc được
+}
+
+// Plan là những gì một lần triển khai sẽ làm, tính từ mã nguồn và trạng thái của mạng
+type Plan struct {
+	Label     string
+	GitCommit string
+	PackageID string
+	Committed *Definition // nil = chaincode chưa có trên kênh
+	Sequence  int64       // Sequence sẽ commit
+	UpToDate  bool        // Định nghĩa đã commit dùng đúng nhãn gói này, không cần làm gì
+	InitOnly  bool        // Định nghĩa đã commit dùng đúng nhãn gói nhưng Init chưa chạy (lần trước lỗi ở Init)
+	Install   []*Peer     // Peer chưa cài gói
+	Approve   []*Peer     // Tổ chức chưa phê duyệt định nghĩa mới (peer đầu tiên của tổ chức)
+}
+
+// Deployer chạy vòng đời chaincode theo Config
+type Deployer struct {
+	config  *Config
+	options Options
+	cli     *peerCLI
+	out     io.Writer
+}
+
+// New tạo Deployer
+func New(config *Config, options Options) *Deployer {
+	out := options.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	return &Deployer{config: config, options: options, cli: &peerCLI{config: config}, out: out}
+}
+
+// Run đóng gói, cài đặt, phê duyệt, commit và gọi Init. Mỗi bước đã xong từ lần chạy trước
+// (gói đã cài, tổ chức đã phê duyệt, định nghĩa đã commit nhưng Init lỗi) được bỏ qua nên chạy
+// lại sau lỗi là an toàn. Với DryRun chỉ các bước chỉ đọc được chạy; Plan trả về cho biết các
+// bước còn lại.
+func (d *Deployer) Run(ctx context.Context) (*Plan, error) {
+	plan := &Plan{}
+	first := d.config.Peers[0]
+
+	// 1. Mã nguồn và nhãn gói
+	commit, dirty, err := sourceCommit(ctx, d.config.Path)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		if !d.options.AllowDirty {
+			return nil, fmt.Errorf("%s có thay đổi chưa commit (commit trước, hoặc dùng -allow-dirty)", d.config.Path)
+		}
+		commit += "-dirty"
+	}
+	plan.GitCommit = commit
+	plan.Label = fmt.Sprintf("%s_%s_%s", d.config.Chaincode, d.config.Version, commit)
+	d.step("Mã nguồn %s, nhãn gói %s", d.config.Path, plan.Label)
+
+	// 2. Đóng gói vào thư mục tạm và tính package ID
+	dir, err := os.MkdirTemp("", "ecom-deploy-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	packageFile := filepath.Join(dir, plan.Label+".tar.gz")
+	if err := d.packageChaincode(ctx, first, packageFile, commit, plan.Label); err != nil {
+		return nil, err
+	}
+	output, err := d.cli.run(ctx, first, false, "lifecycle", "chaincode", "calculatepackageid", packageFile)
+	if err != nil {
+		return nil, err
+	}
+	plan.PackageID = strings.TrimSpace(string(output))
+	d.detail("package ID %s", plan.PackageID)
+
+	// 3. Sequence kế tiếp từ định nghĩa đã commit
+	if plan.Committed, err = d.queryCommitted(ctx, first); err != nil {
+		return nil, err
+	}
+	if plan.Committed == nil {
+		plan.Sequence = 1
+		d.step("Kênh %s chưa có chaincode %s, sequence 1", d.config.Channel, d.config.Chaincode)
+	} else {
+		plan.Sequence = plan.Committed.Sequence + 1
+		d.step("Kênh %s: %s đang ở sequence %d, phiên bản %s, gói %s",
+			d.config.Channel, d.config.Chaincode, plan.Committed.Sequence, plan.Committed.Version, orUnknown(plan.Committed.PackageID))
+		if packageLabel(plan.Committed.PackageID) == plan.Label && plan.Committed.Version == d.config.Version {
+			if !d.options.Force {
+				// Lần trước commit xong nhưng Init lỗi: chỉ gọi lại Init cho sequence đã commit
+				if d.config.Init != nil {
+					initialized, err := d.initialized(ctx, first)
+					if err != nil {
+						return nil, err
+					}
+					if !initialized {
+						plan.InitOnly = true
+						plan.Sequence = plan.Committed.Sequence
+						d.step("Sequence %d đã commit nhưng chưa khởi tạo", plan.Sequence)
+						if err := d.initChaincode(ctx, plan); err != nil {
+							return nil, err
+						}
+						d.step("Hoàn tất: %s sequence %d, gói %s", d.config.Chaincode, plan.Sequence, plan.Label)
+						return plan, nil
+					}
+				}
+				plan.UpToDate = true
+				d.step("Đã triển khai gói %s, không có gì để làm (dùng -force để tạo sequence %d)", plan.Label, plan.Sequence)
+				return plan, nil
+			}
+		}
+		d.detail("sequence mới %d", plan.Sequence)
+	}
+
+	// 4. Cài đặt lên mọi peer chưa có gói
+	d.step("Cài đặt gói")
+	for _, peer := range d.config.Peers {
+		installed, err := d.installed(ctx, peer, plan.PackageID)
+		if err != nil {
+			return nil, err
+		}
+		if installed {
+			d.detail("%s: đã cài", peer.Name)
+			continue
+		}
+		plan.Install = append(plan.Install, peer)
+		d.detail("%s: cài đặt", peer.Name)
+		if err := d.execute(ctx, peer, false, "lifecycle", "chaincode", "install", packageFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Phê duyệt cho từng tổ chức chưa phê duyệt
+	d.step("Phê duyệt sequence %d", plan.Sequence)
+	for _, org := range d.config.Orgs() {
+		approvals, err := d.commitReadiness(ctx, org, plan.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		if approvals[org.MSPID] {
+			d.detail("%s: đã phê duyệt", org.MSPID)
+			continue
+		}
+		plan.Approve = append(plan.Approve, org)
+		d.detail("%s: phê duyệt qua %s", org.MSPID, org.Name)
+		args := append([]string{"lifecycle", "chaincode", "approveformyorg"}, d.ordererArgs(org)...)
+		args = append(args, d.definitionArgs(plan.Sequence)...)
+		args = append(args, "--package-id", plan.PackageID, "--waitForEvent")
+		if err := d.execute(ctx, org, false, args...); err != nil {
+			return nil, err
+		}
+	}
+	if d.options.DryRun {
+		if err := d.commitSteps(ctx, plan); err != nil {
+			return nil, err
+		}
+		return plan, nil
+	}
+
+	// 6. Mọi tổ chức trong cấu hình phải đã phê duyệt trước khi commit
+	d.step("Kiểm tra sẵn sàng commit")
+	approvals, err := d.commitReadiness(ctx, first, plan.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, org := range d.config.Orgs() {
+		if !approvals[org.MSPID] {
+			missing = append(missing, org.MSPID)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("sequence %d chưa được phê duyệt bởi: %s", plan.Sequence, strings.Join(missing, ", "))
+	}
+	var others []string
+	for msp, approved := range approvals {
+		if !approved {
+			others = append(others, msp)
+		}
+	}
+	if len(others) > 0 {
+		sort.Strings(others)
+		d.detail("chưa phê duyệt (ngoài cấu hình): %s", strings.Join(others, ", "))
+	}
+
+	if err := d.commitSteps(ctx, plan); err != nil {
+		return nil, err
+	}
+	d.step("Hoàn tất: %s sequence %d, gói %s", d.config.Chaincode, plan.Sequence, plan.Label)
+	return plan, nil
+}
+
+// commitSteps commit định nghĩa, xác nhận sequence trên kênh rồi gọi Init
+func (d *Deployer) commitSteps(ctx context.Context, plan *Plan) error {
+	first := d.config.Peers[0]
+
+	d.step("Commit sequence %d", plan.Sequence)
+	args := append([]string{"lifecycle", "chaincode", "commit"}, d.ordererArgs(first)...)
+	args = append(args, d.definitionArgs(plan.Sequence)...)
+	args = append(args, d.endorserArgs()...)
+	if err := d.execute(ctx, first, true, args...); err != nil {
+		return err
+	}
+	if !d.options.DryRun {
+		committed, err := d.queryCommitted(ctx, first)
+		if err != nil {
+			return err
+		}
+		if committed == nil || committed.Sequence != plan.Sequence {
+			return fmt.Errorf("commit xong nhưng kênh chưa ở sequence %d", plan.Sequence)
+		}
+	}
+	return d.initChaincode(ctx, plan)
+}
+
+// initChaincode gọi giao dịch --isInit cho sequence plan.Sequence (nếu cấu hình có init)
+func (d *Deployer) initChaincode(ctx context.Context, plan *Plan) error {
+	first := d.config.Peers[0]
+	init := d.config.Init
+	if init == nil {
+		return nil
+	}
+	bootstrap := ""
+	if init.BootstrapFile != "" {
+		data, err := os.ReadFile(init.BootstrapFile)
+		if err != nil {
+			return err
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return fmt.Errorf("%s: %w", init.BootstrapFile, err)
+		}
+		bootstrap = compact.String()
+	}
+	initArgs, err := json.Marshal(map[string][]string{
+		"Args": {init.Function, bootstrap, strconv.FormatBool(init.Overwrite)},
+	})
+	if err != nil {
+		return err
+	}
+
+	d.step("Gọi %s (--isInit)", init.Function)
+	args := append([]string{"chaincode", "invoke"}, d.ordererArgs(first)...)
+	args = append(args, "--channelID", d.config.Channel, "--name", d.config.Chaincode)
+	args = append(args, d.endorserArgs()...)
+	args = append(args, "--isInit", "--waitForEvent", "-c", string(initArgs))
+	if err := d.execute(ctx, first, true, args...); err != nil {
+		return fmt.Errorf("%s thất bại, định nghĩa sequence %d đã commit nhưng chaincode chưa khởi tạo (chạy lại để gọi lại %s): %w",
+			init.Function, plan.Sequence, init.Function, err)
+	}
+	return nil
+}
+
+// packageChaincode sinh buildinfo_generated.go (phiên bản + git commit cho GetContractInfo, vì
+// peer tự build gói nên không truyền được -ldflags), đóng gói rồi xóa file sinh ra
+func (d *Deployer) packageChaincode(ctx context.Context, peer *Peer, packageFile string, commit string, label string) error {
+	d.step("Đóng gói")
+	buildInfo := filepath.Join(d.config.Path, buildInfoFile)
+	source := fmt.Sprintf(`// Code generated by ecom-deploy. DO NOT EDIT.
+
+package main
+
+func init() {
+	contractVersion = %q
+	gitCommit = %q
+}
+`, d.config.Version, commit)
+	if err := os.WriteFile(buildInfo, []byte(source), 0o644); err != nil {
+		return err
+	}
+	defer os.Remove(buildInfo)
+	_, err := d.cli.run(ctx, peer, false, "lifecycle", "chaincode", "package", packageFile,
+		"--path", d.config.Path, "--lang", "golang", "--label", label)
+	return err
+}
+
+// queryCommitted đọc định nghĩa đã commit (nil nếu chưa có) và package ID mà tổ chức của
+// peer đã phê duyệt cho định nghĩa đó
+func (d *Deployer) queryCommitted(ctx context.Context, peer *Peer) (*Definition, error) {
+	output, err := d.cli.run(ctx, peer, false, "lifecycle", "chaincode", "querycommitted",
+		"--channelID", d.config.Channel, "--name", d.config.Chaincode, "--output", "json")
+	if err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && strings.Contains(cmdErr.Output, "is not defined") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var definition Definition
+	if err := json.Unmarshal(output, &definition); err != nil {
+		return nil, fmt.Errorf("querycommitted: %w", err)
+	}
+
+	output, err = d.cli.run(ctx, peer, false, "lifecycle", "chaincode", "queryapproved",
+		"--channelID", d.config.Channel, "--name", d.config.Chaincode,
+		"--sequence", strconv.FormatInt(definition.Sequence, 10), "--output", "json")
+	if err == nil {
+		var approved struct {
+			Source struct {
+				Type struct {
+					LocalPackage struct {
+						PackageID string `json:"package_id"`
+					} `json:"LocalPackage"`
+				} `json:"Type"`
+			} `json:"source"`
+		}
+		if json.Unmarshal(output, &approved) == nil {
+			definition.PackageID = approved.Source.Type.LocalPackage.PackageID
+		}
+	}
+	return &definition, nil
+}
+
+// initialized cho biết định nghĩa đã commit đã được gọi Init chưa. Với --init-required, peer
+// từ chối mọi proposal khác (kể cả truy vấn) cho tới khi giao dịch --isInit thành công.
+func (d *Deployer) initialized(ctx context.Context, peer *Peer) (bool, error) {
+	_, err := d.cli.run(ctx, peer, false, "chaincode", "query",
+		"--channelID", d.config.Channel, "--name", d.config.Chaincode, "-c", `{"Args":["GetContractInfo"]}`)
+	if err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && strings.Contains(cmdErr.Output, "has not been initialized") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// installed cho biết peer đã cài gói packageID chưa
+func (d *Deployer) installed(ctx context.Context, peer *Peer, packageID string) (bool, error) {
+	output, err := d.cli.run(ctx, peer, false, "lifecycle", "chaincode", "queryinstalled", "--output", "json")
+	if err != nil {
+		return false, err
+	}
+	var result struct {
+		InstalledChaincodes []struct {
+			PackageID string `json:"package_id"`
+		} `json:"installed_chaincodes"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return false, fmt.Errorf("queryinstalled (%s): %w", peer.Name, err)
+	}
+	for _, chaincode := range result.InstalledChaincodes {
+		if chaincode.PackageID == packageID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// commitReadiness trả về trạng thái phê duyệt của từng tổ chức trên kênh cho định nghĩa mới
+func (d *Deployer) commitReadiness(ctx context.Context, peer *Peer, sequence int64) (map[string]bool, error) {
+	args := append([]string{"lifecycle", "chaincode", "checkcommitreadiness"}, d.definitionArgs(sequence)...)
+	output, err := d.cli.run(ctx, peer, false, append(args, "--output", "json")...)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Approvals map[string]bool `json:"approvals"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("checkcommitreadiness: %w", err)
+	}
+	return result.Approvals, nil
+}
+
+// execute chạy một lệnh ghi lên mạng; với DryRun chỉ in lệnh
+func (d *Deployer) execute(ctx context.Context, peer *Peer, multiPeer bool, args ...string) error {
+	if d.options.DryRun {
+		command := append([]string{d.config.PeerBinary}, args...)
+		fmt.Fprintf(d.out, "      $ CORE_PEER_LOCALMSPID=%s CORE_PEER_ADDRESS=%s %s\n", peer.MSPID, peer.Address, shellQuote(command))
+		return nil
+	}
+	_, err := d.cli.run(ctx, peer, multiPeer, args...)
+	return err
+}
+
+// definitionArgs là các tham số mô tả định nghĩa chaincode, giống nhau ở approve / check / commit
+func (d *Deployer) definitionArgs(sequence int64) []string {
+	args := []string{
+		"--channelID", d.config.Channel,
+		"--name", d.config.Chaincode,
+		"--version", d.config.Version,
+		"--sequence", strconv.FormatInt(sequence, 10),
+	}
+	if d.config.InitRequired {
+		args = append(args, "--init-required")
+	}
+	if d.config.SignaturePolicy != "" {
+		args = append(args, "--signature-policy", d.config.SignaturePolicy)
+	}
+	return args
+}
+
+// ordererArgs là các tham số kết nối orderer; chứng chỉ TLS client là của admin tổ chức peer
+func (d *Deployer) ordererArgs(peer *Peer) []string {
+	orderer := d.config.Orderer
+	args := []string{"-o", orderer.Address, "--tls", "--cafile", orderer.CAFile}
+	if orderer.HostOverride != "" {
+		args = append(args, "--ordererTLSHostnameOverride", orderer.HostOverride)
+	}
+	if d.config.ClientAuth {
+		args = append(args, "--clientauth", "--certfile", peer.ClientCert, "--keyfile", peer.ClientKey)
+	}
+	return args
+}
+
+// endorserArgs chọn một peer của mỗi tổ chức cho commit / init
+func (d *Deployer) endorserArgs() []string {
+	var args []string
+	for _, org := range d.config.Orgs() {
+		args = append(args, "--peerAddresses", org.Address, "--tlsRootCertFiles", org.TLSRootCert)
+	}
+	return args
+}
+
+func (d *Deployer) step(format string, args ...interface{}) {
+	fmt.Fprintf(d.out, "==> "+format+"\n", args...)
+}
+
+func (d *Deployer) detail(format string, args ...interface{}) {
+	fmt.Fprintf(d.out, "    "+format+"\n", args...)
+}
+
+// sourceCommit trả về git commit (12 ký tự) của thư mục mã nguồn và việc thư mục có thay đổi
+// chưa commit hay không
+func sourceCommit(ctx context.Context, dir string) (string, bool, error) {
+	output, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--short=12", "HEAD").CombinedOutput()
+	if err != nil {
+		return "", false, fmt.Errorf("không đọc được git commit của %s: %v\n%s", dir, err, output)
+	}
+	commit := strings.TrimSpace(string(output))
+	status, err := exec.CommandContext(ctx, "git", "-C", dir, "status", "--porcelain", "--", ".").CombinedOutput()
+	if err != nil {
+		return "", false, fmt.Errorf("git status %s: %v\n%s", dir, err, status)
+	}
+	return commit, len(bytes.TrimSpace(status)) > 0, nil
+}
+
+// packageLabel là phần nhãn của package ID "<nhãn>:<hash>"
+func packageLabel(packageID string) string {
+	label, _, _ := strings.Cut(packageID, ":")
+	return label
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "?"
+	}
+	return s
+}
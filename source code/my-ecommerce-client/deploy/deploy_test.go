@@ -0,0 +1,148 @@
+// my-ecommerce-client/deploy/deploy_test.go
+
+package deploy
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakePeer giả lập peer CLI cho một kênh một tổ chức; trạng thái (định nghĩa đã commit, đã
+// Init chưa) nằm trong thư mục $FAKE_PEER_STATE. File init-fail làm giao dịch --isInit lỗi.
+const fakePeer = `#!/bin/sh
+state="$FAKE_PEER_STATE"
+echo "$*" >> "$state/calls"
+case "$1 $2" in
+"chaincode query")
+	if [ ! -f "$state/initialized" ]; then
+		echo "Error: endorsement failure during query. response: status:500 message:\"chaincode 'ecommerce' has not been initialized for this version, must call as init first\"" >&2
+		exit 1
+	fi
+	echo '{}'; exit 0 ;;
+"chaincode invoke")
+	if [ -f "$state/init-fail" ]; then echo "Error: InitLedger: CONFIG_EXISTS" >&2; exit 1; fi
+	touch "$state/initialized"; exit 0 ;;
+esac
+case "$3" in
+package) touch "$4" ;;
+calculatepackageid) basename "$4" .tar.gz | sed 's/$/:0123abcd/' | tee "$state/package" ;;
+querycommitted)
+	if [ ! -f "$state/committed" ]; then echo "Error: query failed: namespace ecommerce is not defined" >&2; exit 1; fi
+	cat "$state/committed" ;;
+queryapproved) printf '{"source":{"Type":{"LocalPackage":{"package_id":"%s"}}}}' "$(cat "$state/package")" ;;
+queryinstalled) echo '{"installed_chaincodes":[]}' ;;
+checkcommitreadiness) echo '{"approvals":{"Org1MSP":true}}' ;;
+commit) echo '{"sequence":1,"version":"1.0"}' > "$state/committed" ;;
+esac
+`
+
+// newTestDeployer tạo mã nguồn chaincode trong một git repo tạm và Deployer dùng fakePeer
+func newTestDeployer(t *testing.T) (*Deployer, string) {
+	t.Helper()
+	dir := t.TempDir()
+	source := filepath.Join(dir, "chaincode")
+	state := filepath.Join(dir, "state")
+	for _, d := range []string{source, state} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(source, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "chaincode"},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", source}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	peerBinary := filepath.Join(dir, "peer")
+	if err := os.WriteFile(peerBinary, []byte(fakePeer), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAKE_PEER_STATE", state)
+
+	config := &Config{
+		Channel:      "mychannel",
+		Chaincode:    "ecommerce",
+		Version:      "1.0",
+		Path:         source,
+		InitRequired: true,
+		PeerBinary:   peerBinary,
+		Init:         &InitConfig{Function: "InitLedger"},
+		Orderer:      Orderer{Address: "localhost:7050", CAFile: "orderer-ca.pem"},
+		Peers:        []*Peer{{Name: "peer0", MSPID: "Org1MSP", Address: "localhost:7051", TLSRootCert: "ca.pem", MSPConfigPath: "msp"}},
+	}
+	return New(config, Options{Out: io.Discard}), state
+}
+
+// calls trả về các lệnh peer đã chạy (bỏ cờ) và xóa nhật ký
+func calls(t *testing.T, state string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(state, "calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(state, "calls"))
+	var commands []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		commands = append(commands, commandName("peer", strings.Fields(line)))
+	}
+	return commands
+}
+
+func contains(commands []string, command string) bool {
+	for _, c := range commands {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRunRetriesInitAfterFailedInit(t *testing.T) {
+	deployer, state := newTestDeployer(t)
+	ctx := context.Background()
+
+	// Lần đầu: commit xong nhưng Init lỗi
+	if err := os.WriteFile(filepath.Join(state, "init-fail"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deployer.Run(ctx); err == nil || !strings.Contains(err.Error(), "chưa khởi tạo") {
+		t.Fatalf("lỗi = %v, muốn lỗi Init sau khi commit", err)
+	}
+	if !contains(calls(t, state), "peer lifecycle chaincode commit") {
+		t.Fatal("lần đầu không commit")
+	}
+
+	// Chạy lại: không commit sequence mới, chỉ gọi lại Init cho sequence 1
+	os.Remove(filepath.Join(state, "init-fail"))
+	plan, err := deployer.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := calls(t, state)
+	if !plan.InitOnly || plan.UpToDate || plan.Sequence != 1 {
+		t.Errorf("plan = InitOnly %v, UpToDate %v, sequence %d; muốn InitOnly ở sequence 1", plan.InitOnly, plan.UpToDate, plan.Sequence)
+	}
+	if contains(commands, "peer lifecycle chaincode commit") || !contains(commands, "peer chaincode invoke") {
+		t.Errorf("lệnh đã chạy %v, muốn chỉ gọi Init", commands)
+	}
+
+	// Đã khởi tạo: không còn gì để làm
+	plan, err = deployer.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commands := calls(t, state); !plan.UpToDate || plan.InitOnly || contains(commands, "peer chaincode invoke") {
+		t.Errorf("plan = UpToDate %v, InitOnly %v, lệnh %v; muốn UpToDate, không gọi Init", plan.UpToDate, plan.InitOnly, commands)
+	}
+}
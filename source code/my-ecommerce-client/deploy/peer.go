@@ -0,0 +1,125 @@
+// my-ecommerce-client/deploy/peer.go
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandError là lỗi của một lệnh peer CLI, kèm phần cuối output để biết lý do
+type CommandError struct {
+	Command string // VD: "peer lifecycle chaincode install"
+	Peer    string
+	Output  string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s (%s): %v\n%s", e.Command, e.Peer, e.Err, e.Output)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// peerCLI chạy peer CLI với định danh admin và địa chỉ của một peer trong cấu hình
+type peerCLI struct {
+	config *Config
+}
+
+// run chạy lệnh với môi trường của peer và trả về stdout. Với lệnh gửi tới nhiều peer
+// (--peerAddresses), multiPeer bỏ CORE_PEER_TLS_SERVERHOSTOVERRIDE để CLI không dùng tên
+// của peer này cho các peer khác.
+func (p *peerCLI) run(ctx context.Context, peer *Peer, multiPeer bool, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.config.PeerBinary, args...)
+	cmd.Env = p.env(peer, multiPeer)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		output := strings.TrimSpace(stderr.String() + "\n" + stdout.String())
+		return nil, &CommandError{Command: commandName(p.config.PeerBinary, args), Peer: peer.Name, Output: tail(output, 20), Err: err}
+	}
+	return stdout.Bytes(), nil
+}
+
+// env là môi trường của tiến trình hiện tại, thay mọi biến CORE_PEER_* bằng cấu hình của peer
+func (p *peerCLI) env(peer *Peer, multiPeer bool) []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "CORE_PEER_") {
+			continue
+		}
+		if p.config.FabricCfgPath != "" && strings.HasPrefix(kv, "FABRIC_CFG_PATH=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return append(env, peerEnv(p.config, peer, multiPeer)...)
+}
+
+// peerEnv là các biến môi trường chọn peer và định danh cho peer CLI
+func peerEnv(config *Config, peer *Peer, multiPeer bool) []string {
+	env := []string{
+		"CORE_PEER_TLS_ENABLED=true",
+		"CORE_PEER_LOCALMSPID=" + peer.MSPID,
+		"CORE_PEER_ADDRESS=" + peer.Address,
+		"CORE_PEER_TLS_ROOTCERT_FILE=" + peer.TLSRootCert,
+		"CORE_PEER_MSPCONFIGPATH=" + peer.MSPConfigPath,
+	}
+	if peer.HostOverride != "" && !multiPeer {
+		env = append(env, "CORE_PEER_TLS_SERVERHOSTOVERRIDE="+peer.HostOverride)
+	}
+	if config.ClientAuth {
+		env = append(env,
+			"CORE_PEER_TLS_CLIENTCERT_FILE="+peer.ClientCert,
+			"CORE_PEER_TLS_CLIENTKEY_FILE="+peer.ClientKey,
+		)
+	}
+	if config.FabricCfgPath != "" {
+		env = append(env, "FABRIC_CFG_PATH="+config.FabricCfgPath)
+	}
+	return env
+}
+
+// commandName là phần lệnh không có cờ, VD: "peer lifecycle chaincode install"
+func commandName(binary string, args []string) string {
+	name := []string{binary}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		name = append(name, arg)
+		if len(name) == 4 {
+			break
+		}
+	}
+	return strings.Join(name, " ")
+}
+
+// shellQuote in một lệnh sao cho chép được vào shell (dùng cho -dry-run)
+func shellQuote(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+") == "" {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// tail giữ n dòng cuối của output
+func tail(output string, n int) string {
+	lines := strings.Split(output, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,108 @@
+// my-ecommerce-client/events/blocks.go
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"ecommerce.com/client/contract"
+	"ecommerce.com/client/ledger"
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+)
+
+// blockTransactions là kết quả đọc một block
+type blockTransactions struct {
+	notifications []*Notification
+	positions     map[string]int // txID -> vị trí trong block, để bỏ qua phần đã xử lý
+	skipped       []error        // Giao dịch không đọc được (đã bỏ qua)
+}
+
+// parseBlock đọc các giao dịch hợp lệ của chaincode trong block. Giao dịch có sự kiện
+// OrderChanged dùng sự kiện đó; giao dịch của chaincode cũ (chưa phát sự kiện) được suy ra
+// từ các đơn hàng trong write-set.
+func parseBlock(block *common.Block, chaincode string) *blockTransactions {
+	parsed := ledger.ParseBlock(block)
+	result := &blockTransactions{positions: map[string]int{}, skipped: parsed.Skipped}
+	for _, tx := range parsed.Transactions {
+		result.positions[tx.TxID] = tx.Position
+		action := tx.Action(chaincode)
+		if !tx.Valid() || action == nil {
+			continue
+		}
+		notification, err := parseTransaction(tx, action)
+		if err != nil {
+			result.skipped = append(result.skipped, fmt.Errorf("block %d, giao dịch %s: %w", tx.BlockNumber, tx.TxID, err))
+			continue
+		}
+		if notification != nil {
+			notification.BlockNumber = tx.BlockNumber
+			result.notifications = append(result.notifications, notification)
+		}
+	}
+	return result
+}
+
+func parseTransaction(tx *ledger.Transaction, action *ledger.ChaincodeAction) (*Notification, error) {
+	if action.Event != nil && action.Event.GetEventName() == contract.OrderEventName {
+		event, err := decodeOrderEvent(action.Event.GetPayload())
+		if err != nil {
+			return nil, err
+		}
+		return &Notification{TransactionID: tx.TxID, Source: SourceBlock, Event: event}, nil
+	}
+	if event := eventFromWriteSet(tx, action); event != nil {
+		return &Notification{TransactionID: tx.TxID, Source: SourceWriteSet, Event: event}, nil
+	}
+	return nil, nil
+}
+
+// eventFromWriteSet dựng OrderEvent từ các đơn hàng được ghi trong giao dịch. Khóa composite
+// (chỉ mục, ReturnCase, TrackingEvent...) bắt đầu bằng 0x00 nên được bỏ qua.
+func eventFromWriteSet(tx *ledger.Transaction, action *ledger.ChaincodeAction) *contract.OrderEvent {
+	event := &contract.OrderEvent{TxID: tx.TxID, Timestamp: tx.Timestamp}
+	for _, write := range action.Writes {
+		if _, _, composite := ledger.SplitCompositeKey(write.Key); write.IsDelete || composite {
+			continue
+		}
+		var order contract.Order
+		if err := json.Unmarshal(write.Value, &order); err != nil || order.DocType != "Order" {
+			continue
+		}
+		// Chỉ đơn mà giao dịch này ghi lịch sử (MigrateOrders ghi lại đơn nhưng không đổi gì)
+		n := len(order.History)
+		if n == 0 || order.History[n-1].TxID != event.TxID {
+			continue
+		}
+		event.Action = order.History[n-1].Action
+		event.ActorOrg = order.History[n-1].ActorOrg
+		event.Orders = append(event.Orders, orderEventEntry(&order))
+	}
+	if len(event.Orders) == 0 {
+		return nil
+	}
+	return event
+}
+
+func orderEventEntry(order *contract.Order) contract.OrderEventEntry {
+	return contract.OrderEventEntry{
+		OrderID:          order.OrderID,
+		Status:           order.Status,
+		PaymentMethod:    order.PaymentMethod,
+		CodStatus:        order.CodStatus,
+		SellerCompanyID:  order.SellerCompanyID,
+		ShipperCompanyID: order.ShipperCompanyID,
+		TotalAmount:      order.TotalAmount,
+		RefundedAmount:   order.RefundedAmount,
+		PayoutAmount:     order.PayoutAmount,
+		ReturnIDs:        order.ReturnIDs,
+	}
+}
+
+func decodeOrderEvent(payload []byte) (*contract.OrderEvent, error) {
+	var event contract.OrderEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("payload %s không hợp lệ: %w", contract.OrderEventName, err)
+	}
+	return &event, nil
+}
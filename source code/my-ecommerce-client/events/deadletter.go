@@ -0,0 +1,157 @@
+// my-ecommerce-client/events/deadletter.go
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DeadLetter là webhook không gửi được sau khi hết lượt thử (hoặc bị bên nhận từ chối hẳn)
+type DeadLetter struct {
+	Webhook    *Webhook  `json:"webhook"`
+	Error      string    `json:"error"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Attempts   int       `json:"attempts"`
+	FailedAt   time.Time `json:"failedAt"`
+}
+
+// DeadLetterQueue lưu dead letter trong file JSONL (mỗi dòng một webhook). Checkpoint chỉ đi
+// tiếp sau khi webhook đã gửi được hoặc đã nằm trong file này, nên không sự kiện nào bị mất.
+type DeadLetterQueue struct {
+	path string
+	mu   sync.Mutex
+}
+
+// OpenDeadLetterQueue mở (tạo nếu chưa có) hàng đợi dead-letter tại path
+func OpenDeadLetterQueue(path string) (*DeadLetterQueue, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	file.Close()
+	return &DeadLetterQueue{path: path}, nil
+}
+
+// Add ghi thêm một dead letter và fsync trước khi trả về
+func (q *DeadLetterQueue) Add(letter *DeadLetter) error {
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	file, err := os.OpenFile(q.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// List đọc toàn bộ dead letter theo thứ tự ghi
+func (q *DeadLetterQueue) List() ([]*DeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.read()
+}
+
+func (q *DeadLetterQueue) read() ([]*DeadLetter, error) {
+	file, err := os.Open(q.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var letters []*DeadLetter
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			return nil, fmt.Errorf("%s dòng %d: %w", q.path, line, err)
+		}
+		letters = append(letters, &letter)
+	}
+	return letters, scanner.Err()
+}
+
+// Redeliver gửi lại mọi dead letter theo thứ tự; webhook vẫn lỗi được giữ lại (cập nhật lỗi
+// và số lần thử). File được thay bằng rename nên không hỏng khi dừng giữa chừng.
+func (q *DeadLetterQueue) Redeliver(ctx context.Context, sender *Sender) (delivered int, remaining int, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters, err := q.read()
+	if err != nil {
+		return 0, 0, err
+	}
+	var kept []*DeadLetter
+	for i, letter := range letters {
+		if ctx.Err() != nil {
+			kept = append(kept, letters[i:]...)
+			break
+		}
+		attempts, sendErr := sender.Send(ctx, letter.Webhook)
+		if sendErr == nil {
+			delivered++
+			continue
+		}
+		letter.Attempts += attempts
+		letter.Error = sendErr.Error()
+		letter.FailedAt = time.Now().UTC()
+		var deliveryErr *DeliveryError
+		if errors.As(sendErr, &deliveryErr) {
+			letter.StatusCode = deliveryErr.StatusCode
+		}
+		kept = append(kept, letter)
+	}
+	if err := q.rewrite(kept); err != nil {
+		return delivered, len(letters) - delivered, err
+	}
+	return delivered, len(kept), ctx.Err()
+}
+
+func (q *DeadLetterQueue) rewrite(letters []*DeadLetter) error {
+	temp, err := os.CreateTemp(filepath.Dir(q.path), filepath.Base(q.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	encoder := json.NewEncoder(temp)
+	for _, letter := range letters {
+		if err := encoder.Encode(letter); err != nil {
+			temp.Close()
+			return err
+		}
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), q.path)
+}
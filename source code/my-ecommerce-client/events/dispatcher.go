@@ -0,0 +1,54 @@
+// my-ecommerce-client/events/dispatcher.go
+
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// Dispatcher gửi webhook của từng Notification theo thứ tự sổ cái. Webhook gửi không được
+// sau khi hết lượt thử được chuyển vào DeadLetters để không chặn các sự kiện sau.
+type Dispatcher struct {
+	Sender      *Sender
+	DeadLetters *DeadLetterQueue
+	Types       map[string]bool // Loại webhook cần gửi; rỗng = mọi loại
+	Logger      *log.Logger
+}
+
+// Handle là Handler của Listener. Lỗi trả về (ghi dead letter thất bại, đang dừng) làm
+// Listener dừng mà không lưu checkpoint, nên sự kiện được xử lý lại khi khởi động lại.
+func (d *Dispatcher) Handle(ctx context.Context, n *Notification) error {
+	for _, webhook := range Webhooks(n) {
+		if len(d.Types) > 0 && !d.Types[webhook.Type] {
+			continue
+		}
+		attempts, err := d.Sender.Send(ctx, webhook)
+		if err == nil {
+			d.logf("Đã gửi %s %s (đơn %s, block %d, %d lần)", webhook.Type, webhook.ID, webhook.Order.OrderID, webhook.BlockNumber, attempts)
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		letter := &DeadLetter{Webhook: webhook, Error: err.Error(), Attempts: attempts, FailedAt: time.Now().UTC()}
+		var deliveryErr *DeliveryError
+		if errors.As(err, &deliveryErr) {
+			letter.StatusCode = deliveryErr.StatusCode
+		}
+		if err := d.DeadLetters.Add(letter); err != nil {
+			return err
+		}
+		d.logf("Chuyển %s %s vào dead-letter sau %d lần: %v", webhook.Type, webhook.ID, attempts, err)
+	}
+	return nil
+}
+
+func (d *Dispatcher) logf(format string, args ...interface{}) {
+	if d.Logger != nil {
+		d.Logger.Printf(format, args...)
+	}
+}
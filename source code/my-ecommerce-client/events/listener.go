@@ -0,0 +1,219 @@
+// my-ecommerce-client/events/listener.go
+
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ecommerce.com/client/contract"
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+)
+
+// Mode chọn nguồn sự kiện của Listener
+type Mode string
+
+const (
+	// ModeAuto nghe sự kiện chaincode, chuyển sang block khi luồng sự kiện chaincode liên tục
+	// hỏng (peer không hỗ trợ, định danh không có quyền...)
+	ModeAuto Mode = "auto"
+	// ModeChaincode chỉ nghe sự kiện chaincode
+	ModeChaincode Mode = "chaincode"
+	// ModeBlock đọc block; nhận cả giao dịch của chaincode cũ chưa phát sự kiện (qua write-set)
+	ModeBlock Mode = "block"
+)
+
+// fallbackAfter là số lần liên tiếp luồng sự kiện chaincode hỏng mà không nhận được gì
+// trước khi ModeAuto chuyển sang block
+const fallbackAfter = 3
+
+// errStreamClosed: luồng sự kiện bị đóng (peer khởi động lại, mất kết nối...)
+var errStreamClosed = errors.New("luồng sự kiện bị đóng")
+
+// Checkpointer lưu vị trí đã xử lý; client.FileCheckpointer thỏa mãn giao diện này
+type Checkpointer interface {
+	client.Checkpoint
+	CheckpointBlock(blockNumber uint64) error
+	CheckpointTransaction(blockNumber uint64, transactionID string) error
+	Sync() error
+}
+
+// EventSource cung cấp luồng sự kiện cho Listener; *client.Network thỏa mãn giao diện này
+type EventSource interface {
+	ChaincodeEvents(ctx context.Context, chaincodeName string, options ...client.ChaincodeEventsOption) (<-chan *client.ChaincodeEvent, error)
+	BlockEvents(ctx context.Context, options ...client.BlockEventsOption) (<-chan *common.Block, error)
+}
+
+// Handler xử lý một Notification; lỗi trả về làm Listener dừng mà không lưu checkpoint
+type Handler func(ctx context.Context, n *Notification) error
+
+// handlerError phân biệt lỗi của Handler với lỗi của luồng sự kiện
+type handlerError struct{ err error }
+
+func (e *handlerError) Error() string { return e.err.Error() }
+func (e *handlerError) Unwrap() error { return e.err }
+
+// Listener nghe sự kiện của một chaincode, lưu checkpoint sau mỗi sự kiện đã xử lý và tự
+// kết nối lại từ checkpoint khi luồng bị đóng
+type Listener struct {
+	Network      EventSource
+	Chaincode    string
+	Checkpointer Checkpointer
+	Mode         Mode
+	Logger       *log.Logger
+	RetryDelay   time.Duration // Chờ trước khi kết nối lại, nhân đôi tới tối đa 1 phút; mặc định 2s
+}
+
+// Run nghe sự kiện tới khi ctx bị hủy (trả về nil) hoặc handler trả về lỗi
+func (l *Listener) Run(ctx context.Context, handler Handler) error {
+	mode := l.Mode
+	if mode == ModeAuto || mode == "" {
+		mode = ModeChaincode
+	}
+	retryDelay := l.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = 2 * time.Second
+	}
+
+	failures := 0
+	for {
+		l.logf("Nghe sự kiện %s từ block %d (sau giao dịch %q)", mode, l.Checkpointer.BlockNumber(), l.Checkpointer.TransactionID())
+		var progressed bool
+		var err error
+		if mode == ModeBlock {
+			progressed, err = l.blockEvents(ctx, handler)
+		} else {
+			progressed, err = l.chaincodeEvents(ctx, handler)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		var handlerErr *handlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+
+		if progressed {
+			failures = 0
+		} else {
+			failures++
+		}
+		if (l.Mode == ModeAuto || l.Mode == "") && mode == ModeChaincode && failures >= fallbackAfter {
+			l.logf("Sự kiện chaincode lỗi %d lần liên tiếp (%v), chuyển sang đọc block", failures, err)
+			mode, failures = ModeBlock, 0
+			continue
+		}
+
+		delay := retryDelay << min(failures, 5)
+		if delay > time.Minute {
+			delay = time.Minute
+		}
+		l.logf("Mất luồng sự kiện: %v; kết nối lại sau %s", err, delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (l *Listener) chaincodeEvents(ctx context.Context, handler Handler) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := l.Network.ChaincodeEvents(ctx, l.Chaincode, client.WithCheckpoint(l.Checkpointer))
+	if err != nil {
+		return false, err
+	}
+	progressed := false
+	for event := range events {
+		progressed = true
+		if event.EventName == contract.OrderEventName {
+			orderEvent, err := decodeOrderEvent(event.Payload)
+			if err != nil {
+				l.logf("Bỏ qua sự kiện của giao dịch %s: %v", event.TransactionID, err)
+			} else {
+				notification := &Notification{
+					BlockNumber:   event.BlockNumber,
+					TransactionID: event.TransactionID,
+					Source:        SourceChaincode,
+					Event:         orderEvent,
+				}
+				if err := handler(ctx, notification); err != nil {
+					return progressed, &handlerError{err}
+				}
+			}
+		}
+		if err := l.checkpoint(event.BlockNumber, event.TransactionID); err != nil {
+			return progressed, &handlerError{err}
+		}
+	}
+	return progressed, errStreamClosed
+}
+
+func (l *Listener) blockEvents(ctx context.Context, handler Handler) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resumeBlock, resumeAfter := l.Checkpointer.BlockNumber(), l.Checkpointer.TransactionID()
+	blocks, err := l.Network.BlockEvents(ctx, client.WithCheckpoint(l.Checkpointer))
+	if err != nil {
+		return false, err
+	}
+	progressed := false
+	for block := range blocks {
+		progressed = true
+		number := block.GetHeader().GetNumber()
+		transactions := parseBlock(block, l.Chaincode)
+		for _, err := range transactions.skipped {
+			l.logf("Bỏ qua: %v", err)
+		}
+
+		// Block đang dở khi dừng lần trước: bỏ qua các giao dịch tới checkpoint
+		skipThrough := -1
+		if number == resumeBlock && resumeAfter != "" {
+			if position, ok := transactions.positions[resumeAfter]; ok {
+				skipThrough = position
+			}
+		}
+		for _, notification := range transactions.notifications {
+			if transactions.positions[notification.TransactionID] <= skipThrough {
+				continue
+			}
+			if err := handler(ctx, notification); err != nil {
+				return progressed, &handlerError{err}
+			}
+			if err := l.checkpoint(number, notification.TransactionID); err != nil {
+				return progressed, &handlerError{err}
+			}
+		}
+		if err := l.Checkpointer.CheckpointBlock(number); err != nil {
+			return progressed, &handlerError{err}
+		}
+		if err := l.Checkpointer.Sync(); err != nil {
+			return progressed, &handlerError{err}
+		}
+	}
+	return progressed, errStreamClosed
+}
+
+// checkpoint ghi vị trí và fsync: sự kiện đã gửi không được gửi lại sau khi khởi động lại
+func (l *Listener) checkpoint(blockNumber uint64, transactionID string) error {
+	if err := l.Checkpointer.CheckpointTransaction(blockNumber, transactionID); err != nil {
+		return fmt.Errorf("không lưu được checkpoint: %w", err)
+	}
+	if err := l.Checkpointer.Sync(); err != nil {
+		return fmt.Errorf("không lưu được checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (l *Listener) logf(format string, args ...interface{}) {
+	if l.Logger != nil {
+		l.Logger.Printf(format, args...)
+	}
+}
@@ -0,0 +1,251 @@
+// my-ecommerce-client/events/listener_test.go
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"ecommerce.com/client/contract"
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testChaincode = "ecommerce"
+
+// fakeSource phát lại các sự kiện / block cố định, bắt đầu từ vị trí của checkpoint như
+// Gateway làm với client.WithCheckpoint
+type fakeSource struct {
+	checkpoint     Checkpointer
+	events         []*client.ChaincodeEvent
+	blocks         []*common.Block
+	chaincodeErr   error
+	chaincodeOpens int
+	blockOpens     int
+}
+
+func (f *fakeSource) ChaincodeEvents(ctx context.Context, chaincodeName string, options ...client.ChaincodeEventsOption) (<-chan *client.ChaincodeEvent, error) {
+	f.chaincodeOpens++
+	if f.chaincodeErr != nil {
+		return nil, f.chaincodeErr
+	}
+	start, after := f.checkpoint.BlockNumber(), f.checkpoint.TransactionID()
+	events := make(chan *client.ChaincodeEvent, len(f.events))
+	skipping := after != ""
+	for _, event := range f.events {
+		if event.BlockNumber < start {
+			continue
+		}
+		if skipping && event.BlockNumber == start {
+			skipping = event.TransactionID != after
+			continue
+		}
+		events <- event
+	}
+	close(events)
+	return events, nil
+}
+
+func (f *fakeSource) BlockEvents(ctx context.Context, options ...client.BlockEventsOption) (<-chan *common.Block, error) {
+	f.blockOpens++
+	blocks := make(chan *common.Block, len(f.blocks))
+	for _, block := range f.blocks {
+		if block.GetHeader().GetNumber() >= f.checkpoint.BlockNumber() {
+			blocks <- block
+		}
+	}
+	close(blocks)
+	return blocks, nil
+}
+
+func openCheckpointer(t *testing.T, path string) *client.FileCheckpointer {
+	t.Helper()
+	checkpointer, err := client.NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { checkpointer.Close() })
+	return checkpointer
+}
+
+func orderEventPayload(t *testing.T, txID string, orderID string) []byte {
+	t.Helper()
+	payload, err := json.Marshal(&contract.OrderEvent{
+		Version: 1,
+		TxID:    txID,
+		Action:  "ShipOrder",
+		Orders:  []contract.OrderEventEntry{{OrderID: orderID, Status: "SHIPPED", PreviousStatus: "PAID"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func chaincodeEvent(t *testing.T, blockNumber uint64, txID string) *client.ChaincodeEvent {
+	return &client.ChaincodeEvent{
+		BlockNumber:   blockNumber,
+		TransactionID: txID,
+		ChaincodeName: testChaincode,
+		EventName:     contract.OrderEventName,
+		Payload:       orderEventPayload(t, txID, "O-"+txID),
+	}
+}
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	t.Helper()
+	data, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// endorserEnvelope dựng một giao dịch endorser hợp lệ có sự kiện OrderChanged
+func endorserEnvelope(t *testing.T, txID string) []byte {
+	event := &peer.ChaincodeEvent{ChaincodeId: testChaincode, TxId: txID, EventName: contract.OrderEventName, Payload: orderEventPayload(t, txID, "O-"+txID)}
+	action := &peer.ChaincodeAction{
+		Results:     mustMarshal(t, &rwset.TxReadWriteSet{}),
+		Events:      mustMarshal(t, event),
+		ChaincodeId: &peer.ChaincodeID{Name: testChaincode},
+	}
+	response := &peer.ProposalResponsePayload{Extension: mustMarshal(t, action)}
+	actionPayload := &peer.ChaincodeActionPayload{Action: &peer.ChaincodeEndorsedAction{ProposalResponsePayload: mustMarshal(t, response)}}
+	transaction := &peer.Transaction{Actions: []*peer.TransactionAction{{Payload: mustMarshal(t, actionPayload)}}}
+	header := &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), TxId: txID, Timestamp: timestamppb.Now()}
+	payload := &common.Payload{Header: &common.Header{ChannelHeader: mustMarshal(t, header)}, Data: mustMarshal(t, transaction)}
+	return mustMarshal(t, &common.Envelope{Payload: mustMarshal(t, payload)})
+}
+
+func testBlock(t *testing.T, number uint64, txIDs ...string) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+	for _, txID := range txIDs {
+		block.Data.Data = append(block.Data.Data, endorserEnvelope(t, txID))
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = make([]byte, len(txIDs)) // 0 = VALID
+	return block
+}
+
+// recorder là Handler ghi lại txID đã nhận, hủy ctx sau txID stopAfter và lỗi tại failAt
+type recorder struct {
+	received  []string
+	failAt    string
+	stopAfter string
+	cancel    context.CancelFunc
+}
+
+func (r *recorder) handle(ctx context.Context, n *Notification) error {
+	if n.TransactionID == r.failAt {
+		return errors.New("bên nhận lỗi")
+	}
+	r.received = append(r.received, n.TransactionID)
+	if n.TransactionID == r.stopAfter {
+		r.cancel()
+	}
+	return nil
+}
+
+func runListener(t *testing.T, listener *Listener, handler *recorder) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	handler.cancel = cancel
+	err := listener.Run(ctx, handler.handle)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Fatal("Listener không dừng")
+	}
+	return err
+}
+
+func TestListenerResumesFromCheckpointAfterHandlerError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	events := []*client.ChaincodeEvent{
+		chaincodeEvent(t, 3, "tx1"),
+		chaincodeEvent(t, 3, "tx2"),
+		chaincodeEvent(t, 4, "tx3"),
+		chaincodeEvent(t, 5, "tx4"),
+	}
+
+	// Lần chạy đầu: handler lỗi ở tx3 nên Listener dừng, checkpoint dừng sau tx2
+	checkpointer := openCheckpointer(t, path)
+	first := &recorder{failAt: "tx3"}
+	listener := &Listener{Network: &fakeSource{checkpoint: checkpointer, events: events}, Chaincode: testChaincode, Checkpointer: checkpointer, Mode: ModeChaincode}
+	if err := runListener(t, listener, first); err == nil {
+		t.Fatal("Run không trả lỗi của handler")
+	}
+	if !reflect.DeepEqual(first.received, []string{"tx1", "tx2"}) {
+		t.Errorf("lần đầu nhận %v, muốn [tx1 tx2]", first.received)
+	}
+	checkpointer.Close()
+
+	// Khởi động lại từ file checkpoint: tiếp tục từ tx3, không gửi lại tx1, tx2
+	checkpointer = openCheckpointer(t, path)
+	if checkpointer.BlockNumber() != 3 || checkpointer.TransactionID() != "tx2" {
+		t.Fatalf("checkpoint = (%d, %q), muốn (3, \"tx2\")", checkpointer.BlockNumber(), checkpointer.TransactionID())
+	}
+	second := &recorder{stopAfter: "tx4"}
+	listener = &Listener{Network: &fakeSource{checkpoint: checkpointer, events: events}, Chaincode: testChaincode, Checkpointer: checkpointer, Mode: ModeChaincode}
+	if err := runListener(t, listener, second); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(second.received, []string{"tx3", "tx4"}) {
+		t.Errorf("sau khi khởi động lại nhận %v, muốn [tx3 tx4]", second.received)
+	}
+}
+
+func TestListenerBlockModeSkipsHandledTransactions(t *testing.T) {
+	checkpointer := openCheckpointer(t, filepath.Join(t.TempDir(), "checkpoint.json"))
+	// Lần trước dừng giữa block 5, sau giao dịch A
+	if err := checkpointer.CheckpointTransaction(5, "A"); err != nil {
+		t.Fatal(err)
+	}
+	source := &fakeSource{checkpoint: checkpointer, blocks: []*common.Block{
+		testBlock(t, 4, "old"),
+		testBlock(t, 5, "A", "B", "C"),
+		testBlock(t, 6, "D"),
+	}}
+	handler := &recorder{stopAfter: "D"}
+	listener := &Listener{Network: source, Chaincode: testChaincode, Checkpointer: checkpointer, Mode: ModeBlock}
+	if err := runListener(t, listener, handler); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(handler.received, []string{"B", "C", "D"}) {
+		t.Errorf("nhận %v, muốn [B C D]", handler.received)
+	}
+	if checkpointer.BlockNumber() != 7 || checkpointer.TransactionID() != "" {
+		t.Errorf("checkpoint = (%d, %q), muốn (7, \"\") sau block 6", checkpointer.BlockNumber(), checkpointer.TransactionID())
+	}
+}
+
+func TestListenerAutoModeFallsBackToBlocks(t *testing.T) {
+	checkpointer := openCheckpointer(t, filepath.Join(t.TempDir(), "checkpoint.json"))
+	source := &fakeSource{
+		checkpoint:   checkpointer,
+		chaincodeErr: errors.New("permission denied"),
+		blocks:       []*common.Block{testBlock(t, 0, "A")},
+	}
+	handler := &recorder{stopAfter: "A"}
+	listener := &Listener{Network: source, Chaincode: testChaincode, Checkpointer: checkpointer, Mode: ModeAuto, RetryDelay: time.Millisecond}
+	if err := runListener(t, listener, handler); err != nil {
+		t.Fatal(err)
+	}
+	if source.chaincodeOpens != fallbackAfter || source.blockOpens != 1 {
+		t.Errorf("mở luồng chaincode %d lần, block %d lần; muốn %d và 1", source.chaincodeOpens, source.blockOpens, fallbackAfter)
+	}
+	if !reflect.DeepEqual(handler.received, []string{"A"}) {
+		t.Errorf("nhận %v, muốn [A]", handler.received)
+	}
+}
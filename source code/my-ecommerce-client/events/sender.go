@@ -0,0 +1,179 @@
+// my-ecommerce-client/events/sender.go
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header của webhook
+const (
+	HeaderEventID   = "X-Ecommerce-Event-Id"
+	HeaderEventType = "X-Ecommerce-Event-Type"
+	HeaderTimestamp = "X-Ecommerce-Timestamp" // Unix giây, nằm trong phần được ký
+	HeaderSignature = "X-Ecommerce-Signature" // sha256=<hex HMAC-SHA256(secret, timestamp + "." + body)>
+	HeaderAttempt   = "X-Ecommerce-Attempt"
+)
+
+// Sign tính chữ ký của một webhook
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify kiểm tra chữ ký phía nhận; tolerance > 0 từ chối webhook có timestamp lệch quá mức
+// (chống gửi lại webhook cũ bị bắt được)
+func Verify(secret []byte, timestamp string, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return errors.New("chữ ký không hợp lệ")
+	}
+	if tolerance > 0 {
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("timestamp không hợp lệ: %q", timestamp)
+		}
+		if skew := now.Sub(time.Unix(seconds, 0)); skew > tolerance || skew < -tolerance {
+			return fmt.Errorf("timestamp lệch %s", skew.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// DeliveryError là lỗi của một lần gửi
+type DeliveryError struct {
+	StatusCode int    // 0: lỗi mạng / timeout
+	Body       string // Đầu phản hồi của bên nhận
+	Err        error
+	Permanent  bool          // Không thử lại (4xx trừ 408, 429)
+	RetryAfter time.Duration // Theo header Retry-After (429, 503)
+}
+
+func (e *DeliveryError) Error() string {
+	if e.StatusCode == 0 {
+		return e.Err.Error()
+	}
+	if e.Body != "" {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("HTTP %d", e.StatusCode)
+}
+
+func (e *DeliveryError) Unwrap() error { return e.Err }
+
+// Sender gửi webhook tới URL, thử lại với backoff lũy thừa khi lỗi tạm thời
+type Sender struct {
+	URL            string
+	Secret         []byte
+	Client         *http.Client
+	MaxAttempts    int           // Mặc định 8
+	InitialBackoff time.Duration // Mặc định 1s, nhân đôi sau mỗi lần
+	MaxBackoff     time.Duration // Mặc định 1 phút
+}
+
+// Send gửi webhook, trả về số lần đã gửi. Lỗi trả về là lần gửi cuối (*DeliveryError) hoặc
+// ctx.Err() khi dừng giữa chừng.
+func (s *Sender) Send(ctx context.Context, w *Webhook) (int, error) {
+	body, err := json.Marshal(w)
+	if err != nil {
+		return 0, err
+	}
+	maxAttempts := s.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 8
+	}
+	backoff := s.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	maxBackoff := s.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := s.deliver(ctx, w, body, attempt)
+		if err == nil {
+			return attempt, nil
+		}
+		if ctx.Err() != nil {
+			return attempt, ctx.Err()
+		}
+		var deliveryErr *DeliveryError
+		if !errors.As(err, &deliveryErr) || deliveryErr.Permanent || attempt >= maxAttempts {
+			return attempt, err
+		}
+
+		// Jitter ±20% để nhiều daemon không dồn cùng lúc vào bên nhận vừa hồi phục
+		delay := backoff + time.Duration((rand.Float64()*0.4-0.2)*float64(backoff))
+		if deliveryErr.RetryAfter > delay {
+			delay = min(deliveryErr.RetryAfter, maxBackoff)
+		}
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(delay):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, w *Webhook, body []byte, attempt int) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return &DeliveryError{Err: err, Permanent: true}
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "ecom-events")
+	request.Header.Set(HeaderEventID, w.ID)
+	request.Header.Set(HeaderEventType, w.Type)
+	request.Header.Set(HeaderTimestamp, timestamp)
+	request.Header.Set(HeaderAttempt, strconv.Itoa(attempt))
+	if len(s.Secret) > 0 {
+		request.Header.Set(HeaderSignature, Sign(s.Secret, timestamp, body))
+	}
+
+	httpClient := s.Client
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return &DeliveryError{Err: err}
+	}
+	defer response.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	deliveryErr := &DeliveryError{
+		StatusCode: response.StatusCode,
+		Body:       strings.TrimSpace(string(snippet)),
+		Permanent: response.StatusCode >= 400 && response.StatusCode < 500 &&
+			response.StatusCode != http.StatusRequestTimeout && response.StatusCode != http.StatusTooManyRequests,
+	}
+	if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		deliveryErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return deliveryErr
+}
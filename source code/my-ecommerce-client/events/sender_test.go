@@ -0,0 +1,134 @@
+// my-ecommerce-client/events/sender_test.go
+
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"ecommerce.com/client/contract"
+)
+
+var testSecret = []byte("dev")
+
+// receiver là bên nhận webhook: kiểm tra chữ ký, trả lần lượt các mã trong statuses (hết thì 204)
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	attempts []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if err := Verify(testSecret, req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderSignature), body, time.Minute, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, req.Header.Get(HeaderAttempt))
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "1")
+	}
+	w.WriteHeader(status)
+}
+
+func newTestSender(t *testing.T, r *receiver) *Sender {
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return &Sender{URL: server.URL, Secret: testSecret, MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+}
+
+func testWebhook(id string) *Webhook {
+	return &Webhook{ID: id, Type: "order.shipped", TxID: id, Action: "ShipOrder", Order: contract.OrderEventEntry{OrderID: "W001", Status: "SHIPPED"}}
+}
+
+func TestSenderRetriesTransientFailures(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	attempts, err := newTestSender(t, r).Send(context.Background(), testWebhook("tx1-0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || !reflect.DeepEqual(r.attempts, []string{"1", "2", "3"}) {
+		t.Errorf("gửi %d lần, header attempt %v; muốn 3 lần [1 2 3]", attempts, r.attempts)
+	}
+}
+
+func TestSenderDoesNotRetryPermanentFailures(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusUnprocessableEntity}}
+	attempts, err := newTestSender(t, r).Send(context.Background(), testWebhook("tx1-0"))
+	var deliveryErr *DeliveryError
+	if !errors.As(err, &deliveryErr) || !deliveryErr.Permanent || deliveryErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("lỗi = %v, muốn DeliveryError 422 không thử lại", err)
+	}
+	if attempts != 1 {
+		t.Errorf("gửi %d lần, muốn 1", attempts)
+	}
+}
+
+func TestSenderGivesUpAfterMaxAttempts(t *testing.T) {
+	r := &receiver{statuses: []int{500, 500, 500, 500}}
+	attempts, err := newTestSender(t, r).Send(context.Background(), testWebhook("tx1-0"))
+	var deliveryErr *DeliveryError
+	if !errors.As(err, &deliveryErr) || deliveryErr.Permanent || deliveryErr.StatusCode != 500 {
+		t.Fatalf("lỗi = %v, muốn DeliveryError 500", err)
+	}
+	if attempts != 3 {
+		t.Errorf("gửi %d lần, muốn 3", attempts)
+	}
+}
+
+func TestSenderSignatureIsRejectedWithWrongSecret(t *testing.T) {
+	sender := newTestSender(t, &receiver{})
+	sender.Secret = []byte("khác")
+	attempts, err := sender.Send(context.Background(), testWebhook("tx1-0"))
+	var deliveryErr *DeliveryError
+	if !errors.As(err, &deliveryErr) || deliveryErr.StatusCode != http.StatusUnauthorized || attempts != 1 {
+		t.Errorf("gửi %d lần, lỗi = %v; muốn 401 sau 1 lần", attempts, err)
+	}
+}
+
+func TestDispatcherDeadLettersAndRedelivers(t *testing.T) {
+	queue, err := OpenDeadLetterQueue(filepath.Join(t.TempDir(), "dead-letters.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Đơn đầu bị từ chối hẳn, đơn sau vẫn được gửi: dead letter không chặn sự kiện sau
+	r := &receiver{statuses: []int{http.StatusUnprocessableEntity}}
+	dispatcher := &Dispatcher{Sender: newTestSender(t, r), DeadLetters: queue}
+	notification := &Notification{BlockNumber: 7, TransactionID: "tx9", Source: SourceChaincode, Event: &contract.OrderEvent{
+		Action: "ShipOrder",
+		Orders: []contract.OrderEventEntry{{OrderID: "W001", Status: "SHIPPED"}, {OrderID: "W002", Status: "SHIPPED"}},
+	}}
+	if err := dispatcher.Handle(context.Background(), notification); err != nil {
+		t.Fatal(err)
+	}
+
+	letters, err := queue.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 1 || letters[0].Webhook.ID != "tx9-0" || letters[0].StatusCode != http.StatusUnprocessableEntity || letters[0].Attempts != 1 {
+		t.Fatalf("dead letter = %+v, muốn tx9-0 với 422 sau 1 lần", letters)
+	}
+
+	delivered, remaining, err := queue.Redeliver(context.Background(), dispatcher.Sender)
+	if err != nil || delivered != 1 || remaining != 0 {
+		t.Errorf("Redeliver = (%d, %d, %v), muốn (1, 0, nil)", delivered, remaining, err)
+	}
+	if letters, _ := queue.List(); len(letters) != 0 {
+		t.Errorf("còn %d dead letter sau khi gửi lại", len(letters))
+	}
+}
@@ -0,0 +1,79 @@
+// my-ecommerce-client/events/webhook.go
+
+// Package events chuyển sự kiện OrderChanged của chaincode ecommerce thành webhook JSON có
+// chữ ký cho hệ thống ngoài chuỗi (Odoo...): nghe sự kiện chaincode (hoặc block khi cần),
+// lưu checkpoint bền vững, gửi lại khi lỗi và đưa webhook hỏng vào hàng đợi dead-letter.
+package events
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce.com/client/contract"
+)
+
+// Nguồn của một Notification
+const (
+	SourceChaincode = "chaincode" // Sự kiện chaincode
+	SourceBlock     = "block"     // Sự kiện chaincode đọc từ block
+	SourceWriteSet  = "writeset"  // Suy ra từ write-set (chaincode cũ chưa phát sự kiện)
+)
+
+// Notification là một sự kiện đã đọc từ sổ cái, kèm vị trí để lưu checkpoint
+type Notification struct {
+	BlockNumber   uint64
+	TransactionID string
+	Source        string
+	Event         *contract.OrderEvent
+}
+
+// Webhook là nội dung gửi cho bên nhận; mỗi đơn trong sự kiện là một webhook
+type Webhook struct {
+	ID          string                   `json:"id"`   // <txID>-<thứ tự>: khóa chống trùng phía nhận
+	Type        string                   `json:"type"` // order.shipped, order.delivered, order.tracking, order.updated...
+	BlockNumber uint64                   `json:"blockNumber"`
+	TxID        string                   `json:"txID"`
+	Action      string                   `json:"action"`
+	ActorOrg    string                   `json:"actorOrg"`
+	Timestamp   time.Time                `json:"timestamp"`
+	Order       contract.OrderEventEntry `json:"order"`
+	Tracking    *contract.TrackingEvent  `json:"tracking,omitempty"`
+	Source      string                   `json:"source"`
+}
+
+// Webhooks tách một Notification thành các webhook, theo thứ tự đơn trong sự kiện
+func Webhooks(n *Notification) []*Webhook {
+	event := n.Event
+	webhooks := make([]*Webhook, 0, len(event.Orders))
+	for i, entry := range event.Orders {
+		webhooks = append(webhooks, &Webhook{
+			ID:          n.TransactionID + "-" + strconv.Itoa(i),
+			Type:        webhookType(n, entry),
+			BlockNumber: n.BlockNumber,
+			TxID:        n.TransactionID,
+			Action:      event.Action,
+			ActorOrg:    event.ActorOrg,
+			Timestamp:   event.Timestamp,
+			Order:       entry,
+			Tracking:    event.Tracking,
+			Source:      n.Source,
+		})
+	}
+	return webhooks
+}
+
+// webhookType: order.tracking cho checkpoint vận chuyển, order.imported cho đơn lịch sử nạp
+// bằng ImportOrders, order.<trạng thái> khi trạng thái đổi (hoặc không biết trạng thái trước,
+// với sự kiện suy ra từ write-set), còn lại order.updated
+func webhookType(n *Notification, entry contract.OrderEventEntry) string {
+	switch {
+	case n.Event.Tracking != nil:
+		return "order.tracking"
+	case n.Event.Action == "ImportOrders":
+		return "order.imported"
+	case entry.PreviousStatus != "", n.Event.Action == "CreateOrder", n.Source == SourceWriteSet:
+		return "order." + strings.ToLower(string(entry.Status))
+	}
+	return "order.updated"
+}
@@ -0,0 +1,222 @@
+// my-ecommerce-client/export/datasets.go
+
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ecommerce.com/client/readmodel"
+)
+
+// Các dòng xuất. Thứ tự trường là thứ tự cột trong mọi định dạng; tên cột lấy từ tag parquet.
+// Số tiền là số nguyên (đơn vị nhỏ nhất) như trên sổ cái; cột thời gian giữ mili giây Unix
+// (timestamp UTC trong Parquet, RFC 3339 trong CSV / JSON Lines, 0 = null). Đổi cột phải tăng
+// SchemaVersion.
+
+// OrderRow là một đơn hàng (dataset orders, lọc theo created_at)
+type OrderRow struct {
+	OrderID          string `parquet:"order_id"`
+	Status           string `parquet:"status"`
+	PaymentMethod    string `parquet:"payment_method"`
+	CodStatus        string `parquet:"cod_status"`
+	SellerID         string `parquet:"seller_id"`
+	SellerCompanyID  string `parquet:"seller_company_id"`
+	ShipperID        string `parquet:"shipper_id"`
+	ShipperCompanyID string `parquet:"shipper_company_id"`
+	CreatedAt        int64  `parquet:"created_at,timestamp(millisecond)"`
+	UpdatedAt        int64  `parquet:"updated_at,timestamp(millisecond)"`
+	DeliveredAt      int64  `parquet:"delivered_at,optional,timestamp(millisecond)"`
+	TotalAmount      int64  `parquet:"total_amount"`
+	RefundedAmount   int64  `parquet:"refunded_amount"`
+	PlatformFee      int64  `parquet:"platform_fee"`
+	PayoutAmount     int64  `parquet:"payout_amount"`
+	LastTxID         string `parquet:"last_tx_id"`
+	LastBlock        int64  `parquet:"last_block"`
+}
+
+// SettlementRow là một lần thanh toán cho Seller (dataset settlements, lọc theo settled_at)
+type SettlementRow struct {
+	OrderID         string `parquet:"order_id"`
+	SettledAt       int64  `parquet:"settled_at,timestamp(millisecond)"`
+	PaymentMethod   string `parquet:"payment_method"`
+	SellerID        string `parquet:"seller_id"`
+	SellerCompanyID string `parquet:"seller_company_id"`
+	TotalAmount     int64  `parquet:"total_amount"`
+	RefundedAmount  int64  `parquet:"refunded_amount"`
+	PlatformFee     int64  `parquet:"platform_fee"`
+	PayoutAmount    int64  `parquet:"payout_amount"`
+	TxID            string `parquet:"tx_id"`
+	BlockNumber     int64  `parquet:"block_number"`
+}
+
+// RemittanceRow là một lần nộp tiền thu hộ của đơn COD (dataset remittances, lọc theo
+// remitted_at). RemitCOD ghi từng đơn, nên một đợt nộp của hãng vận chuyển là các dòng
+// cùng ngày / cùng người thực hiện; tx_id định danh từng lần ghi trên sổ cái.
+type RemittanceRow struct {
+	OrderID          string `parquet:"order_id"`
+	RemittedAt       int64  `parquet:"remitted_at,timestamp(millisecond)"`
+	SellerCompanyID  string `parquet:"seller_company_id"`
+	ShipperCompanyID string `parquet:"shipper_company_id"`
+	CodAmount        int64  `parquet:"cod_amount"`
+	ActorOrg         string `parquet:"actor_org"`
+	TxID             string `parquet:"tx_id"`
+}
+
+// dataset đọc một loại dòng từ read model và ghi ra các định dạng
+type dataset struct {
+	name    string
+	columns []Column
+	write   func(ctx context.Context, store *readmodel.Store, filter Filter, out rowSink) (int, error)
+}
+
+// datasets theo thứ tự xuất
+var datasets = []dataset{
+	{"orders", rowColumns[OrderRow](), writeRows(queryOrders)},
+	{"settlements", rowColumns[SettlementRow](), writeRows(querySettlements)},
+	{"remittances", rowColumns[RemittanceRow](), writeRows(queryRemittances)},
+}
+
+// DatasetNames là tên các dataset hỗ trợ
+func DatasetNames() []string {
+	names := make([]string, len(datasets))
+	for i, d := range datasets {
+		names[i] = d.name
+	}
+	return names
+}
+
+func lookupDataset(name string) (dataset, bool) {
+	for _, d := range datasets {
+		if d.name == name {
+			return d, true
+		}
+	}
+	return dataset{}, false
+}
+
+// rowQuery đọc các dòng theo bộ lọc, sắp xếp ổn định để hai lần xuất cùng dữ liệu cho cùng file
+type rowQuery[T any] func(ctx context.Context, store *readmodel.Store, filter Filter, each func(T) error) error
+
+func writeRows[T any](query rowQuery[T]) func(context.Context, *readmodel.Store, Filter, rowSink) (int, error) {
+	return func(ctx context.Context, store *readmodel.Store, filter Filter, out rowSink) (int, error) {
+		writers, err := newRowWriters[T](out)
+		if err != nil {
+			return 0, err
+		}
+		count := 0
+		err = query(ctx, store, filter, func(row T) error {
+			count++
+			for _, w := range writers {
+				if err := w.Write(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		for _, w := range writers {
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return count, err
+	}
+}
+
+func queryOrders(ctx context.Context, store *readmodel.Store, filter Filter, each func(OrderRow) error) error {
+	query := `SELECT order_id, status, payment_method, cod_status, seller_id, seller_company_id, shipper_id,
+			shipper_company_id, created_at, updated_at, delivered_at, total_amount, refunded_amount,
+			platform_fee, payout_amount, last_tx_id, last_block
+		FROM orders WHERE created_at >= ? AND created_at < ?`
+	args := []interface{}{filter.From.UTC(), filter.To.UTC()}
+	if filter.Company != "" {
+		query += ` AND (seller_company_id = ? OR shipper_company_id = ?)`
+		args = append(args, filter.Company, filter.Company)
+	}
+	query += ` ORDER BY created_at, order_id`
+
+	return scanRows(ctx, store, query, args, func(rows *sql.Rows) error {
+		var row OrderRow
+		var createdAt, updatedAt time.Time
+		var deliveredAt sql.NullTime
+		if err := rows.Scan(&row.OrderID, &row.Status, &row.PaymentMethod, &row.CodStatus, &row.SellerID,
+			&row.SellerCompanyID, &row.ShipperID, &row.ShipperCompanyID, &createdAt, &updatedAt,
+			&deliveredAt, &row.TotalAmount, &row.RefundedAmount, &row.PlatformFee, &row.PayoutAmount,
+			&row.LastTxID, &row.LastBlock); err != nil {
+			return err
+		}
+		row.CreatedAt, row.UpdatedAt, row.DeliveredAt = millis(createdAt), millis(updatedAt), millis(deliveredAt.Time)
+		return each(row)
+	})
+}
+
+func querySettlements(ctx context.Context, store *readmodel.Store, filter Filter, each func(SettlementRow) error) error {
+	query := `SELECT order_id, settled_at, payment_method, seller_id, seller_company_id, total_amount,
+			refunded_amount, platform_fee, payout_amount, tx_id, block_number
+		FROM settlements WHERE settled_at >= ? AND settled_at < ?`
+	args := []interface{}{filter.From.UTC(), filter.To.UTC()}
+	if filter.Company != "" {
+		query += ` AND seller_company_id = ?`
+		args = append(args, filter.Company)
+	}
+	query += ` ORDER BY settled_at, order_id`
+
+	return scanRows(ctx, store, query, args, func(rows *sql.Rows) error {
+		var row SettlementRow
+		var settledAt time.Time
+		if err := rows.Scan(&row.OrderID, &settledAt, &row.PaymentMethod, &row.SellerID, &row.SellerCompanyID,
+			&row.TotalAmount, &row.RefundedAmount, &row.PlatformFee, &row.PayoutAmount, &row.TxID, &row.BlockNumber); err != nil {
+			return err
+		}
+		row.SettledAt = millis(settledAt)
+		return each(row)
+	})
+}
+
+func queryRemittances(ctx context.Context, store *readmodel.Store, filter Filter, each func(RemittanceRow) error) error {
+	query := `SELECT h.order_id, h.happened_at, o.seller_company_id, o.shipper_company_id, o.total_amount,
+			h.actor_org, h.tx_id
+		FROM order_history h JOIN orders o ON o.order_id = h.order_id
+		WHERE h.action = 'RemitCOD' AND h.happened_at >= ? AND h.happened_at < ?`
+	args := []interface{}{filter.From.UTC(), filter.To.UTC()}
+	if filter.Company != "" {
+		query += ` AND (o.seller_company_id = ? OR o.shipper_company_id = ?)`
+		args = append(args, filter.Company, filter.Company)
+	}
+	query += ` ORDER BY h.happened_at, h.order_id`
+
+	return scanRows(ctx, store, query, args, func(rows *sql.Rows) error {
+		var row RemittanceRow
+		var remittedAt time.Time
+		if err := rows.Scan(&row.OrderID, &remittedAt, &row.SellerCompanyID, &row.ShipperCompanyID,
+			&row.CodAmount, &row.ActorOrg, &row.TxID); err != nil {
+			return err
+		}
+		row.RemittedAt = millis(remittedAt)
+		return each(row)
+	})
+}
+
+func scanRows(ctx context.Context, store *readmodel.Store, query string, args []interface{}, scan func(*sql.Rows) error) error {
+	rows, err := store.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fmt.Errorf("đọc read model: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+// millis đổi thời gian sang mili giây Unix, 0 khi không có giá trị
+func millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
@@ -0,0 +1,181 @@
+// my-ecommerce-client/export/export.go
+
+// Package export trích xuất đơn hàng, đối soát cho Seller và nộp tiền thu hộ COD từ read model
+// (ecom-indexer) ra CSV, JSON Lines và Parquet với lược đồ cột cố định, kèm manifest.json chứa
+// SHA-256 của từng file để kiểm tra bản trích xuất về sau (Verify).
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"ecommerce.com/client/readmodel"
+)
+
+// SchemaVersion là phiên bản lược đồ cột; tăng khi thêm, bớt hoặc đổi cột
+const SchemaVersion = 1
+
+// ManifestFile là tên manifest trong thư mục xuất
+const ManifestFile = "manifest.json"
+
+// Filter chọn dữ liệu theo kỳ [From, To) và công ty
+type Filter struct {
+	From    time.Time
+	To      time.Time
+	Company string // Rỗng = mọi công ty; khác rỗng: cửa hàng hoặc hãng vận chuyển của đơn
+}
+
+// Options là cấu hình một lần xuất
+type Options struct {
+	Filter   Filter
+	Datasets []string // Rỗng = mọi dataset (DatasetNames)
+	Formats  []Format // Rỗng = CSV
+	Dir      string   // Thư mục đích; không được chứa bản xuất trước (manifest.json)
+}
+
+// Run xuất các dataset vào Options.Dir và ghi manifest sau cùng: thư mục không có manifest là
+// bản xuất dở dang.
+func Run(ctx context.Context, store *readmodel.Store, options Options) (*Manifest, error) {
+	filter := options.Filter
+	if !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("kỳ xuất không hợp lệ: %s .. %s", filter.From.Format(time.RFC3339), filter.To.Format(time.RFC3339))
+	}
+	names := options.Datasets
+	if len(names) == 0 {
+		names = DatasetNames()
+	}
+	formats := options.Formats
+	if len(formats) == 0 {
+		formats = []Format{FormatCSV}
+	}
+	var selected []dataset
+	for _, name := range names {
+		d, ok := lookupDataset(name)
+		if !ok {
+			return nil, fmt.Errorf("dataset không hỗ trợ: %q (có: %v)", name, DatasetNames())
+		}
+		selected = append(selected, d)
+	}
+
+	manifestPath := filepath.Join(options.Dir, ManifestFile)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return nil, fmt.Errorf("%s đã có bản xuất (%s); chọn thư mục khác", options.Dir, ManifestFile)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err := os.MkdirAll(options.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   time.Now().UTC().Truncate(time.Second),
+		From:          filter.From,
+		To:            filter.To,
+		Company:       filter.Company,
+	}
+	var err error
+	if manifest.Checkpoints, err = readCheckpoints(ctx, store); err != nil {
+		return nil, err
+	}
+
+	for _, d := range selected {
+		files, err := exportDataset(ctx, store, d, filter, formats, options.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.name, err)
+		}
+		manifest.Files = append(manifest.Files, files...)
+	}
+	manifest.SHA256 = manifest.digest()
+	if err := manifest.write(manifestPath); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// exportDataset ghi một dataset ra mọi định dạng trong một lượt đọc
+func exportDataset(ctx context.Context, store *readmodel.Store, d dataset, filter Filter, formats []Format, dir string) ([]*FileEntry, error) {
+	type target struct {
+		file   *os.File
+		hash   hash.Hash
+		counts *countingWriter
+		entry  *FileEntry
+	}
+	var targets []*target
+	var sink rowSink
+	defer func() {
+		for _, t := range targets {
+			t.file.Close()
+		}
+	}()
+	for _, format := range formats {
+		name := d.name + "." + string(format)
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		t := &target{file: file, hash: sha256.New(), counts: &countingWriter{}, entry: &FileEntry{Dataset: d.name, Format: format, Path: name}}
+		targets = append(targets, t)
+		sink = append(sink, formatOutput{format: format, w: io.MultiWriter(file, t.hash, t.counts)})
+	}
+
+	rows, err := d.write(ctx, store, filter, sink)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*FileEntry, len(targets))
+	for i, t := range targets {
+		if err := t.file.Sync(); err != nil {
+			return nil, err
+		}
+		t.entry.Rows = rows
+		t.entry.Bytes = t.counts.n
+		t.entry.SHA256 = hex.EncodeToString(t.hash.Sum(nil))
+		t.entry.Columns = d.columns
+		entries[i] = t.entry
+	}
+	return entries, nil
+}
+
+// readCheckpoints ghi lại read model đã đồng bộ tới đâu và cho định danh nào
+func readCheckpoints(ctx context.Context, store *readmodel.Store) ([]Checkpoint, error) {
+	rows, err := store.Query(ctx, `SELECT stream, next_block, viewer_msp_id, viewer_role, viewer_company_id
+		FROM indexer_checkpoint ORDER BY stream`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	checkpoints := []Checkpoint{}
+	for rows.Next() {
+		var c Checkpoint
+		if err := rows.Scan(&c.Stream, &c.NextBlock, &c.ViewerMSPID, &c.ViewerRole, &c.ViewerCompanyID); err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, c)
+	}
+	return checkpoints, rows.Err()
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// rowColumns là lược đồ của kiểu dòng T
+func rowColumns[T any]() []Column {
+	var zero T
+	return columnsOf(reflect.TypeOf(zero))
+}
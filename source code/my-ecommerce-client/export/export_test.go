@@ -0,0 +1,191 @@
+// my-ecommerce-client/export/export_test.go
+
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ecommerce.com/client/contract"
+	"ecommerce.com/client/ledger"
+	"ecommerce.com/client/readmodel"
+	"github.com/parquet-go/parquet-go"
+)
+
+var (
+	periodStart = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd   = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// testOrder tạo đơn của Shop_ABC / GHN tạo lúc createdAt, lịch sử gồm các action cách nhau một ngày
+func testOrder(orderID string, paymentMethod string, status contract.Status, createdAt time.Time, actions ...string) *contract.Order {
+	order := &contract.Order{
+		DocType: "Order", SchemaVersion: 2, OrderID: orderID, Status: status, PaymentMethod: paymentMethod,
+		SellerID: "SellerOrgMSP", ShipperID: "ShipperOrgMSP", SellerCompanyID: "Shop_ABC", ShipperCompanyID: "GHN",
+		CreatedAt: createdAt, UpdatedAt: createdAt, TotalAmount: 300000,
+		Lines: []contract.OrderLine{{LineID: "L1", SKU: "AO", Quantity: 3, UnitPrice: 100000}},
+	}
+	for i, action := range actions {
+		at := createdAt.Add(time.Duration(i) * 24 * time.Hour)
+		order.History = append(order.History, contract.HistoryEntry{TxID: "tx1", Timestamp: at, Action: action, ActorOrg: "ECommercePlatformOrgMSP"})
+		order.UpdatedAt = at
+	}
+	return order
+}
+
+// newTestStore tạo read model SQLite có ba đơn: một đơn đã đối soát và một đơn COD đã nộp
+// tiền trong kỳ, một đơn tạo trước kỳ
+func newTestStore(t *testing.T) *readmodel.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := readmodel.Open(ctx, "sqlite:"+filepath.Join(t.TempDir(), "readmodel.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	stream := readmodel.Stream{Channel: "mychannel", Chaincode: "ecommerce", Viewer: readmodel.Viewer{MSPID: "ECommercePlatformOrgMSP", Role: readmodel.RolePlatform}}
+	if err := store.InitCheckpoint(ctx, stream, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	settled := testOrder("E001", contract.PaymentPrepaid, contract.StatusSettled, periodStart.Add(time.Hour),
+		"CreateOrder", "ConfirmPayment", "ShipOrder", "ConfirmDelivery", "PayoutToSeller")
+	settled.PayoutAmount = 300000
+	remitted := testOrder("E002", contract.PaymentCOD, contract.StatusDelivered, periodStart.Add(2*time.Hour),
+		"CreateOrder", "ShipOrder", "ConfirmDelivery", "RemitCOD")
+	remitted.CodStatus = "REMITTED"
+	before := testOrder("E003", contract.PaymentPrepaid, contract.StatusCreated, periodStart.Add(-time.Hour), "CreateOrder")
+
+	var writes []*ledger.Write
+	for _, order := range []*contract.Order{settled, remitted, before} {
+		value, err := json.Marshal(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writes = append(writes, &ledger.Write{Key: order.OrderID, Value: value})
+	}
+	block := &ledger.Block{Number: 0, Transactions: []*ledger.Transaction{{
+		BlockNumber: 0, TxID: "tx1", Timestamp: periodStart,
+		Actions: []*ledger.ChaincodeAction{{Chaincode: "ecommerce", Writes: writes}},
+	}}}
+	if _, applied, err := store.ApplyBlock(ctx, stream, block); err != nil || !applied {
+		t.Fatalf("ApplyBlock = %v, %v", applied, err)
+	}
+	return store
+}
+
+// countRows đếm số dòng dữ liệu thực có trong file xuất theo định dạng
+func countRows(t *testing.T, path string, format Format) int {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if format == FormatParquet {
+		info, err := file.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parquetFile, err := parquet.OpenFile(file, info.Size())
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		return int(parquetFile.NumRows())
+	}
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if format == FormatCSV {
+		lines-- // Dòng tiêu đề
+	}
+	return lines
+}
+
+func TestRunThenVerify(t *testing.T) {
+	store := newTestStore(t)
+	dir := filepath.Join(t.TempDir(), "export")
+	options := Options{Filter: Filter{From: periodStart, To: periodEnd}, Formats: Formats, Dir: dir}
+	manifest, err := Run(context.Background(), store, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRows := map[string]int{"orders": 2, "settlements": 1, "remittances": 1}
+	if len(manifest.Files) != len(wantRows)*len(Formats) {
+		t.Fatalf("manifest có %d file, muốn %d", len(manifest.Files), len(wantRows)*len(Formats))
+	}
+	for _, file := range manifest.Files {
+		if file.Rows != wantRows[file.Dataset] {
+			t.Errorf("%s: manifest ghi %d dòng, muốn %d", file.Path, file.Rows, wantRows[file.Dataset])
+		}
+		if rows := countRows(t, filepath.Join(dir, file.Path), file.Format); rows != file.Rows {
+			t.Errorf("%s: file có %d dòng, manifest ghi %d", file.Path, rows, file.Rows)
+		}
+	}
+	if len(manifest.Checkpoints) != 1 || manifest.Checkpoints[0].NextBlock != 1 {
+		t.Errorf("checkpoints = %+v, muốn một luồng ở block 1", manifest.Checkpoints)
+	}
+
+	verified, err := Verify(dir)
+	if err != nil {
+		t.Fatalf("Verify bản xuất nguyên vẹn: %v", err)
+	}
+	if verified.SHA256 != manifest.SHA256 {
+		t.Errorf("Verify đọc sha256 %s, Run ghi %s", verified.SHA256, manifest.SHA256)
+	}
+
+	// Không ghi đè bản xuất đã có
+	if _, err := Run(context.Background(), store, options); err == nil {
+		t.Error("Run ghi đè thư mục đã có manifest")
+	}
+}
+
+func TestVerifyDetectsTamperedFiles(t *testing.T) {
+	store := newTestStore(t)
+	dir := filepath.Join(t.TempDir(), "export")
+	if _, err := Run(context.Background(), store, Options{Filter: Filter{From: periodStart, To: periodEnd}, Formats: Formats, Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sửa một số tiền trong CSV (cùng kích thước) và xóa file Parquet
+	csvPath := filepath.Join(dir, "settlements.csv")
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), "300000", "900000", 1)
+	if tampered == string(data) {
+		t.Fatal("không tìm thấy số tiền để sửa")
+	}
+	if err := os.WriteFile(csvPath, []byte(tampered), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "orders.parquet")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Verify(dir)
+	if err == nil {
+		t.Fatal("Verify chấp nhận bản xuất đã bị sửa")
+	}
+	for _, path := range []string{"settlements.csv", "orders.parquet"} {
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("lỗi không nêu %s:\n%v", path, err)
+		}
+	}
+	if strings.Contains(err.Error(), "remittances.csv") {
+		t.Errorf("lỗi nêu file không bị sửa:\n%v", err)
+	}
+}
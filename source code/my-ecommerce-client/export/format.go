@@ -0,0 +1,197 @@
+// my-ecommerce-client/export/format.go
+
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Format là định dạng file xuất
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSONL   Format = "jsonl"
+	FormatParquet Format = "parquet"
+)
+
+// Formats là các định dạng hỗ trợ
+var Formats = []Format{FormatCSV, FormatJSONL, FormatParquet}
+
+// ParseFormat đọc tên định dạng (csv | jsonl | parquet)
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("định dạng không hỗ trợ: %q (csv | jsonl | parquet)", name)
+}
+
+// timeLayout: thời gian trong CSV / JSON Lines, cùng độ chính xác mili giây với Parquet
+const timeLayout = "2006-01-02T15:04:05.000Z"
+
+// Column là một cột trong lược đồ xuất (ghi vào manifest)
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // string | int64 | timestamp
+	Nullable bool   `json:"nullable,omitempty"`
+}
+
+// columnsOf đọc lược đồ từ tag parquet của kiểu dòng
+func columnsOf(t reflect.Type) []Column {
+	columns := make([]Column, t.NumField())
+	for i := range columns {
+		field := t.Field(i)
+		options := strings.Split(field.Tag.Get("parquet"), ",")
+		column := Column{Name: options[0], Type: field.Type.Kind().String()}
+		for _, option := range options[1:] {
+			switch {
+			case option == "optional":
+				column.Nullable = true
+			case strings.HasPrefix(option, "timestamp"):
+				column.Type = "timestamp"
+			}
+		}
+		columns[i] = column
+	}
+	return columns
+}
+
+// rowSink là các file đích của một dataset, mỗi định dạng một file
+type rowSink []formatOutput
+
+type formatOutput struct {
+	format Format
+	w      io.Writer
+}
+
+// rowWriter ghi từng dòng của một dataset vào một định dạng
+type rowWriter[T any] interface {
+	Write(row T) error
+	Close() error
+}
+
+func newRowWriters[T any](out rowSink) ([]rowWriter[T], error) {
+	var zero T
+	columns := columnsOf(reflect.TypeOf(zero))
+	writers := make([]rowWriter[T], len(out))
+	for i, output := range out {
+		switch output.format {
+		case FormatCSV:
+			w := csv.NewWriter(output.w)
+			names := make([]string, len(columns))
+			for j, column := range columns {
+				names[j] = column.Name
+			}
+			if err := w.Write(names); err != nil {
+				return nil, err
+			}
+			writers[i] = &csvWriter[T]{w: w, columns: columns}
+		case FormatJSONL:
+			writers[i] = &jsonlWriter[T]{w: bufio.NewWriter(output.w), columns: columns}
+		case FormatParquet:
+			writers[i] = &parquetWriter[T]{w: parquet.NewGenericWriter[T](output.w)}
+		default:
+			return nil, fmt.Errorf("định dạng không hỗ trợ: %q", output.format)
+		}
+	}
+	return writers, nil
+}
+
+// fieldText là giá trị của một cột dạng văn bản; null = true khi cột nullable có giá trị 0
+func fieldText(value reflect.Value, column Column) (text string, null bool) {
+	switch v := value.Interface().(type) {
+	case int64:
+		if column.Nullable && v == 0 {
+			return "", true
+		}
+		if column.Type == "timestamp" {
+			return time.UnixMilli(v).UTC().Format(timeLayout), false
+		}
+		return strconv.FormatInt(v, 10), false
+	case string:
+		return v, false
+	}
+	return fmt.Sprint(value.Interface()), false
+}
+
+type csvWriter[T any] struct {
+	w       *csv.Writer
+	columns []Column
+}
+
+func (c *csvWriter[T]) Write(row T) error {
+	value := reflect.ValueOf(row)
+	record := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		record[i], _ = fieldText(value.Field(i), column)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter[T]) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonlWriter ghi mỗi dòng một đối tượng JSON với khóa theo thứ tự cột
+type jsonlWriter[T any] struct {
+	w       *bufio.Writer
+	columns []Column
+}
+
+func (j *jsonlWriter[T]) Write(row T) error {
+	value := reflect.ValueOf(row)
+	j.w.WriteByte('{')
+	for i, column := range j.columns {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		name, _ := json.Marshal(column.Name)
+		j.w.Write(name)
+		j.w.WriteByte(':')
+		text, null := fieldText(value.Field(i), column)
+		switch {
+		case null:
+			j.w.WriteString("null")
+		case column.Type == "int64":
+			j.w.WriteString(text)
+		default:
+			encoded, err := json.Marshal(text)
+			if err != nil {
+				return err
+			}
+			j.w.Write(encoded)
+		}
+	}
+	j.w.WriteString("}\n")
+	return nil
+}
+
+func (j *jsonlWriter[T]) Close() error {
+	return j.w.Flush()
+}
+
+type parquetWriter[T any] struct {
+	w *parquet.GenericWriter[T]
+}
+
+func (p *parquetWriter[T]) Write(row T) error {
+	_, err := p.w.Write([]T{row})
+	return err
+}
+
+func (p *parquetWriter[T]) Close() error {
+	return p.w.Close()
+}
@@ -0,0 +1,127 @@
+// my-ecommerce-client/export/manifest.go
+
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Manifest mô tả một bản xuất: bộ lọc, nguồn (checkpoint của read model) và từng file kèm số
+// dòng, lược đồ cột và SHA-256. SHA256 của manifest băm danh sách "<sha256>  <file>" (như
+// sha256sum) sắp theo tên file, đại diện cho toàn bộ dữ liệu đã xuất.
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	GeneratedAt   time.Time    `json:"generatedAt"`
+	From          time.Time    `json:"from"`
+	To            time.Time    `json:"to"`
+	Company       string       `json:"company,omitempty"`
+	Checkpoints   []Checkpoint `json:"checkpoints"`
+	Files         []*FileEntry `json:"files"`
+	SHA256        string       `json:"sha256"`
+}
+
+// Checkpoint là vị trí đồng bộ của read model khi xuất (bảng indexer_checkpoint)
+type Checkpoint struct {
+	Stream          string `json:"stream"`
+	NextBlock       uint64 `json:"nextBlock"`
+	ViewerMSPID     string `json:"viewerMSPID"`
+	ViewerRole      string `json:"viewerRole"`
+	ViewerCompanyID string `json:"viewerCompanyID,omitempty"`
+}
+
+// FileEntry là một file dữ liệu của bản xuất
+type FileEntry struct {
+	Dataset string   `json:"dataset"`
+	Format  Format   `json:"format"`
+	Path    string   `json:"path"` // Tương đối với thư mục chứa manifest
+	Rows    int      `json:"rows"`
+	Bytes   int64    `json:"bytes"`
+	SHA256  string   `json:"sha256"`
+	Columns []Column `json:"columns"`
+}
+
+// digest băm danh sách file theo định dạng của sha256sum
+func (m *Manifest) digest() string {
+	files := append([]*FileEntry(nil), m.Files...)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	hash := sha256.New()
+	for _, file := range files {
+		io.WriteString(hash, file.SHA256+"  "+file.Path+"\n")
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (m *Manifest) write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ReadManifest đọc manifest.json (đường dẫn tới file hoặc thư mục xuất)
+func ReadManifest(path string) (*Manifest, string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, ManifestFile)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	return &manifest, filepath.Dir(path), nil
+}
+
+// Verify băm lại các file của bản xuất và so với manifest. Lỗi liệt kê mọi file thiếu hoặc
+// khác; nil khi bản xuất còn nguyên vẹn.
+func Verify(path string) (*Manifest, error) {
+	manifest, dir, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	if manifest.digest() != manifest.SHA256 {
+		problems = append(problems, "danh sách file không khớp sha256 của manifest")
+	}
+	for _, file := range manifest.Files {
+		sum, size, err := hashFile(filepath.Join(dir, file.Path))
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s: %v", file.Path, err))
+		case size != file.Bytes || sum != file.SHA256:
+			problems = append(problems, fmt.Sprintf("%s: sha256 %s (%d byte), manifest ghi %s (%d byte)", file.Path, sum, size, file.SHA256, file.Bytes))
+		}
+	}
+	if len(problems) > 0 {
+		return manifest, fmt.Errorf("bản xuất không khớp manifest:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return manifest, nil
+}
+
+func hashFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
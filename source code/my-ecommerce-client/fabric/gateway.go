@@ -0,0 +1,230 @@
+// my-ecommerce-client/fabric/gateway.go
+
+package fabric
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"ecommerce.com/client/contract"
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-gateway/pkg/identity"
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/gateway"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Connection là một kết nối Fabric Gateway của một định danh
+type Connection struct {
+	Profile  *Profile
+	grpcConn *grpc.ClientConn
+	gateway  *client.Gateway
+	network  *client.Network
+	contract *client.Contract
+}
+
+// Connect mở kết nối gRPC tới peer gateway và kết nối Gateway với định danh của hồ sơ
+func Connect(profile *Profile) (*Connection, error) {
+	grpcConn, err := newGrpcConnection(profile)
+	if err != nil {
+		return nil, err
+	}
+	id, sign, err := newIdentity(profile)
+	if err != nil {
+		grpcConn.Close()
+		return nil, err
+	}
+
+	gw, err := client.Connect(id,
+		client.WithSign(sign),
+		client.WithClientConnection(grpcConn),
+		client.WithEvaluateTimeout(profile.Timeouts.Evaluate),
+		client.WithEndorseTimeout(profile.Timeouts.Endorse),
+		client.WithSubmitTimeout(profile.Timeouts.Submit),
+		client.WithCommitStatusTimeout(profile.Timeouts.CommitStatus),
+	)
+	if err != nil {
+		grpcConn.Close()
+		return nil, fmt.Errorf("không kết nối được gateway: %w", err)
+	}
+	network := gw.GetNetwork(profile.Channel)
+	return &Connection{
+		Profile:  profile,
+		grpcConn: grpcConn,
+		gateway:  gw,
+		network:  network,
+		contract: network.GetContract(profile.Chaincode),
+	}, nil
+}
+
+// Close đóng kết nối Gateway và gRPC
+func (c *Connection) Close() error {
+	c.gateway.Close()
+	return c.grpcConn.Close()
+}
+
+// Network trả về kênh, dùng để nghe sự kiện chaincode / block
+func (c *Connection) Network() *client.Network {
+	return c.network
+}
+
+// ChainHeight đọc chiều cao sổ cái của kênh (số của block kế tiếp) qua qscc GetChainInfo
+func (c *Connection) ChainHeight(ctx context.Context) (uint64, error) {
+	result, err := c.network.GetContract("qscc").EvaluateWithContext(ctx, "GetChainInfo", client.WithArguments(c.network.Name()))
+	if err != nil {
+		return 0, fmt.Errorf("qscc GetChainInfo: %w", err)
+	}
+	info := &common.BlockchainInfo{}
+	if err := proto.Unmarshal(result, info); err != nil {
+		return 0, err
+	}
+	return info.GetHeight(), nil
+}
+
+// Contract trả về contract.Contract gọi chaincode qua Gateway
+func (c *Connection) Contract() contract.Contract {
+	return &gatewayContract{contract: c.contract, locale: c.Profile.Locale}
+}
+
+func newGrpcConnection(profile *Profile) (*grpc.ClientConn, error) {
+	caPEM, err := readPEM(profile.TLSCACert)
+	if err != nil {
+		return nil, fmt.Errorf("không đọc được tlsCACert: %w", err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("tlsCACert %s không chứa chứng chỉ PEM", profile.TLSCACert)
+	}
+	transportCredentials := credentials.NewClientTLSFromCert(certPool, profile.PeerHostOverride)
+	conn, err := grpc.Dial(profile.PeerEndpoint, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("không kết nối được %s: %w", profile.PeerEndpoint, err)
+	}
+	return conn, nil
+}
+
+func newIdentity(profile *Profile) (*identity.X509Identity, identity.Sign, error) {
+	certPEM, err := readPEM(profile.CertPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("không đọc được certPath: %w", err)
+	}
+	cert, err := identity.CertificateFromPEM(certPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("certPath không hợp lệ: %w", err)
+	}
+	id, err := identity.NewX509Identity(profile.MSPID, cert)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM, err := readPEM(profile.KeyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("không đọc được keyPath: %w", err)
+	}
+	key, err := identity.PrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("keyPath không hợp lệ: %w", err)
+	}
+	sign, err := identity.NewPrivateKeySign(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, sign, nil
+}
+
+// gatewayContract thực hiện contract.Contract trên client.Contract của fabric-gateway
+type gatewayContract struct {
+	contract *client.Contract
+	locale   string
+}
+
+func (g *gatewayContract) options(call contract.Call) []client.ProposalOption {
+	if call.Transient[contract.TransientLocale] == nil {
+		call = call.WithLocale(g.locale)
+	}
+	options := []client.ProposalOption{client.WithArguments(call.Args...)}
+	if len(call.Transient) > 0 {
+		options = append(options, client.WithTransient(call.Transient))
+	}
+	return options
+}
+
+func (g *gatewayContract) Submit(ctx context.Context, call contract.Call) ([]byte, error) {
+	result, err := g.contract.SubmitWithContext(ctx, call.Name, g.options(call)...)
+	return result, translateError(err)
+}
+
+func (g *gatewayContract) Evaluate(ctx context.Context, call contract.Call) ([]byte, error) {
+	result, err := g.contract.EvaluateWithContext(ctx, call.Name, g.options(call)...)
+	return result, translateError(err)
+}
+
+// translateError chuyển lỗi của fabric-gateway thành *contract.Error. Lỗi JSON của
+// chaincode nằm trong chi tiết (ErrorDetail) của từng peer endorse.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var commitErr *client.CommitError
+	if errors.As(err, &commitErr) {
+		return &contract.Error{
+			Code:          contract.ErrCommitFailed,
+			Message:       commitErr.Error(),
+			Details:       map[string]interface{}{"validationCode": commitErr.Code.String()},
+			TransactionID: commitErr.TransactionID,
+		}
+	}
+
+	txID := transactionID(err)
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if errorDetail, ok := detail.(*gateway.ErrorDetail); ok {
+			if contractErr, ok := contract.ParseMessage(errorDetail.GetMessage()); ok {
+				contractErr.TransactionID = txID
+				return contractErr
+			}
+		}
+	}
+	if contractErr, ok := contract.ParseMessage(st.Message()); ok {
+		contractErr.TransactionID = txID
+		return contractErr
+	}
+
+	code := contract.ErrUnknown
+	switch {
+	case st.Code() == codes.Unavailable:
+		code = contract.ErrUnavailable
+	case st.Code() == codes.DeadlineExceeded, errors.Is(err, context.DeadlineExceeded):
+		code = contract.ErrTimeout
+	}
+	message := st.Message()
+	for _, detail := range st.Details() {
+		if errorDetail, ok := detail.(*gateway.ErrorDetail); ok {
+			message += fmt.Sprintf("; %s (%s): %s", errorDetail.GetAddress(), errorDetail.GetMspId(), errorDetail.GetMessage())
+		}
+	}
+	return &contract.Error{Code: code, Message: message, TransactionID: txID}
+}
+
+// transactionID lấy mã giao dịch từ lỗi endorse / submit / commit status
+func transactionID(err error) string {
+	var endorseErr *client.EndorseError
+	var submitErr *client.SubmitError
+	var statusErr *client.CommitStatusError
+	switch {
+	case errors.As(err, &endorseErr):
+		return endorseErr.TransactionID
+	case errors.As(err, &submitErr):
+		return submitErr.TransactionID
+	case errors.As(err, &statusErr):
+		return statusErr.TransactionID
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+// my-ecommerce-client/fabric/profile.go
+
+// Package fabric kết nối tới chaincode ecommerce qua Fabric Gateway theo một hồ sơ
+// (profile) gồm định danh, peer gateway, kênh và tên chaincode.
+package fabric
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Giá trị mặc định theo mạng my-ecommerce-network (deploy.example.yaml)
+const (
+	DefaultChannel   = "orderchannel"
+	DefaultChaincode = "ecommerce"
+)
+
+// Profile là cấu hình kết nối của một định danh. Đường dẫn tương đối được tính từ
+// thư mục chứa file hồ sơ.
+type Profile struct {
+	Name             string   `yaml:"-"`
+	MSPID            string   `yaml:"mspID"`
+	PeerEndpoint     string   `yaml:"peerEndpoint"`     // VD: localhost:9051
+	PeerHostOverride string   `yaml:"peerHostOverride"` // Tên trong chứng chỉ TLS của peer, VD: peer0.seller.com
+	TLSCACert        string   `yaml:"tlsCACert"`        // CA TLS của peer
+	CertPath         string   `yaml:"certPath"`         // File chứng chỉ hoặc thư mục signcerts
+	KeyPath          string   `yaml:"keyPath"`          // File khóa riêng hoặc thư mục keystore
+	Channel          string   `yaml:"channel"`
+	Chaincode        string   `yaml:"chaincode"`
+	Locale           string   `yaml:"locale"`    // Ngôn ngữ thông báo lỗi: vi (mặc định) | en
+	CompanyID        string   `yaml:"companyID"` // Mã công ty trong chứng chỉ (chỉ để hiển thị / lọc)
+	Timeouts         Timeouts `yaml:"timeouts"`
+}
+
+// Timeouts là thời gian chờ của từng bước giao dịch
+type Timeouts struct {
+	Evaluate     time.Duration `yaml:"evaluate"`
+	Endorse      time.Duration `yaml:"endorse"`
+	Submit       time.Duration `yaml:"submit"`
+	CommitStatus time.Duration `yaml:"commitStatus"`
+}
+
+// ProfileFile chứa nhiều hồ sơ, VD: một hồ sơ cho mỗi tổ chức
+//
+//	default: seller
+//	profiles:
+//	  seller:
+//	    mspID: SellerOrgMSP
+//	    peerEndpoint: localhost:9051
+//	    ...
+type ProfileFile struct {
+	Default  string              `yaml:"default"`
+	Profiles map[string]*Profile `yaml:"profiles"`
+}
+
+// LoadProfile đọc hồ sơ name (rỗng = hồ sơ mặc định) trong file path
+func LoadProfile(path string, name string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("không đọc được file hồ sơ: %w", err)
+	}
+	var file ProfileFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("file hồ sơ %s không hợp lệ: %w", path, err)
+	}
+
+	if name == "" {
+		name = file.Default
+	}
+	if name == "" && len(file.Profiles) == 1 {
+		for only := range file.Profiles {
+			name = only
+		}
+	}
+	profile, ok := file.Profiles[name]
+	if !ok || profile == nil {
+		names := make([]string, 0, len(file.Profiles))
+		for n := range file.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("không có hồ sơ %q trong %s (có: %v)", name, path, names)
+	}
+	profile.Name = name
+
+	baseDir := filepath.Dir(path)
+	for _, p := range []*string{&profile.TLSCACert, &profile.CertPath, &profile.KeyPath} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(baseDir, *p)
+		}
+	}
+	profile.applyDefaults()
+	return profile, profile.validate()
+}
+
+func (p *Profile) applyDefaults() {
+	if p.Channel == "" {
+		p.Channel = DefaultChannel
+	}
+	if p.Chaincode == "" {
+		p.Chaincode = DefaultChaincode
+	}
+	if p.Timeouts.Evaluate == 0 {
+		p.Timeouts.Evaluate = 5 * time.Second
+	}
+	if p.Timeouts.Endorse == 0 {
+		p.Timeouts.Endorse = 15 * time.Second
+	}
+	if p.Timeouts.Submit == 0 {
+		p.Timeouts.Submit = 5 * time.Second
+	}
+	if p.Timeouts.CommitStatus == 0 {
+		p.Timeouts.CommitStatus = time.Minute
+	}
+}
+
+func (p *Profile) validate() error {
+	required := map[string]string{
+		"mspID":        p.MSPID,
+		"peerEndpoint": p.PeerEndpoint,
+		"tlsCACert":    p.TLSCACert,
+		"certPath":     p.CertPath,
+		"keyPath":      p.KeyPath,
+	}
+	for _, field := range []string{"mspID", "peerEndpoint", "tlsCACert", "certPath", "keyPath"} {
+		if required[field] == "" {
+			return fmt.Errorf("hồ sơ %q thiếu %s", p.Name, field)
+		}
+	}
+	return nil
+}
+
+// readPEM đọc file PEM; nếu path là thư mục (signcerts, keystore) thì lấy file đầu tiên
+func readPEM(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				return os.ReadFile(filepath.Join(path, entry.Name()))
+			}
+		}
+		return nil, fmt.Errorf("thư mục %s không có file", path)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,189 @@
+// my-ecommerce-client/ledger/block.go
+
+// Package ledger đọc block của Fabric thành các giao dịch với sự kiện chaincode và write-set,
+// dùng chung cho ecom-events (webhook) và ecom-indexer (read model).
+package ledger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go-apiv2/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+// Transaction là một giao dịch endorser trong block
+type Transaction struct {
+	BlockNumber    uint64
+	Position       int // Vị trí trong block
+	TxID           string
+	Timestamp      time.Time
+	ValidationCode peer.TxValidationCode
+	Actions        []*ChaincodeAction
+}
+
+// Valid cho biết giao dịch đã được commit (write-set có hiệu lực)
+func (tx *Transaction) Valid() bool {
+	return tx.ValidationCode == peer.TxValidationCode_VALID
+}
+
+// Action trả về hành động của chaincode trong giao dịch (nil nếu không có)
+func (tx *Transaction) Action(chaincode string) *ChaincodeAction {
+	for _, action := range tx.Actions {
+		if action.Chaincode == chaincode {
+			return action
+		}
+	}
+	return nil
+}
+
+// ChaincodeAction là kết quả endorse của một chaincode: sự kiện và các khóa được ghi
+type ChaincodeAction struct {
+	Chaincode string
+	Event     *peer.ChaincodeEvent // nil nếu chaincode không gọi SetEvent
+	Writes    []*Write             // Chỉ các khóa của chính chaincode (không gồm private data)
+}
+
+// Write là một khóa được ghi hoặc xóa
+type Write struct {
+	Key      string
+	Value    []byte
+	IsDelete bool
+}
+
+// Block là các giao dịch endorser của một block, theo thứ tự
+type Block struct {
+	Number       uint64
+	Transactions []*Transaction
+	Skipped      []error // Giao dịch không đọc được (đã bỏ qua)
+}
+
+// Position trả về vị trí của giao dịch txID trong block
+func (b *Block) Position(txID string) (int, bool) {
+	for _, tx := range b.Transactions {
+		if tx.TxID == txID {
+			return tx.Position, true
+		}
+	}
+	return 0, false
+}
+
+// ParseBlock đọc mọi giao dịch endorser của block (kể cả giao dịch không hợp lệ, xem Valid)
+func ParseBlock(block *common.Block) *Block {
+	result := &Block{Number: block.GetHeader().GetNumber()}
+	var validation []byte
+	if metadata := block.GetMetadata().GetMetadata(); len(metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		validation = metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+
+	for i, data := range block.GetData().GetData() {
+		tx, err := parseTransaction(data)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Errorf("block %d, giao dịch %d: %w", result.Number, i, err))
+			continue
+		}
+		if tx == nil {
+			continue
+		}
+		tx.BlockNumber, tx.Position = result.Number, i
+		if i < len(validation) {
+			tx.ValidationCode = peer.TxValidationCode(validation[i])
+		}
+		result.Transactions = append(result.Transactions, tx)
+	}
+	return result
+}
+
+// parseTransaction trả về nil cho giao dịch không phải endorser (cấu hình kênh...)
+func parseTransaction(data []byte) (*Transaction, error) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return nil, err
+	}
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.GetPayload(), payload); err != nil {
+		return nil, err
+	}
+	header := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.GetHeader().GetChannelHeader(), header); err != nil {
+		return nil, err
+	}
+	if common.HeaderType(header.GetType()) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil
+	}
+
+	tx := &Transaction{TxID: header.GetTxId(), Timestamp: header.GetTimestamp().AsTime()}
+	transaction := &peer.Transaction{}
+	if err := proto.Unmarshal(payload.GetData(), transaction); err != nil {
+		return nil, fmt.Errorf("%s: %w", tx.TxID, err)
+	}
+	for _, transactionAction := range transaction.GetActions() {
+		action, err := parseAction(transactionAction)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tx.TxID, err)
+		}
+		tx.Actions = append(tx.Actions, action)
+	}
+	return tx, nil
+}
+
+func parseAction(transactionAction *peer.TransactionAction) (*ChaincodeAction, error) {
+	actionPayload := &peer.ChaincodeActionPayload{}
+	if err := proto.Unmarshal(transactionAction.GetPayload(), actionPayload); err != nil {
+		return nil, err
+	}
+	responsePayload := &peer.ProposalResponsePayload{}
+	if err := proto.Unmarshal(actionPayload.GetAction().GetProposalResponsePayload(), responsePayload); err != nil {
+		return nil, err
+	}
+	chaincodeAction := &peer.ChaincodeAction{}
+	if err := proto.Unmarshal(responsePayload.GetExtension(), chaincodeAction); err != nil {
+		return nil, err
+	}
+
+	action := &ChaincodeAction{Chaincode: chaincodeAction.GetChaincodeId().GetName()}
+	if events := chaincodeAction.GetEvents(); len(events) > 0 {
+		action.Event = &peer.ChaincodeEvent{}
+		if err := proto.Unmarshal(events, action.Event); err != nil {
+			return nil, err
+		}
+	}
+
+	txReadWriteSet := &rwset.TxReadWriteSet{}
+	if err := proto.Unmarshal(chaincodeAction.GetResults(), txReadWriteSet); err != nil {
+		return nil, err
+	}
+	for _, namespace := range txReadWriteSet.GetNsRwset() {
+		if namespace.GetNamespace() != action.Chaincode {
+			continue
+		}
+		kvReadWriteSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(namespace.GetRwset(), kvReadWriteSet); err != nil {
+			return nil, err
+		}
+		for _, write := range kvReadWriteSet.GetWrites() {
+			action.Writes = append(action.Writes, &Write{Key: write.GetKey(), Value: write.GetValue(), IsDelete: write.GetIsDelete()})
+		}
+	}
+	return action, nil
+}
+
+// compositeKeyNamespace là ký tự mở đầu của khóa composite (shim.CreateCompositeKey)
+const compositeKeyNamespace = "\x00"
+
+// SplitCompositeKey tách khóa composite thành loại và các thuộc tính; ok = false với khóa thường
+func SplitCompositeKey(key string) (objectType string, attributes []string, ok bool) {
+	if !strings.HasPrefix(key, compositeKeyNamespace) {
+		return "", nil, false
+	}
+	parts := strings.Split(strings.TrimPrefix(key, compositeKeyNamespace), "\x00")
+	if len(parts) < 2 {
+		return "", nil, false
+	}
+	// Khóa kết thúc bằng \x00 nên phần tử cuối rỗng
+	return parts[0], parts[1 : len(parts)-1], true
+}
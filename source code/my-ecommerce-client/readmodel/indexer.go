@@ -0,0 +1,103 @@
+// my-ecommerce-client/readmodel/indexer.go
+
+package readmodel
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"ecommerce.com/client/ledger"
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+// Indexer đọc các block đã commit từ checkpoint của Store và áp dụng lần lượt
+type Indexer struct {
+	Network    *client.Network
+	Store      *Store
+	Stream     Stream
+	Logger     *log.Logger
+	RetryDelay time.Duration // Chờ trước khi kết nối lại, nhân đôi tới tối đa 1 phút; mặc định 2s
+}
+
+// Run đồng bộ tới khi ctx bị hủy (trả về nil). Luồng block hỏng hay lỗi cơ sở dữ liệu được
+// thử lại từ checkpoint; checkpoint bị indexer khác cập nhật hoặc đổi định danh thì dừng.
+func (ix *Indexer) Run(ctx context.Context) error {
+	retryDelay := ix.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = 2 * time.Second
+	}
+
+	failures := 0
+	for {
+		next, found, err := ix.Store.Checkpoint(ctx, ix.Stream)
+		if err == nil && !found {
+			return errors.New("chưa khởi tạo checkpoint (Store.InitCheckpoint)")
+		}
+		if err == nil {
+			ix.logf("Đồng bộ %s từ block %d cho %s", ix.Stream.key(), next, ix.Stream.Viewer)
+			var progressed bool
+			progressed, err = ix.follow(ctx, next)
+			if progressed {
+				failures = 0
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, ErrCheckpointConflict) {
+			return err
+		}
+		var viewerErr *viewerMismatchError
+		if errors.As(err, &viewerErr) {
+			return err
+		}
+
+		failures++
+		delay := retryDelay << min(failures-1, 5)
+		if delay > time.Minute {
+			delay = time.Minute
+		}
+		ix.logf("Ngừng đồng bộ: %v; thử lại sau %s", err, delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// follow áp dụng các block từ next tới khi luồng bị đóng hoặc gặp lỗi
+func (ix *Indexer) follow(ctx context.Context, next uint64) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	blocks, err := ix.Network.BlockEvents(ctx, client.WithStartBlock(next))
+	if err != nil {
+		return false, err
+	}
+	progressed := false
+	for block := range blocks {
+		parsed := ledger.ParseBlock(block)
+		for _, err := range parsed.Skipped {
+			ix.logf("Bỏ qua: %v", err)
+		}
+		stats, applied, err := ix.Store.ApplyBlock(ctx, ix.Stream, parsed)
+		if err != nil {
+			return progressed, err
+		}
+		progressed = true
+		if applied && stats.Changed() {
+			ix.logf("Block %d: %d giao dịch, %d đơn, %d đối soát, %d trả hàng, %d đơn không được xem",
+				parsed.Number, stats.Transactions, stats.Orders, stats.Settlements, stats.ReturnCases, stats.Hidden)
+		}
+	}
+	return progressed, errors.New("luồng block bị đóng")
+}
+
+func (ix *Indexer) logf(format string, args ...interface{}) {
+	if ix.Logger != nil {
+		ix.Logger.Printf(format, args...)
+	}
+}
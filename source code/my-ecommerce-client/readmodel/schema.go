@@ -0,0 +1,125 @@
+// my-ecommerce-client/readmodel/schema.go
+
+package readmodel
+
+import (
+	"strconv"
+	"strings"
+)
+
+// dialect là khác biệt giữa PostgreSQL và SQLite mà read model cần biết
+type dialect struct {
+	name      string
+	timestamp string // Kiểu cột thời gian
+	numbered  bool   // Tham số $1, $2... thay cho ?
+}
+
+var (
+	postgres = dialect{name: "postgres", timestamp: "TIMESTAMPTZ", numbered: true}
+	sqlite   = dialect{name: "sqlite", timestamp: "TIMESTAMP"}
+)
+
+// rebind đổi tham số ? sang dạng của dialect (câu lệnh trong package không chứa ? trong chuỗi)
+func (d dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// schema tạo các bảng nếu chưa có. Số tiền là số nguyên (đơn vị nhỏ nhất) như trên sổ cái.
+func (d dialect) schema() []string {
+	ts := d.timestamp
+	return []string{
+		`CREATE TABLE IF NOT EXISTS orders (
+			order_id TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			payment_method TEXT NOT NULL,
+			cod_status TEXT NOT NULL,
+			seller_id TEXT NOT NULL,
+			shipper_id TEXT NOT NULL,
+			seller_company_id TEXT NOT NULL,
+			shipper_company_id TEXT NOT NULL,
+			created_at ` + ts + ` NOT NULL,
+			updated_at ` + ts + ` NOT NULL,
+			delivered_at ` + ts + `,
+			total_amount BIGINT NOT NULL,
+			refundable_amount BIGINT NOT NULL,
+			payout_amount BIGINT NOT NULL,
+			platform_fee BIGINT NOT NULL,
+			refunded_amount BIGINT NOT NULL,
+			seller_sensitive_data TEXT NOT NULL,
+			shipper_sensitive_data TEXT NOT NULL,
+			schema_version INTEGER NOT NULL,
+			last_tx_id TEXT NOT NULL,
+			last_block BIGINT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS orders_status_idx ON orders (status, updated_at)`,
+		`CREATE INDEX IF NOT EXISTS orders_seller_idx ON orders (seller_company_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS orders_shipper_idx ON orders (shipper_company_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS order_lines (
+			order_id TEXT NOT NULL,
+			line_id TEXT NOT NULL,
+			sku TEXT NOT NULL,
+			quantity INTEGER NOT NULL,
+			unit_price BIGINT NOT NULL,
+			returned_quantity INTEGER NOT NULL,
+			PRIMARY KEY (order_id, line_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS order_lines_sku_idx ON order_lines (sku)`,
+		`CREATE TABLE IF NOT EXISTS order_history (
+			order_id TEXT NOT NULL,
+			seq INTEGER NOT NULL,
+			tx_id TEXT NOT NULL,
+			happened_at ` + ts + ` NOT NULL,
+			action TEXT NOT NULL,
+			actor_org TEXT NOT NULL,
+			PRIMARY KEY (order_id, seq)
+		)`,
+		`CREATE INDEX IF NOT EXISTS order_history_action_idx ON order_history (action, happened_at)`,
+		`CREATE TABLE IF NOT EXISTS settlements (
+			order_id TEXT PRIMARY KEY,
+			tx_id TEXT NOT NULL,
+			block_number BIGINT NOT NULL,
+			settled_at ` + ts + ` NOT NULL,
+			payment_method TEXT NOT NULL,
+			seller_id TEXT NOT NULL,
+			seller_company_id TEXT NOT NULL,
+			total_amount BIGINT NOT NULL,
+			refunded_amount BIGINT NOT NULL,
+			platform_fee BIGINT NOT NULL,
+			payout_amount BIGINT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS settlements_seller_idx ON settlements (seller_company_id, settled_at)`,
+		`CREATE TABLE IF NOT EXISTS return_cases (
+			order_id TEXT NOT NULL,
+			return_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			full_return BOOLEAN NOT NULL,
+			refund_amount BIGINT NOT NULL,
+			accepted BOOLEAN NOT NULL,
+			created_at ` + ts + ` NOT NULL,
+			updated_at ` + ts + ` NOT NULL,
+			last_tx_id TEXT NOT NULL,
+			PRIMARY KEY (order_id, return_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS indexer_checkpoint (
+			stream TEXT PRIMARY KEY,
+			next_block BIGINT NOT NULL,
+			viewer_msp_id TEXT NOT NULL,
+			viewer_role TEXT NOT NULL,
+			viewer_company_id TEXT NOT NULL,
+			updated_at ` + ts + ` NOT NULL
+		)`,
+	}
+}
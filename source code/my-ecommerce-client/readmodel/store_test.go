@@ -0,0 +1,284 @@
+// my-ecommerce-client/readmodel/store_test.go
+
+package readmodel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ecommerce.com/client/contract"
+	"ecommerce.com/client/ledger"
+)
+
+const testChaincode = "ecommerce"
+
+var (
+	platformViewer = Viewer{MSPID: "ECommercePlatformOrgMSP", Role: RolePlatform}
+	sellerViewer   = Viewer{MSPID: "SellerOrgMSP", Role: RoleSeller, CompanyID: "Shop_ABC"}
+	testTime       = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+)
+
+func openStore(t *testing.T, dsn string, stream Stream, initialize bool) *Store {
+	t.Helper()
+	store, err := Open(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if initialize {
+		if err := store.InitCheckpoint(context.Background(), stream, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func testStream(viewer Viewer) Stream {
+	return Stream{Channel: "mychannel", Chaincode: testChaincode, Viewer: viewer}
+}
+
+// testOrder tạo đơn của Shop_ABC; giao dịch txID là dòng lịch sử cuối
+func testOrder(orderID string, status contract.Status, txID string, action string) *contract.Order {
+	return &contract.Order{
+		DocType: "Order", SchemaVersion: 2, OrderID: orderID, Status: status, PaymentMethod: contract.PaymentPrepaid,
+		SellerID: "SellerOrgMSP", ShipperID: "ShipperOrgMSP", SellerCompanyID: "Shop_ABC", ShipperCompanyID: "GHN",
+		CreatedAt: testTime, UpdatedAt: testTime, TotalAmount: 300000,
+		Lines:   []contract.OrderLine{{LineID: "L1", SKU: "AO", Quantity: 3, UnitPrice: 100000}},
+		History: []contract.HistoryEntry{{TxID: txID, Timestamp: testTime, Action: action, ActorOrg: "SellerOrgMSP"}},
+	}
+}
+
+func orderWrite(t *testing.T, order *contract.Order) *ledger.Write {
+	t.Helper()
+	value, err := json.Marshal(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ledger.Write{Key: order.OrderID, Value: value}
+}
+
+func returnCaseWrite(t *testing.T, returnCase *contract.ReturnCase) *ledger.Write {
+	t.Helper()
+	value, err := json.Marshal(returnCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "\x00" + returnCaseObjectType + "\x00" + returnCase.OrderID + "\x00" + returnCase.ReturnID + "\x00"
+	return &ledger.Write{Key: key, Value: value}
+}
+
+// testBlock tạo block number, mỗi giao dịch một danh sách khóa được ghi
+func testBlock(number uint64, txID string, writes ...*ledger.Write) *ledger.Block {
+	return &ledger.Block{Number: number, Transactions: []*ledger.Transaction{{
+		BlockNumber: number, TxID: txID, Timestamp: testTime,
+		Actions: []*ledger.ChaincodeAction{{Chaincode: testChaincode, Writes: writes}},
+	}}}
+}
+
+func applyBlock(t *testing.T, store *Store, stream Stream, block *ledger.Block) BlockStats {
+	t.Helper()
+	stats, applied, err := store.ApplyBlock(context.Background(), stream, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !applied {
+		t.Fatalf("block %d không được áp dụng", block.Number)
+	}
+	return stats
+}
+
+func count(t *testing.T, store *Store, table string) int {
+	t.Helper()
+	var n int
+	if err := store.DB().QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func orderStatus(t *testing.T, store *Store, orderID string) string {
+	t.Helper()
+	rows, err := store.Query(context.Background(), `SELECT status FROM orders WHERE order_id = ?`, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return ""
+	}
+	var status string
+	if err := rows.Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
+
+func TestApplyBlockInsertUpdateDelete(t *testing.T) {
+	stream := testStream(platformViewer)
+	store := openStore(t, "sqlite::memory:", stream, true)
+
+	// Tạo đơn
+	order := testOrder("O1", contract.StatusDelivered, "tx0", "CreateOrder")
+	applyBlock(t, store, stream, testBlock(0, "tx0", orderWrite(t, order)))
+	if status := orderStatus(t, store, "O1"); status != "DELIVERED" {
+		t.Fatalf("trạng thái = %q, muốn DELIVERED", status)
+	}
+
+	// Cập nhật đơn cùng yêu cầu trả hàng trong một giao dịch
+	order.Lines[0].ReturnedQuantity = 1
+	order.RefundableAmount = 100000
+	order.History = append(order.History, contract.HistoryEntry{TxID: "tx1", Timestamp: testTime, Action: "RequestReturn"})
+	returnCase := &contract.ReturnCase{DocType: "ReturnCase", OrderID: "O1", ReturnID: "R1", Status: contract.StatusReturnRequested,
+		RefundAmount: 100000, CreatedAt: testTime, UpdatedAt: testTime}
+	stats := applyBlock(t, store, stream, testBlock(1, "tx1", returnCaseWrite(t, returnCase), orderWrite(t, order)))
+	if stats.Orders != 1 || stats.ReturnCases != 1 {
+		t.Errorf("stats = %+v, muốn 1 đơn, 1 yêu cầu trả hàng", stats)
+	}
+	if n := count(t, store, "order_history"); n != 2 {
+		t.Errorf("%d dòng lịch sử, muốn 2", n)
+	}
+
+	// Thanh toán: giao dịch đưa đơn sang SETTLED ghi một dòng đối soát
+	order.Status = contract.StatusSettled
+	order.PayoutAmount = 200000
+	order.History = append(order.History, contract.HistoryEntry{TxID: "tx2", Timestamp: testTime, Action: "PayoutToSeller"})
+	if stats := applyBlock(t, store, stream, testBlock(2, "tx2", orderWrite(t, order))); stats.Settlements != 1 {
+		t.Errorf("stats = %+v, muốn 1 đối soát", stats)
+	}
+	if status := orderStatus(t, store, "O1"); status != "SETTLED" {
+		t.Errorf("trạng thái = %q, muốn SETTLED", status)
+	}
+
+	// Xóa đơn xóa mọi dữ liệu đi kèm
+	applyBlock(t, store, stream, testBlock(3, "tx3", &ledger.Write{Key: "O1", IsDelete: true}))
+	for _, table := range []string{"orders", "order_lines", "order_history", "return_cases", "settlements"} {
+		if n := count(t, store, table); n != 0 {
+			t.Errorf("%s còn %d dòng sau khi xóa đơn", table, n)
+		}
+	}
+}
+
+func TestApplyBlockSkipsOtherKeysButFailsOnUndecodableOrder(t *testing.T) {
+	stream := testStream(platformViewer)
+	store := openStore(t, "sqlite::memory:", stream, true)
+
+	// Khóa thường không phải đơn (docType khác) và khóa composite khác được bỏ qua
+	config := &ledger.Write{Key: "meta", Value: []byte(`{"docType":"Meta"}`)}
+	counter := &ledger.Write{Key: "\x00OrderCount\x00CREATED\x0001\x00", Value: []byte("1")}
+	applyBlock(t, store, stream, testBlock(0, "tx0", config, counter))
+
+	// Đơn không đọc được: block không được áp dụng, checkpoint giữ nguyên
+	bad := &ledger.Write{Key: "O2", Value: []byte(`{"docType":"Order","totalAmount":"nhiều"}`)}
+	if _, applied, err := store.ApplyBlock(context.Background(), stream, testBlock(1, "tx1", bad)); err == nil || applied {
+		t.Fatalf("ApplyBlock = (applied %v, %v), muốn lỗi", applied, err)
+	}
+	if next, _, err := store.Checkpoint(context.Background(), stream); err != nil || next != 1 {
+		t.Errorf("checkpoint = %d (%v), muốn 1", next, err)
+	}
+}
+
+func TestViewerVisibleMatchesCheckOrderAccess(t *testing.T) {
+	order := testOrder("O1", contract.StatusCreated, "tx0", "CreateOrder")
+	noCompany := testOrder("O2", contract.StatusCreated, "tx0", "CreateOrder")
+	noCompany.SellerCompanyID, noCompany.ShipperCompanyID = "", ""
+
+	tests := []struct {
+		name    string
+		viewer  Viewer
+		order   *contract.Order
+		visible bool
+	}{
+		{"Sàn xem mọi đơn", platformViewer, order, true},
+		{"Seller đúng Shop", sellerViewer, order, true},
+		{"Seller khác Shop", Viewer{MSPID: "SellerOrgMSP", Role: RoleSeller, CompanyID: "Shop_XYZ"}, order, false},
+		{"Seller không có companyCode", Viewer{MSPID: "SellerOrgMSP", Role: RoleSeller}, order, false},
+		{"Seller, đơn không gắn Shop", Viewer{MSPID: "SellerOrgMSP", Role: RoleSeller, CompanyID: "Shop_XYZ"}, noCompany, true},
+		{"Seller của tổ chức khác", Viewer{MSPID: "Seller2OrgMSP", Role: RoleSeller, CompanyID: "Shop_ABC"}, order, false},
+		{"Shipper đúng hãng", Viewer{MSPID: "ShipperOrgMSP", Role: RoleShipper, CompanyID: "GHN"}, order, true},
+		{"Shipper khác hãng", Viewer{MSPID: "ShipperOrgMSP", Role: RoleShipper, CompanyID: "GHTK"}, order, false},
+		{"Vai trò Seller nhưng MSP của Shipper", Viewer{MSPID: "ShipperOrgMSP", Role: RoleSeller, CompanyID: "Shop_ABC"}, order, false},
+		{"Không có vai trò", Viewer{MSPID: "SellerOrgMSP", CompanyID: "Shop_ABC"}, order, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.viewer.Visible(tt.order); got != tt.visible {
+				t.Errorf("Visible = %v, muốn %v", got, tt.visible)
+			}
+		})
+	}
+}
+
+func TestApplyBlockStoresOnlyVisibleOrders(t *testing.T) {
+	stream := testStream(sellerViewer)
+	store := openStore(t, "sqlite::memory:", stream, true)
+
+	own := testOrder("O1", contract.StatusCreated, "tx0", "CreateOrder")
+	other := testOrder("O2", contract.StatusCreated, "tx0", "CreateOrder")
+	other.SellerCompanyID = "Shop_XYZ"
+	otherReturn := &contract.ReturnCase{DocType: "ReturnCase", OrderID: "O2", ReturnID: "R1", Status: contract.StatusReturnRequested}
+	stats := applyBlock(t, store, stream, testBlock(0, "tx0", returnCaseWrite(t, otherReturn), orderWrite(t, own), orderWrite(t, other)))
+	if stats.Orders != 1 || stats.Hidden != 1 || stats.ReturnCases != 0 {
+		t.Errorf("stats = %+v, muốn 1 đơn, 1 đơn ẩn, 0 yêu cầu trả hàng", stats)
+	}
+	if orderStatus(t, store, "O2") != "" || count(t, store, "return_cases") != 0 {
+		t.Error("đơn (và yêu cầu trả hàng) của Shop khác được lưu")
+	}
+
+	// Đơn được chuyển sang Shop khác không còn được xem nữa
+	own.SellerCompanyID = "Shop_XYZ"
+	applyBlock(t, store, stream, testBlock(1, "tx1", orderWrite(t, own)))
+	if orderStatus(t, store, "O1") != "" {
+		t.Error("đơn không còn được xem vẫn nằm trong read model")
+	}
+}
+
+func TestApplyBlockResumesFromCheckpoint(t *testing.T) {
+	dsn := "sqlite:" + filepath.Join(t.TempDir(), "readmodel.db")
+	stream := testStream(platformViewer)
+	order := testOrder("O1", contract.StatusCreated, "tx0", "CreateOrder")
+	blocks := []*ledger.Block{testBlock(0, "tx0", orderWrite(t, order))}
+	order.Status = contract.StatusPaid
+	order.History = append(order.History, contract.HistoryEntry{TxID: "tx1", Timestamp: testTime, Action: "ConfirmPayment"})
+	blocks = append(blocks, testBlock(1, "tx1", orderWrite(t, order)))
+	order.Status = contract.StatusShipped
+	order.History = append(order.History, contract.HistoryEntry{TxID: "tx2", Timestamp: testTime, Action: "ShipOrder"})
+	blocks = append(blocks, testBlock(2, "tx2", orderWrite(t, order)))
+
+	store := openStore(t, dsn, stream, true)
+	applyBlock(t, store, stream, blocks[0])
+	applyBlock(t, store, stream, blocks[1])
+	store.Close()
+
+	// Khởi động lại: checkpoint được giữ, block đã áp dụng bị bỏ qua, block nhảy cóc là lỗi
+	store = openStore(t, dsn, stream, false)
+	ctx := context.Background()
+	next, found, err := store.Checkpoint(ctx, stream)
+	if err != nil || !found || next != 2 {
+		t.Fatalf("Checkpoint = (%d, %v, %v), muốn (2, true, nil)", next, found, err)
+	}
+	for _, block := range blocks[:2] {
+		if stats, applied, err := store.ApplyBlock(ctx, stream, block); err != nil || applied || stats.Changed() {
+			t.Errorf("áp dụng lại block %d = (%+v, %v, %v), muốn bỏ qua", block.Number, stats, applied, err)
+		}
+	}
+	if _, _, err := store.ApplyBlock(ctx, stream, testBlock(3, "tx3")); err == nil {
+		t.Error("block 3 được áp dụng khi đang chờ block 2")
+	}
+	applyBlock(t, store, stream, blocks[2])
+	if n := count(t, store, "order_history"); n != 3 {
+		t.Errorf("%d dòng lịch sử, muốn 3 (không ghi trùng)", n)
+	}
+	if status := orderStatus(t, store, "O1"); status != "SHIPPED" {
+		t.Errorf("trạng thái = %q, muốn SHIPPED", status)
+	}
+
+	// Read model của định danh khác không dùng lại được
+	var mismatch *viewerMismatchError
+	if _, _, err := store.Checkpoint(ctx, testStream(sellerViewer)); !errors.As(err, &mismatch) {
+		t.Errorf("Checkpoint với định danh khác: lỗi = %v, muốn viewerMismatchError", err)
+	}
+}
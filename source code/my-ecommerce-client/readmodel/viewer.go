@@ -0,0 +1,60 @@
+// my-ecommerce-client/readmodel/viewer.go
+
+// Package readmodel đồng bộ đơn hàng của chaincode ecommerce vào cơ sở dữ liệu quan hệ
+// (PostgreSQL, hoặc SQLite khi chạy thử) để làm báo cáo: đơn, dòng hàng, lịch sử, đối soát
+// và yêu cầu trả hàng. Indexer đọc lại các block đã commit theo thứ tự; mỗi block được ghi
+// trong một giao dịch SQL cùng checkpoint nên khởi động lại không ghi trùng hay bỏ sót.
+// Chỉ những đơn mà định danh của indexer được xem qua QueryOrder mới được lưu.
+package readmodel
+
+import (
+	"fmt"
+
+	"ecommerce.com/client/contract"
+)
+
+// Role là vai trò của tổ chức trong sổ đăng ký vai trò (my-ecommerce-chaincode/roles.go)
+type Role string
+
+const (
+	RolePlatform Role = "PLATFORM"
+	RoleSeller   Role = "SELLER"
+	RoleShipper  Role = "SHIPPER"
+)
+
+// ParseRole kiểm tra tên vai trò
+func ParseRole(name string) (Role, error) {
+	switch role := Role(name); role {
+	case RolePlatform, RoleSeller, RoleShipper:
+		return role, nil
+	}
+	return "", fmt.Errorf("vai trò không hợp lệ: %q (PLATFORM | SELLER | SHIPPER)", name)
+}
+
+// Viewer là định danh mà read model mô phỏng quyền xem
+type Viewer struct {
+	MSPID     string
+	Role      Role
+	CompanyID string // Thuộc tính companyCode trong chứng chỉ
+}
+
+// Visible áp dụng cùng quy tắc với checkOrderAccess của chaincode: Sàn xem mọi đơn; Seller /
+// Shipper chỉ xem đơn của tổ chức mình và, khi đơn gắn công ty, của đúng công ty mình
+func (v Viewer) Visible(order *contract.Order) bool {
+	switch v.Role {
+	case RolePlatform:
+		return true
+	case RoleSeller:
+		return order.SellerID == v.MSPID && (order.SellerCompanyID == "" || order.SellerCompanyID == v.CompanyID)
+	case RoleShipper:
+		return order.ShipperID == v.MSPID && (order.ShipperCompanyID == "" || order.ShipperCompanyID == v.CompanyID)
+	}
+	return false
+}
+
+func (v Viewer) String() string {
+	if v.CompanyID == "" {
+		return fmt.Sprintf("%s (%s)", v.MSPID, v.Role)
+	}
+	return fmt.Sprintf("%s/%s (%s)", v.MSPID, v.CompanyID, v.Role)
+}
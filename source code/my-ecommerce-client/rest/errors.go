@@ -0,0 +1,60 @@
+// my-ecommerce-client/rest/errors.go
+
+package rest
+
+import (
+	"net/http"
+
+	"ecommerce.com/client/contract"
+)
+
+// statusByCode ánh xạ mã lỗi của hợp đồng sang mã HTTP
+var statusByCode = map[contract.ErrorCode]int{
+	contract.ErrInvalidArgument:     http.StatusBadRequest,
+	contract.ErrIdentity:            http.StatusUnauthorized,
+	contract.ErrAccessDenied:        http.StatusForbidden,
+	contract.ErrRoleRequired:        http.StatusForbidden,
+	contract.ErrCompanyMismatch:     http.StatusForbidden,
+	contract.ErrSelfApproval:        http.StatusForbidden,
+	contract.ErrOrderNotFound:       http.StatusNotFound,
+	contract.ErrReturnNotFound:      http.StatusNotFound,
+	contract.ErrRequestNotFound:     http.StatusNotFound,
+	contract.ErrApprovalNotFound:    http.StatusNotFound,
+	contract.ErrOrderAlreadyExists:  http.StatusConflict,
+	contract.ErrReturnAlreadyExists: http.StatusConflict,
+	contract.ErrRequestIDConflict:   http.StatusConflict,
+	contract.ErrConfigExists:        http.StatusConflict,
+	contract.ErrInvalidState:        http.StatusConflict,
+	contract.ErrNothingToReturn:     http.StatusConflict,
+	contract.ErrApprovalPending:     http.StatusConflict,
+	contract.ErrCommitFailed:        http.StatusConflict,
+	contract.ErrPaymentMethod:       http.StatusUnprocessableEntity,
+	contract.ErrWindowExpired:       http.StatusUnprocessableEntity,
+	contract.ErrWindowNotElapsed:    http.StatusUnprocessableEntity,
+	contract.ErrTrackingOutOfOrder:  http.StatusUnprocessableEntity,
+	contract.ErrApprovalExpired:     http.StatusGone,
+	contract.ErrFrozen:              http.StatusLocked,
+	contract.ErrLedger:              http.StatusInternalServerError,
+	contract.ErrUnavailable:         http.StatusServiceUnavailable,
+	contract.ErrTimeout:             http.StatusGatewayTimeout,
+}
+
+// StatusFor trả về mã HTTP của một mã lỗi (500 nếu không xác định)
+func StatusFor(code contract.ErrorCode) int {
+	if status, ok := statusByCode[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// errorSchema là schema OpenAPI của thân lỗi
+var errorSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"code", "message"},
+	"properties": map[string]interface{}{
+		"code":          map[string]interface{}{"type": "string", "example": string(contract.ErrInvalidState)},
+		"message":       map[string]interface{}{"type": "string"},
+		"details":       map[string]interface{}{"type": "object", "additionalProperties": true},
+		"transactionID": map[string]interface{}{"type": "string"},
+	},
+}
@@ -0,0 +1,176 @@
+// my-ecommerce-client/rest/openapi.go
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ecommerce.com/client/contract"
+)
+
+// BuildOpenAPI sinh đặc tả OpenAPI 3.0 cho Routes. Kiểu tham số, kiểu kết quả và các
+// schema (Order, ReturnCase...) lấy từ metadata của chaincode; tên trường lấy từ danh mục
+// giao dịch vì contractapi đặt tên tham số là param0, param1...
+func BuildOpenAPI(metadata *contract.Metadata) map[string]interface{} {
+	schemas := map[string]interface{}{"Error": errorSchema}
+	for name, raw := range metadata.Components.Schemas {
+		schemas[name] = decodeSchema(raw)
+	}
+
+	paths := map[string]map[string]interface{}{}
+	for _, route := range Routes {
+		tx, _ := contract.Lookup(route.Transaction)
+		txMetadata, ok := metadata.Transaction(route.Transaction)
+		if !ok {
+			continue // Chaincode đang chạy cũ hơn client
+		}
+		if paths[route.Path] == nil {
+			paths[route.Path] = map[string]interface{}{}
+		}
+		paths[route.Path][strings.ToLower(route.Method)] = operation(route, tx, txMetadata)
+	}
+
+	info := metadata.Contracts[contract.SmartContractName].Info
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "E-commerce order contract",
+			"version":     info.Version,
+			"description": "REST API cho chaincode ecommerce qua Fabric Gateway. Lỗi trả về {code, message, details}.",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+func operation(route Route, tx *contract.Transaction, txMetadata *contract.TransactionMetadata) map[string]interface{} {
+	op := map[string]interface{}{
+		"operationId": tx.Name,
+		"summary":     tx.Summary,
+		"tags":        []string{tag(route.Path)},
+	}
+
+	parameters := []interface{}{map[string]interface{}{
+		"name": HeaderLocale, "in": "header", "required": false,
+		"schema": map[string]interface{}{"type": "string", "enum": []string{"vi", "en"}},
+	}}
+	if tx.Kind == contract.Submit {
+		parameters = append(parameters, map[string]interface{}{
+			"name": HeaderIdempotencyKey, "in": "header", "required": false,
+			"description": "Request ID: gửi lại cùng giá trị sẽ nhận kết quả của lần đầu",
+			"schema":      map[string]interface{}{"type": "string"},
+		})
+	}
+
+	bodyProperties := map[string]interface{}{}
+	var bodyRequired []string
+	inQuery := route.Method == http.MethodGet || route.Method == http.MethodDelete
+	for i, param := range tx.Params {
+		schema := paramSchema(param, i, txMetadata)
+		switch {
+		case strings.Contains(route.Path, "{"+param.Name+"}"):
+			parameters = append(parameters, map[string]interface{}{
+				"name": param.Name, "in": "path", "required": true, "schema": schema,
+			})
+		case inQuery:
+			parameters = append(parameters, map[string]interface{}{
+				"name": param.Name, "in": "query", "required": !param.Optional, "schema": schema,
+			})
+		default:
+			bodyProperties[param.Name] = schema
+			if !param.Optional {
+				bodyRequired = append(bodyRequired, param.Name)
+			}
+		}
+	}
+	op["parameters"] = parameters
+	if len(bodyProperties) > 0 {
+		body := map[string]interface{}{"type": "object", "properties": bodyProperties, "additionalProperties": false}
+		if len(bodyRequired) > 0 {
+			body["required"] = bodyRequired
+		}
+		op["requestBody"] = map[string]interface{}{
+			"required": len(bodyRequired) > 0,
+			"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": body}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]interface{}{"description": http.StatusText(status)}
+	if len(txMetadata.Returns) > 0 {
+		success["content"] = map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": decodeSchema(txMetadata.Returns)},
+		}
+	} else if status == http.StatusOK {
+		status = http.StatusNoContent
+		success["description"] = http.StatusText(status)
+	}
+	errorResponse := map[string]interface{}{
+		"description": "Lỗi của hợp đồng; mã HTTP theo mã lỗi (INVALID_ARGUMENT 400, *_NOT_FOUND 404, INVALID_STATE 409, FROZEN 423...)",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"}},
+		},
+	}
+	op["responses"] = map[string]interface{}{
+		strconv.Itoa(status): success,
+		"default":            errorResponse,
+	}
+	return op
+}
+
+// paramSchema lấy schema tham số từ metadata; tham số JSON nhận đối tượng / mảng bất kỳ
+func paramSchema(param contract.Param, index int, txMetadata *contract.TransactionMetadata) interface{} {
+	if param.Type == contract.JSON {
+		return map[string]interface{}{"description": "JSON (đối tượng / mảng, hoặc chuỗi JSON)"}
+	}
+	if index < len(txMetadata.Parameters) {
+		return decodeSchema(txMetadata.Parameters[index].Schema)
+	}
+	return map[string]interface{}{"type": string(param.Type)}
+}
+
+// tag nhóm endpoint theo đoạn đầu của đường dẫn (orders, admin...)
+func tag(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) > 2 && parts[0] == "orders" && (parts[2] == "returns" || parts[2] == "approvals" || parts[2] == "tracking") {
+		return parts[2]
+	}
+	return parts[0]
+}
+
+// decodeSchema chuyển JSON schema của contractapi sang schema OpenAPI: bỏ "$id" và
+// đổi "$ref": "Order" thành "#/components/schemas/Order"
+func decodeSchema(raw json.RawMessage) interface{} {
+	var schema interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return map[string]interface{}{}
+	}
+	return normalizeSchema(schema)
+}
+
+func normalizeSchema(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		delete(v, "$id")
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" && !strings.HasPrefix(ref, "#") {
+				v[key] = "#/components/schemas/" + ref
+				continue
+			}
+			v[key] = normalizeSchema(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalizeSchema(child)
+		}
+		return v
+	}
+	return value
+}
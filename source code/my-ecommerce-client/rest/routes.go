@@ -0,0 +1,66 @@
+// my-ecommerce-client/rest/routes.go
+
+package rest
+
+import "net/http"
+
+// Route ánh xạ một endpoint REST tới một giao dịch. Biến trong đường dẫn ({orderID}...)
+// trùng tên tham số của giao dịch; tham số còn lại lấy từ query string (GET/DELETE)
+// hoặc từ thân JSON (POST/PUT).
+type Route struct {
+	Method      string
+	Path        string
+	Transaction string
+	Status      int // Mã HTTP khi thành công, 0 = 200 (hoặc 204 nếu không có kết quả)
+}
+
+// Routes phủ mọi giao dịch trong contract.Transactions
+var Routes = []Route{
+	// --- Đơn hàng ---
+	{http.MethodPost, "/orders", "CreateOrder", http.StatusCreated},
+	{http.MethodGet, "/orders", "QueryOrdersByString", 0},
+	{http.MethodGet, "/orders/{orderID}", "QueryOrder", 0},
+	{http.MethodGet, "/orders/{orderID}/for-org", "QueryOrderForOrg", 0},
+	{http.MethodPost, "/orders/{orderID}/pay", "ConfirmPayment", 0},
+	{http.MethodPost, "/orders/{orderID}/cancel", "CancelOrder", 0},
+	{http.MethodPost, "/orders/{orderID}/ship", "ShipOrder", 0},
+	{http.MethodPost, "/orders/{orderID}/deliver", "ConfirmDelivery", 0},
+	{http.MethodPost, "/orders/{orderID}/deliver-cod", "ConfirmCODDelivery", 0},
+	{http.MethodPost, "/orders/{orderID}/remit", "RemitCOD", 0},
+	{http.MethodPost, "/orders/{orderID}/payout", "PayoutToSeller", 0},
+	{http.MethodGet, "/orders/{orderID}/endorsers", "GetOrderEndorsers", 0},
+	{http.MethodGet, "/orders/{orderID}/tracking", "GetTracking", 0},
+	{http.MethodPost, "/orders/{orderID}/tracking", "AddTrackingEvent", http.StatusCreated},
+	{http.MethodPost, "/orders/expire-unpaid", "ExpireUnpaidOrders", 0},
+
+	// --- Duyệt hai người ---
+	{http.MethodGet, "/orders/{orderID}/approvals", "GetApprovals", 0},
+	{http.MethodPost, "/orders/{orderID}/approvals/{approvalID}/approve", "ApproveAction", 0},
+
+	// --- Trả hàng ---
+	{http.MethodGet, "/orders/{orderID}/returns", "QueryReturnCases", 0},
+	{http.MethodPost, "/orders/{orderID}/returns", "RequestReturn", http.StatusCreated},
+	{http.MethodGet, "/orders/{orderID}/returns/{returnID}", "QueryReturnCase", 0},
+	{http.MethodPost, "/orders/{orderID}/returns/{returnID}/ship", "ShipReturn", 0},
+	{http.MethodPost, "/orders/{orderID}/returns/{returnID}/receive", "ConfirmReturnReceived", 0},
+	{http.MethodPost, "/orders/{orderID}/returns/{returnID}/inspect", "InspectReturn", 0},
+	{http.MethodPost, "/orders/{orderID}/returns/{returnID}/refund", "RefundReturn", 0},
+
+	// --- Thông tin & idempotency ---
+	{http.MethodGet, "/info", "GetContractInfo", 0},
+	{http.MethodGet, "/requests/{requestID}", "GetClientRequest", 0},
+
+	// --- Quản trị (Sàn) ---
+	{http.MethodPost, "/admin/init", "InitLedger", 0},
+	{http.MethodGet, "/admin/policy", "GetPolicyConfig", 0},
+	{http.MethodPut, "/admin/policy", "SetPolicyConfig", 0},
+	{http.MethodGet, "/admin/roles", "GetRoleRegistry", 0},
+	{http.MethodPut, "/admin/roles", "SetRoleRegistry", 0},
+	{http.MethodGet, "/admin/freezes", "GetFreezes", 0},
+	{http.MethodPost, "/admin/freezes", "Freeze", 0},
+	{http.MethodPost, "/admin/freezes/unfreeze", "Unfreeze", 0},
+	{http.MethodGet, "/admin/freezes/log", "GetFreezeLog", 0},
+	{http.MethodPost, "/admin/migrate", "MigrateOrders", 0},
+	{http.MethodPost, "/admin/import", "ImportOrders", 0},
+	{http.MethodPost, "/admin/counters/rebuild", "RebuildOrderCounters", 0},
+}
@@ -0,0 +1,218 @@
+// my-ecommerce-client/rest/server.go
+
+// Package rest cung cấp REST API cho mọi giao dịch của hợp đồng ecommerce, kèm đặc tả
+// OpenAPI sinh từ metadata của chaincode.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"ecommerce.com/client/contract"
+)
+
+// Header của request
+const (
+	HeaderIdempotencyKey = "Idempotency-Key" // Gửi lại an toàn: chaincode trả kết quả lần đầu
+	HeaderLocale         = "Accept-Language" // "en" -> thông báo lỗi tiếng Anh, mặc định tiếng Việt
+)
+
+// maxBodySize giới hạn thân request (đủ cho cấu hình và đơn nhiều dòng)
+const maxBodySize = 1 << 20
+
+// Server phục vụ REST API trên một contract.Contract (Fabric Gateway hoặc Fake)
+type Server struct {
+	contract contract.Contract
+	logger   *log.Logger
+	mux      *http.ServeMux
+	timeout  time.Duration
+
+	metadataMu sync.Mutex
+	metadata   *contract.Metadata
+}
+
+// NewServer tạo server với các Routes. timeout = 0: dùng thời gian chờ của gateway.
+func NewServer(c contract.Contract, logger *log.Logger, timeout time.Duration) *Server {
+	s := &Server{contract: c, logger: logger, mux: http.NewServeMux(), timeout: timeout}
+	for _, route := range Routes {
+		tx, ok := contract.Lookup(route.Transaction)
+		if !ok {
+			panic("rest: route tới giao dịch không có trong danh mục: " + route.Transaction)
+		}
+		s.mux.HandleFunc(route.Method+" "+route.Path, s.transactionHandler(route, tx))
+	}
+	s.mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return s
+}
+
+// ServeHTTP thực hiện http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) transactionHandler(route Route, tx *contract.Transaction) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		values, err := requestValues(r, tx)
+		if err != nil {
+			s.writeError(w, r, tx, err, started)
+			return
+		}
+		args, err := tx.Args(values)
+		if err != nil {
+			s.writeError(w, r, tx, err, started)
+			return
+		}
+
+		call := contract.Call{Args: args}.
+			WithRequestID(r.Header.Get(HeaderIdempotencyKey)).
+			WithLocale(requestLocale(r))
+		ctx := r.Context()
+		if s.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+		}
+		result, err := contract.Invoke(ctx, s.contract, tx, call)
+		if err != nil {
+			s.writeError(w, r, tx, err, started)
+			return
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if route.Transaction == "CreateOrder" && len(args) > 0 {
+			w.Header().Set("Location", "/orders/"+args[0])
+		}
+		if len(result) == 0 {
+			if status == http.StatusOK {
+				status = http.StatusNoContent
+			}
+			w.WriteHeader(status)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write(result)
+		}
+		s.logf("%s %s -> %s %d (%s)", r.Method, r.URL.Path, tx.Name, status, time.Since(started).Round(time.Millisecond))
+	}
+}
+
+// requestValues gom tham số từ đường dẫn, query string (GET/DELETE) và thân JSON (POST/PUT)
+func requestValues(r *http.Request, tx *contract.Transaction) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if r.Method == http.MethodGet || r.Method == http.MethodDelete {
+		for name, list := range r.URL.Query() {
+			if len(list) > 0 {
+				values[name] = list[len(list)-1]
+			}
+		}
+	} else if body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1)); err != nil {
+		return nil, badRequest("body", err.Error())
+	} else if len(body) > maxBodySize {
+		return nil, badRequest("body", fmt.Sprintf("<= %d bytes", maxBodySize))
+	} else if len(bytes.TrimSpace(body)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil {
+			return nil, badRequest("body", "phải là đối tượng JSON: "+err.Error())
+		}
+	}
+
+	for _, param := range tx.Params {
+		pathValue := r.PathValue(param.Name)
+		if pathValue == "" {
+			continue
+		}
+		if bodyValue, ok := values[param.Name]; ok && fmt.Sprint(bodyValue) != pathValue {
+			return nil, badRequest(param.Name, "khác giá trị trong đường dẫn")
+		}
+		values[param.Name] = pathValue
+	}
+	return values, nil
+}
+
+// requestLocale đọc ngôn ngữ ưu tiên đầu tiên trong Accept-Language
+func requestLocale(r *http.Request) string {
+	header := strings.ToLower(strings.TrimSpace(r.Header.Get(HeaderLocale)))
+	if strings.HasPrefix(header, "en") {
+		return "en"
+	}
+	return ""
+}
+
+func badRequest(field string, constraint string) *contract.Error {
+	return &contract.Error{
+		Code:    contract.ErrInvalidArgument,
+		Message: fmt.Sprintf("tham số '%s' không hợp lệ: %s", field, constraint),
+		Details: map[string]interface{}{"field": field, "constraint": constraint},
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, tx *contract.Transaction, err error, started time.Time) {
+	contractErr, ok := contract.AsError(err)
+	if !ok {
+		contractErr = &contract.Error{Code: contract.ErrUnknown, Message: err.Error()}
+	}
+	status := StatusFor(contractErr.Code)
+	s.logf("%s %s -> %s %d %s: %s (%s)", r.Method, r.URL.Path, tx.Name, status, contractErr.Code, contractErr.Message, time.Since(started).Round(time.Millisecond))
+	writeJSON(w, status, contractErr)
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	metadata, err := s.getMetadata(r.Context())
+	if err != nil {
+		contractErr, ok := contract.AsError(err)
+		if !ok {
+			contractErr = &contract.Error{Code: contract.ErrUnknown, Message: err.Error()}
+		}
+		writeJSON(w, StatusFor(contractErr.Code), contractErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, BuildOpenAPI(metadata))
+}
+
+// getMetadata đọc metadata của chaincode lần đầu được yêu cầu rồi giữ lại
+func (s *Server) getMetadata(ctx context.Context) (*contract.Metadata, error) {
+	s.metadataMu.Lock()
+	defer s.metadataMu.Unlock()
+	if s.metadata != nil {
+		return s.metadata, nil
+	}
+	metadata, err := contract.FetchMetadata(ctx, s.contract)
+	if err != nil {
+		return nil, err
+	}
+	if missing := metadata.Uncatalogued(); len(missing) > 0 {
+		s.logf("cảnh báo: chaincode có giao dịch chưa có route REST: %v", missing)
+	}
+	s.metadata = metadata
+	return metadata, nil
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.logger != nil {
+		s.logger.Printf(format, args...)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+}
@@ -0,0 +1,149 @@
+// my-ecommerce-client/rest/server_test.go
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"ecommerce.com/client/contract"
+)
+
+func serve(t *testing.T, fake *contract.Fake, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	NewServer(fake, nil, 0).ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decodeError(t *testing.T, recorder *httptest.ResponseRecorder) *contract.Error {
+	t.Helper()
+	var contractErr contract.Error
+	if err := json.Unmarshal(recorder.Body.Bytes(), &contractErr); err != nil {
+		t.Fatalf("thân lỗi không phải JSON: %q", recorder.Body.String())
+	}
+	return &contractErr
+}
+
+func TestCreateOrderSubmitsArgsInParamOrder(t *testing.T) {
+	fake := contract.NewFake().Return("CreateOrder", map[string]string{"orderID": "R001"})
+	body := `{"orderID":"R001","paymentMethod":"COD","shipperCompanyID":"GHN",` +
+		`"lines":[{"lineID":"L1","sku":"AO","quantity":2,"unitPrice":150000}]}`
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set(HeaderIdempotencyKey, "create-R001")
+	req.Header.Set(HeaderLocale, "en-US,en;q=0.9")
+
+	recorder := serve(t, fake, req)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("mã HTTP = %d, muốn %d: %s", recorder.Code, http.StatusCreated, recorder.Body)
+	}
+	if location := recorder.Header().Get("Location"); location != "/orders/R001" {
+		t.Errorf("Location = %q, muốn /orders/R001", location)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("số lần gọi = %d, muốn 1", len(calls))
+	}
+	call := calls[0]
+	if call.Kind != contract.Submit || call.Call.Name != "CreateOrder" {
+		t.Errorf("lần gọi = %s %s, muốn submit CreateOrder", call.Kind, call.Call.Name)
+	}
+	wantArgs := []string{"R001", "COD", "GHN", "", "", "",
+		`[{"lineID":"L1","quantity":2,"sku":"AO","unitPrice":150000}]`}
+	if !reflect.DeepEqual(call.Call.Args, wantArgs) {
+		t.Errorf("args = %q, muốn %q", call.Call.Args, wantArgs)
+	}
+	if got := string(call.Call.Transient[contract.TransientRequestID]); got != "create-R001" {
+		t.Errorf("request ID = %q, muốn create-R001", got)
+	}
+	if got := string(call.Call.Transient[contract.TransientLocale]); got != "en" {
+		t.Errorf("locale = %q, muốn en", got)
+	}
+}
+
+func TestQueryOrderIsEvaluatedFromPath(t *testing.T) {
+	fake := contract.NewFake().Return("QueryOrder", map[string]string{"orderID": "R002", "status": "CREATED"})
+
+	recorder := serve(t, fake, httptest.NewRequest(http.MethodGet, "/orders/R002", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("mã HTTP = %d, muốn %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+	}
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].Kind != contract.Evaluate || !reflect.DeepEqual(calls[0].Call.Args, []string{"R002"}) {
+		t.Errorf("lần gọi = %+v, muốn evaluate QueryOrder [R002]", calls)
+	}
+	if len(calls) == 1 && calls[0].Call.Transient != nil {
+		t.Errorf("transient = %v, muốn rỗng khi không có header", calls[0].Call.Transient)
+	}
+}
+
+func TestEmptyResultIsNoContent(t *testing.T) {
+	fake := contract.NewFake().On("ConfirmPayment", func(contract.Call) ([]byte, error) {
+		return nil, nil
+	})
+
+	recorder := serve(t, fake, httptest.NewRequest(http.MethodPost, "/orders/R003/pay", nil))
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("mã HTTP = %d, muốn %d", recorder.Code, http.StatusNoContent)
+	}
+}
+
+func TestContractErrorsMapToHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code   contract.ErrorCode
+		status int
+	}{
+		{contract.ErrOrderNotFound, http.StatusNotFound},
+		{contract.ErrApprovalPending, http.StatusConflict},
+		{contract.ErrSelfApproval, http.StatusForbidden},
+		{contract.ErrFrozen, http.StatusLocked},
+		{contract.ErrUnknown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			fake := contract.NewFake().Fail("PayoutToSeller", tt.code, "lỗi thử")
+
+			recorder := serve(t, fake, httptest.NewRequest(http.MethodPost, "/orders/R004/payout", nil))
+			if recorder.Code != tt.status {
+				t.Errorf("mã HTTP = %d, muốn %d", recorder.Code, tt.status)
+			}
+			if contractErr := decodeError(t, recorder); contractErr.Code != tt.code || contractErr.Message != "lỗi thử" {
+				t.Errorf("lỗi = %+v, muốn %s", contractErr, tt.code)
+			}
+		})
+	}
+}
+
+func TestInvalidRequestIsRejectedBeforeGateway(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"orderID khác đường dẫn", `{"orderID":"OTHER"}`, "orderID"},
+		{"tham số lạ", `{"amount":1}`, "amount"},
+		{"thân không phải JSON", `[1,2]`, "body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := contract.NewFake()
+
+			recorder := serve(t, fake, httptest.NewRequest(http.MethodPost, "/orders/R005/payout", strings.NewReader(tt.body)))
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("mã HTTP = %d, muốn %d", recorder.Code, http.StatusBadRequest)
+			}
+			contractErr := decodeError(t, recorder)
+			if contractErr.Code != contract.ErrInvalidArgument || contractErr.Details["field"] != tt.field {
+				t.Errorf("lỗi = %+v, muốn %s cho %s", contractErr, contract.ErrInvalidArgument, tt.field)
+			}
+			if calls := fake.Calls(); len(calls) != 0 {
+				t.Errorf("gateway bị gọi %d lần, muốn 0", len(calls))
+			}
+		})
+	}
+}
@@ -35,9 +35,11 @@ function createOrg3 {
 	fabric-ca-client register --caname ca-org3 --id.name peer0 --id.secret peer0pw --id.type peer --tls.certfiles "${PWD}/fabric-ca/org3/tls-cert.pem"
   { set +x; } 2>/dev/null
 
-  infoln "Registering user"
+  # Chaincode phân quyền theo attribute "role" trong chứng chỉ (admin | finance | support | warehouse)
+  # Org admin (OU=admin) được coi là admin nên không cần attribute.
+  infoln "Registering user (role=${ORG3_USER_ROLE:-warehouse})"
   set -x
-  fabric-ca-client register --caname ca-org3 --id.name user1 --id.secret user1pw --id.type client --tls.certfiles "${PWD}/fabric-ca/org3/tls-cert.pem"
+  fabric-ca-client register --caname ca-org3 --id.name user1 --id.secret user1pw --id.type client --id.attrs "role=${ORG3_USER_ROLE:-warehouse}:ecert" --tls.certfiles "${PWD}/fabric-ca/org3/tls-cert.pem"
   { set +x; } 2>/dev/null
 
   infoln "Registering the org admin"